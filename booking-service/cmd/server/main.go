@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"quicket/booking-service/pkg/di"
+	"quicket/booking-service/pkg/lifecycle"
 	"quicket/booking-service/router"
 	"runtime/debug"
 )
@@ -35,22 +37,34 @@ func main() {
         log.Fatalf("Failed to initialize app: %v", err)
     }
 
-    go func ()  {
-        if err := app.EventConsumer.Start(context.Background()); err != nil {
-            log.Fatalf("Failed to start event consumer: %v", err)
-        }
-    }()
-
-    go func ()  {
-        if err := app.UserConsumer.Start(context.Background()); err != nil {
-            log.Fatalf("Failed to start event consumer: %v", err)
-        }
-    }()
+    lc := lifecycle.New(app.Config.Server.ShutdownTimeout, app.Logger)
+
+    sqlDB, err := app.DB.DB()
+    if err != nil {
+        log.Fatalf("Failed to get database pool: %v", err)
+    }
+    lc.OnClose("mysql", sqlDB.Close)
+    lc.OnClose("redis", app.Redis.Close)
+    lc.OnClose("rabbitmq connection", app.RabbitMQ.Close)
+    lc.OnClose("rabbitmq consumer channel", app.Consumer.Close)
+    lc.OnClose("rabbitmq publisher", app.Publisher.Close)
+
+    lc.Go("event consumer", app.EventConsumer.Start)
+    lc.Go("user consumer", app.UserConsumer.Start)
+    lc.Go("outbox relay", app.Relay.Run)
+    lc.Go("booking expirer", func(ctx context.Context) error {
+        app.Expirer.Run(ctx)
+        return nil
+    })
     
     r := router.SetupRouter(app)
     
-    addr := fmt.Sprintf(":%s", app.Config.Server.Port)
-	if err := r.Run(addr); err != nil {
-        log.Fatalf("Server failed :%v", err)
+    lc.Serve(&http.Server{
+        Addr: fmt.Sprintf(":%s", app.Config.Server.Port),
+        Handler: r,
+    })
+
+    if err := lc.Wait(); err != nil {
+        log.Fatalf("Server stopped with errors: %v", err)
     }
 }
\ No newline at end of file
@@ -1,21 +1,71 @@
 package booking
 
-import "time"
+import (
+	"quicket/booking-service/pkg/money"
+	"time"
+)
 
 type CreateBookingRequest struct {
 	EventID 	string 	`json:"event_id" binding:"required"`
 	Seats 		uint 	`json:"seats" binding:"required,gt=0"`
+	TicketTypeID string `json:"ticket_type_id" binding:"omitempty,max=36"`
 }
 
 type BookingDTO struct {
 	PublicID  string    `json:"id"`
 	EventPublicID   string    `json:"event_id"`
 	UserID    string    `json:"user_id"`
+	TicketTypeID string `json:"ticket_type_id,omitempty"`
 	Seats     uint      `json:"seats"`
+	TotalPrice money.Money `json:"total_price"`
 	Status    string    `json:"status"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
+type CancelBookingDTO struct {
+	PublicID string `json:"id"`
+	Seats    uint   `json:"seats"`
+	Status   string `json:"status"`
+}
+
+type ListBookingsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending success failed expired cancelled"`
+	When   string `form:"when" binding:"omitempty,oneof=upcoming past"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type BookingEventDTO struct {
+	PublicID  string    `json:"id"`
+	Title     string    `json:"title"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+type BookingSummaryDTO struct {
+	PublicID   string          `json:"id"`
+	Seats      uint            `json:"seats"`
+	TotalPrice money.Money     `json:"total_price"`
+	Status     string          `json:"status"`
+	ExpiredAt  time.Time       `json:"expired_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Event      BookingEventDTO `json:"event"`
+}
+
+// BookingDetailDTO is a booking with the status of its payment. Payments
+// are recorded by the payment service, so PaymentStatus is derived from the
+// booking status: success and failed bookings were settled by a payment of
+// that status, and the others have none yet.
+type BookingDetailDTO struct {
+	BookingSummaryDTO `json:",inline"`
+	PaymentStatus     *string `json:"payment_status"`
+}
+
+type BookingListDTO struct {
+	Bookings   []BookingSummaryDTO `json:"bookings"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
 type EventDateTimeAndSeats struct {
 	ID             int
 	AvailableSeats uint64
@@ -30,4 +80,19 @@ type ResponseSuccess struct {
 type CreateBookingSuccessResponse struct {
 	ResponseSuccess `json:",inline"`
 	Data         BookingDTO `json:"booking"`
+}
+
+type CancelBookingSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Data         CancelBookingDTO `json:"booking"`
+}
+
+type ListBookingsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	BookingListDTO  `json:",inline"`
+}
+
+type BookingDetailSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Data         BookingDetailDTO `json:"booking"`
 }
\ No newline at end of file
@@ -8,5 +8,11 @@ var (
 	ErrEventNotFound = errors.New("event not found")
 	ErrSeatsUnavailable = errors.New("no available seats")
 	ErrNotEnoughSeats = errors.New("not enough setas")
+	ErrTicketTypeRequired = errors.New("ticket type is required for this event")
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrExceedsMaxPerOrder = errors.New("seats exceed the ticket type per-order limit")
+	ErrNotBookingOwner = errors.New("booking belongs to another user")
+	ErrBookingNotCancellable = errors.New("booking can not be cancelled in its current status")
+	ErrEventAlreadyStarted = errors.New("event has already started")
 	ErrDB = errors.New("database error")
 )
\ No newline at end of file
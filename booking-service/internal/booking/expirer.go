@@ -0,0 +1,76 @@
+package booking
+
+import (
+	"context"
+	"quicket/booking-service/pkg/config"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Expirer releases the seats of pending bookings whose hold window has
+// passed. Several replicas may run it at the same time; the repository skips
+// rows that are already locked by someone else.
+type Expirer struct {
+	repo RepositoryInterface
+	interval time.Duration
+	batchSize int
+	logger zerolog.Logger
+}
+
+func NewExpirer(repo RepositoryInterface, cfg *config.Config, logger zerolog.Logger) *Expirer {
+	return &Expirer{
+		repo: repo,
+		interval: cfg.Booking.ExpiryInterval,
+		batchSize: cfg.Booking.ExpiryBatchSize,
+		logger: logger,
+	}
+}
+
+// Run expires due bookings every interval until ctx is cancelled.
+func (e *Expirer) Run(ctx context.Context) {
+	e.logger.Info().
+		Dur("interval", e.interval).
+		Int("batch_size", e.batchSize).
+		Msg("booking expirer started")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info().Msg("booking expirer stopped")
+			return
+		case <-ticker.C:
+			if _, err := e.ExpireDue(ctx, time.Now()); err != nil {
+				e.logger.Error().Err(err).Msg("failed to expire pending bookings")
+			}
+		}
+	}
+}
+
+// ExpireDue expires pending bookings in batches until a batch comes back
+// smaller than the batch size, and returns how many were expired.
+func (e *Expirer) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		expired, err := e.repo.ExpirePending(ctx, now, e.batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += len(expired)
+
+		for _, b := range expired {
+			e.logger.Info().
+				Uint("booking_id", b.ID).
+				Uint("event_id", b.EventID).
+				Uint("seats", b.Seats).
+				Msg("booking expired, seats released")
+		}
+
+		if len(expired) < e.batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
\ No newline at end of file
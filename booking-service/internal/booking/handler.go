@@ -26,10 +26,12 @@ func NewHandler(srv ServiceInterface) *Handler {
 // @Accept json
 // @Produce json
 // @Param request body CreateBookingRequest true "Create booking creation data" 
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 201 {object} CreateBookingSuccessResponse
 // @Failure 400 {object} errs.ErrorResponse "Validation Error"
 // @Failure 401 {object} errs.ErrorResponse "Unauthorized"
 // @Failure 409 {object} errs.ErrorResponse "Conflict Error"
+// @Failure 422 {object} errs.ErrorResponse "Idempotency-Key reused with a different request"
 // @Failure 500 {object} errs.ErrorResponse "Internal Server Error"
 // @Router /api/v1/bookings/ [post]
 func (h *Handler) CreateBooking(c *gin.Context) {
@@ -60,6 +62,120 @@ func (h *Handler) CreateBooking(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// Cancel godoc
+// @Summary Cancel booking
+// @Description Cancel a booking owned by the current user and release its seats. Cancelling a paid booking requests a refund of its total price from the payment service.
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Booking public ID"
+// @Success 200 {object} CancelBookingSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not Found"
+// @Failure 409 {object} errs.ErrorResponse "Conflict Error"
+// @Failure 500 {object} errs.ErrorResponse "Internal Server Error"
+// @Router /api/v1/bookings/{id}/cancel [post]
+func (h *Handler) CancelBooking(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingID := c.Param("id")
+
+	booking, err := h.srv.Cancel(ctx, userPublicID, bookingID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := CancelBookingSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Booking cancelled successfully",
+		},
+		Data: *booking,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListBookings godoc
+// @Summary List my bookings
+// @Description List the bookings of the current user, newest first
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Booking status" Enums(pending, success, failed, expired, cancelled)
+// @Param when query string false "Event time" Enums(upcoming, past)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} ListBookingsSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation Error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errs.ErrorResponse "Internal Server Error"
+// @Router /api/v1/bookings [get]
+func (h *Handler) ListBookings(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var query ListBookingsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.srv.ListMine(ctx, userPublicID, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListBookingsSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Bookings retrieved successfully",
+		},
+		BookingListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBooking godoc
+// @Summary Get booking
+// @Description Get a booking of the current user with its event. payment_status is derived from the booking status, as payments are not recorded in this service: success and failed bookings report a payment of that status, other bookings report null.
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Booking public ID"
+// @Success 200 {object} BookingDetailSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not Found"
+// @Failure 500 {object} errs.ErrorResponse "Internal Server Error"
+// @Router /api/v1/bookings/{publicID} [get]
+func (h *Handler) GetBooking(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingID := c.Param("publicID")
+
+	booking, err := h.srv.GetMine(ctx, userPublicID, bookingID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := BookingDetailSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Booking retrieved successfully",
+		},
+		Data: *booking,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // HealthCheck godoc
 // @Summary Health Check
 // @Description Check if the service is healthy
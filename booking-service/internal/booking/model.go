@@ -1,19 +1,30 @@
 package booking
 
 import (
+	"quicket/booking-service/pkg/money"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
+	StatusCancelled = "cancelled"
+)
+
 type Booking struct {
 	gorm.Model
 	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex"`
 	EventID uint `gorm:"column:event_id;not null"`
+	TicketTypeID *uint `gorm:"column:ticket_type_id"`
 	UserID uint `gorm:"column:user_id;not null"`
 	Seats uint `gorm:"column:seats;not null"`
-	TotalPrice float32 `gorm:"column:total_price;not null"`
-	Status string `gorm:"column:status;type:ENUM('success', 'failed', 'pending');default:'pending'"`
+	TotalPrice money.Money `gorm:"column:total_price;not null"`
+	Currency money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Status string `gorm:"column:status;type:ENUM('success', 'failed', 'pending', 'expired', 'cancelled');default:'pending'"`
 	ExpiredAt time.Time `gorm:"column:expired_at;not null"`
 }
 
@@ -8,6 +8,7 @@ var ProviderSet = wire.NewSet(
 		NewRepo,
 		Newsrv,
 		NewHandler,
+		NewExpirer,
 		wire.Bind(new(RepositoryInterface), new(*repo)),
 		wire.Bind(new(ServiceInterface), new(*srv)),
 )
\ No newline at end of file
@@ -2,7 +2,12 @@ package booking
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"quicket/booking-service/internal/mq/producer"
+	"quicket/booking-service/pkg/money"
+	"time"
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
@@ -10,30 +15,105 @@ import (
 )
 
 type RepositoryInterface interface {
-	Create(ctx context.Context, b *Booking) (*Booking, error)
+	Create(ctx context.Context, b *Booking, ticketTypePublicID string) (*Booking, error)
+	ExpirePending(ctx context.Context, now time.Time, limit int) ([]ReleasedBooking, error)
+	Cancel(ctx context.Context, publicID string, userID uint, now time.Time) (*Booking, error)
+	ListByUser(ctx context.Context, filter ListFilter) ([]BookingView, error)
+	FindView(ctx context.Context, publicID string) (*BookingView, error)
 }
 
+// ListFilter narrows the bookings returned by ListByUser. AfterID is the id
+// of the last booking of the previous page; zero starts from the newest.
+type ListFilter struct {
+	UserID uint
+	Status string
+	Upcoming *bool
+	Now time.Time
+	AfterID uint
+	Limit int
+}
+
+// BookingView is a booking joined with the snapshot of the event it is for.
+type BookingView struct {
+	ID uint
+	PublicID string
+	UserID uint
+	Seats uint
+	TotalPrice money.Money
+	Currency money.Currency
+	Status string
+	ExpiredAt time.Time
+	CreatedAt time.Time
+	EventPublicID string
+	EventTitle string
+	EventStartDate time.Time
+	EventEndDate time.Time
+}
+
+// eventsTable and ticketTypesTable are the local copies of events and their
+// ticket types kept in sync from the event service.
+const (
+	eventsTable = "events_snapshot"
+	ticketTypesTable = "ticket_types_snapshot"
+)
+
+const bookingViewColumns = "bookings.id, bookings.public_id, bookings.user_id, bookings.seats, " +
+	"bookings.total_price, bookings.currency, bookings.status, bookings.expired_at, bookings.created_at, " +
+	"ev.public_id AS event_public_id, ev.title AS event_title, " +
+	"ev.start_date AS event_start_date, ev.end_date AS event_end_date"
+
+const bookingViewJoin = "JOIN " + eventsTable + " ev ON ev.id = bookings.event_id"
+
 type eventRow struct {
 	ID uint
 	AvailableSeats uint
 }
 
+type eventStartRow struct {
+	StartDate time.Time
+}
+
+type ticketTypeRow struct {
+	ID uint
+	Price money.Money
+	Currency money.Currency
+	Available uint64
+	MaxPerOrder uint
+}
+
+// ReleasedBooking is a booking whose seats were given back to its event and
+// ticket type.
+type ReleasedBooking struct {
+	ID uint
+	EventID uint
+	TicketTypeID *uint
+	Seats uint
+}
+
 type repo struct{
 	db *gorm.DB
 	logger zerolog.Logger
+	events *producer.EventProducer
+	payments *producer.PaymentProducer
 }
 
-func NewRepo(db *gorm.DB, logger zerolog.Logger) *repo {
+func NewRepo(db *gorm.DB, logger zerolog.Logger, events *producer.EventProducer, payments *producer.PaymentProducer) *repo {
 	return &repo{
 		db: db,
 		logger: logger,
+		events: events,
+		payments: payments,
 	}
 }
 
-func (r *repo) Create(ctx context.Context, b *Booking) (*Booking, error) {
+// Create persists b and takes its seats from the event and, when
+// ticketTypePublicID is set, from that ticket type. The total price is
+// computed here from the ticket type price read under the row lock. The
+// seats taken are queued for the event service in the same transaction.
+func (r *repo) Create(ctx context.Context, b *Booking, ticketTypePublicID string) (*Booking, error) {
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var ev eventRow
-		if err := tx.Table("events").
+		if err := tx.Table(eventsTable).
 			Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 			Select("id, available_seats").
 			Where("id = ?", b.EventID).
@@ -53,6 +133,10 @@ func (r *repo) Create(ctx context.Context, b *Booking) (*Booking, error) {
 			return ErrNotEnoughSeats
 		}
 
+		if err := r.takeTicketTypeSeats(tx, b, ticketTypePublicID); err != nil {
+			return err
+		}
+
 		if err := tx.Create(b).Error; err != nil  {
 			r.logger.Error().Err(err).
 				Uint("event_id", b.EventID).
@@ -61,7 +145,7 @@ func (r *repo) Create(ctx context.Context, b *Booking) (*Booking, error) {
 			return fmt.Errorf("%w: %v", ErrDB, err)
 		}
 
-		if err := tx.Table("events").
+		if err := tx.Table(eventsTable).
 			Where("id = ?", b.EventID).
 			Update("available_seats", gorm.Expr("available_seats - ?", b.Seats)).
 			Error; err != nil {
@@ -72,8 +156,343 @@ func (r *repo) Create(ctx context.Context, b *Booking) (*Booking, error) {
 			return fmt.Errorf("%w: %v", ErrDB, err)
 		}
 
+		if err := r.publishSeatsDelta(tx, b.EventID, b.TicketTypeID, -int64(b.Seats)); err != nil {
+			return err
+		}
+
 		return nil
 	})
 
 	return b, err
+}
+
+// ExpirePending moves up to limit pending bookings whose hold has passed to
+// the expired status and gives their seats back to the events, all in one
+// transaction. Rows locked by another replica or by a payment worker are
+// skipped and picked up by a later run.
+func (r *repo) ExpirePending(ctx context.Context, now time.Time, limit int) ([]ReleasedBooking, error) {
+	var expired []ReleasedBooking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("bookings").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id, event_id, ticket_type_id, seats").
+			Where("status = ? AND expired_at <= ? AND deleted_at IS NULL", StatusPending, now).
+			Order("expired_at").
+			Limit(limit).
+			Find(&expired).Error; err != nil {
+			r.logger.Error().Err(err).Msg("lock/select expired bookings failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if len(expired) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(expired))
+		for _, b := range expired {
+			ids = append(ids, b.ID)
+		}
+
+		if err := tx.Table("bookings").
+			Where("id IN ? AND status = ?", ids, StatusPending).
+			Update("status", StatusExpired).Error; err != nil {
+			r.logger.Error().Err(err).
+				Int("bookings", len(ids)).
+				Msg("mark bookings expired failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		return r.releaseSeats(tx, expired)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+// Cancel moves a pending or successful booking owned by userID to the
+// cancelled status and gives its seats back to the event. A successful
+// booking was paid for, so a refund of its total price is requested from the
+// payment service in the same transaction.
+func (r *repo) Cancel(ctx context.Context, publicID string, userID uint, now time.Time) (*Booking, error) {
+	var b Booking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&b).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBookingNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("booking_public_id", publicID).
+				Msg("lock/select booking failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if b.UserID != userID {
+			return ErrNotBookingOwner
+		}
+
+		if b.Status != StatusPending && b.Status != StatusSuccess {
+			return ErrBookingNotCancellable
+		}
+
+		var ev eventStartRow
+		if err := tx.Table(eventsTable).
+			Select("start_date").
+			Where("id = ?", b.EventID).
+			Take(&ev).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if !now.Before(ev.StartDate) {
+			return ErrEventAlreadyStarted
+		}
+
+		paid := b.Status == StatusSuccess
+		if err := tx.Model(&b).Update("status", StatusCancelled).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", b.ID).
+				Msg("mark booking cancelled failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		released := ReleasedBooking{ID: b.ID, EventID: b.EventID, TicketTypeID: b.TicketTypeID, Seats: b.Seats}
+		if err := r.releaseSeats(tx, []ReleasedBooking{released}); err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Uint("seats", b.Seats).
+				Msg("release seats failed")
+			return err
+		}
+
+		if paid {
+			if err := r.payments.RequestRefund(tx, producer.RefundRequestedMessage{
+				BookingID: b.ID,
+				BookingPublicID: b.PublicID,
+				UserID: b.UserID,
+				Amount: b.TotalPrice.Amount,
+				Currency: string(b.Currency),
+				Reason: "cancelled by user",
+			}); err != nil {
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// ListByUser returns the bookings of filter.UserID, newest first.
+func (r *repo) ListByUser(ctx context.Context, filter ListFilter) ([]BookingView, error) {
+	q := r.db.WithContext(ctx).
+		Table("bookings").
+		Select(bookingViewColumns).
+		Joins(bookingViewJoin).
+		Where("bookings.user_id = ? AND bookings.deleted_at IS NULL", filter.UserID)
+
+	if filter.Status != "" {
+		q = q.Where("bookings.status = ?", filter.Status)
+	}
+	if filter.Upcoming != nil {
+		if *filter.Upcoming {
+			q = q.Where("ev.start_date > ?", filter.Now)
+		} else {
+			q = q.Where("ev.start_date <= ?", filter.Now)
+		}
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("bookings.id < ?", filter.AfterID)
+	}
+
+	var views []BookingView
+	if err := q.Order("bookings.id DESC").Limit(filter.Limit).Find(&views).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("user_id", filter.UserID).
+			Msg("list bookings failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return views, nil
+}
+
+func (r *repo) FindView(ctx context.Context, publicID string) (*BookingView, error) {
+	var view BookingView
+	if err := r.db.WithContext(ctx).
+		Table("bookings").
+		Select(bookingViewColumns).
+		Joins(bookingViewJoin).
+		Where("bookings.public_id = ? AND bookings.deleted_at IS NULL", publicID).
+		Take(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("booking_public_id", publicID).
+			Msg("find booking failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return &view, nil
+}
+
+// takeTicketTypeSeats locks the requested ticket type, checks it can serve
+// the booking and deducts the seats. Without a ticket type the booking is
+// only allowed for events that have no tiers.
+func (r *repo) takeTicketTypeSeats(tx *gorm.DB, b *Booking, ticketTypePublicID string) error {
+	if ticketTypePublicID == "" {
+		var tiers int64
+		if err := tx.Table(ticketTypesTable).Where("event_id = ?", b.EventID).Count(&tiers).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("count ticket types failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if tiers > 0 {
+			return ErrTicketTypeRequired
+		}
+
+		var currency string
+		if err := tx.Table(eventsTable).Select("currency").Where("id = ?", b.EventID).Row().Scan(&currency); err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("select event currency failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		b.Currency = money.Currency(currency)
+		b.TotalPrice = money.New(0, b.Currency)
+		return nil
+	}
+
+	var tt ticketTypeRow
+	if err := tx.Table(ticketTypesTable).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Select("id, price, currency, available, max_per_order").
+		Where("public_id = ? AND event_id = ?", ticketTypePublicID, b.EventID).
+		Take(&tt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketTypeNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("ticket_type_public_id", ticketTypePublicID).
+			Msg("lock/select ticket type failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if b.Seats > tt.MaxPerOrder {
+		return ErrExceedsMaxPerOrder
+	}
+	if tt.Available < uint64(b.Seats) {
+		return ErrNotEnoughSeats
+	}
+
+	if err := tx.Table(ticketTypesTable).
+		Where("id = ?", tt.ID).
+		Update("available", gorm.Expr("available - ?", b.Seats)).
+		Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("ticket_type_id", tt.ID).
+			Uint("seats", b.Seats).
+			Msg("deduct ticket type seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	b.TicketTypeID = &tt.ID
+	b.Currency = tt.Currency
+	total, err := tt.Price.Mul(int64(b.Seats))
+	if err != nil {
+		return fmt.Errorf("booking total price: %w", err)
+	}
+	b.TotalPrice = total
+	return nil
+}
+
+// releaseSeats adds the seats of the given bookings back to their events and
+// ticket types. Events are updated before ticket types, each in id order, so
+// concurrent releases and bookings lock rows in the same order. The seats
+// given back are queued for the event service, one change per booking.
+func (r *repo) releaseSeats(tx *gorm.DB, bookings []ReleasedBooking) error {
+	seatsByEvent := make(map[uint]uint)
+	seatsByTicketType := make(map[uint]uint)
+	for _, b := range bookings {
+		seatsByEvent[b.EventID] += b.Seats
+		if b.TicketTypeID != nil {
+			seatsByTicketType[*b.TicketTypeID] += b.Seats
+		}
+	}
+
+	for _, id := range sortedKeys(seatsByEvent) {
+		if err := tx.Table(eventsTable).
+			Where("id = ?", id).
+			Update("available_seats", gorm.Expr("available_seats + ?", seatsByEvent[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: release seats of event %d: %v", ErrDB, id, err)
+		}
+	}
+
+	for _, id := range sortedKeys(seatsByTicketType) {
+		if err := tx.Table(ticketTypesTable).
+			Where("id = ?", id).
+			Update("available", gorm.Expr("available + ?", seatsByTicketType[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: release seats of ticket type %d: %v", ErrDB, id, err)
+		}
+	}
+
+	for _, b := range bookings {
+		if err := r.publishSeatsDelta(tx, b.EventID, b.TicketTypeID, int64(b.Seats)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishSeatsDelta bumps the seats version of the event snapshot and queues
+// the change under the new version. The bump locks the snapshot row until tx
+// commits, so the versions of an event follow the order its changes commit
+// in and the event service can tell a missing or repeated one.
+func (r *repo) publishSeatsDelta(tx *gorm.DB, eventID uint, ticketTypeID *uint, delta int64) error {
+	if err := tx.Table(eventsTable).
+		Where("id = ?", eventID).
+		Update("seats_version", gorm.Expr("seats_version + 1")).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("bump seats version failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	var version uint
+	if err := tx.Table(eventsTable).Select("seats_version").Where("id = ?", eventID).Row().Scan(&version); err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("select seats version failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if err := r.events.PublishAvailableSeatsUpdate(tx, eventID, ticketTypeID, delta, version); err != nil {
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[uint]uint) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	eventsnapshot "quicket/booking-service/internal/event_snapshot"
 	usersnapshot "quicket/booking-service/internal/user_snapshot"
+	"quicket/booking-service/pkg/config"
 	"quicket/booking-service/pkg/errs"
 	"quicket/booking-service/pkg/util"
 	"time"
@@ -15,30 +16,32 @@ import (
 )
 
 type ServiceInterface interface {
-	FindByID(id uint) error
 	Create(ctx context.Context, req *CreateBookingRequest, userPublicID string) (*BookingDTO, error)
+	Cancel(ctx context.Context, userPublicID, bookingPublicID string) (*CancelBookingDTO, error)
+	ListMine(ctx context.Context, userPublicID string, query *ListBookingsQuery) (*BookingListDTO, error)
+	GetMine(ctx context.Context, userPublicID, bookingPublicID string) (*BookingDetailDTO, error)
 }
 
+const defaultListLimit = 20
+
 type srv struct {
 	repo RepositoryInterface
 	evSrv eventsnapshot.Service
 	usrSrv usersnapshot.Service
+	holdDuration time.Duration
 	logger zerolog.Logger
 }
 
-func Newsrv(repo RepositoryInterface, evSrv eventsnapshot.Service, usrSrv usersnapshot.Service, logger zerolog.Logger) *srv {
+func Newsrv(repo RepositoryInterface, evSrv eventsnapshot.Service, usrSrv usersnapshot.Service, cfg *config.Config, logger zerolog.Logger) *srv {
 	return &srv{
 		repo: repo,
 		evSrv: evSrv,
 		usrSrv: usrSrv,
+		holdDuration: cfg.Booking.HoldDuration,
 		logger: logger,
 	}
 }
 
-func (s *srv) FindByID(id uint) error {
-	return nil
-}
-
 func (s *srv) Create(ctx context.Context, req *CreateBookingRequest, userPublicID string) (*BookingDTO, error) {
 	log := s.logger.With().
 		Str("event_public_id", req.EventID).
@@ -71,11 +74,17 @@ func (s *srv) Create(ctx context.Context, req *CreateBookingRequest, userPublicI
 		return nil, err
 	}
 
-	persisted, err := s.repo.Create(ctx, newB)
+	persisted, err := s.repo.Create(ctx, newB, req.TicketTypeID)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrNotEnoughSeats):
 			return nil, errs.NewConflictError("not enough available seats")
+		case errors.Is(err, ErrTicketTypeRequired):
+			return nil, errs.NewValidationError("ticket_type_id is required for this event")
+		case errors.Is(err, ErrTicketTypeNotFound):
+			return nil, errs.NewErrNotFound("ticket type")
+		case errors.Is(err, ErrExceedsMaxPerOrder):
+			return nil, errs.NewValidationError("seats exceed the per-order limit of this ticket type")
 		case errors.Is(err, ErrEventNotFound):
 			return nil, errs.NewErrNotFound("event")
 		default:
@@ -84,16 +93,163 @@ func (s *srv) Create(ctx context.Context, req *CreateBookingRequest, userPublicI
 	}
 
 	bDTO := s.prepareBookingDTO(persisted, req.EventID, userPublicID)
+	bDTO.TicketTypeID = req.TicketTypeID
 	return bDTO, nil
 }
 
+func (s *srv) Cancel(ctx context.Context, userPublicID, bookingPublicID string) (*CancelBookingDTO, error) {
+	log := s.logger.With().
+		Str("booking_public_id", bookingPublicID).
+		Str("user_public_id", userPublicID).
+		Logger()
+
+	userID, err := s.usrSrv.GetUserSnapshotID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#cancel: %w", err)
+	}
+
+	b, err := s.repo.Cancel(ctx, bookingPublicID, *userID, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBookingNotFound):
+			return nil, errs.NewErrNotFound("booking")
+		case errors.Is(err, ErrNotBookingOwner):
+			return nil, errs.NewForbiddenError("booking belongs to another user")
+		case errors.Is(err, ErrBookingNotCancellable):
+			return nil, errs.NewConflictError("booking can not be cancelled anymore")
+		case errors.Is(err, ErrEventAlreadyStarted):
+			return nil, errs.NewConflictError("can not cancel booking after the event has started")
+		case errors.Is(err, ErrEventNotFound):
+			return nil, errs.NewErrNotFound("event")
+		default:
+			return nil, fmt.Errorf("booking#cancel: persist: %w", err)
+		}
+	}
+
+	log.Info().
+		Uint("event_id", b.EventID).
+		Uint("seats", b.Seats).
+		Msg("Booking cancelled")
+
+	return &CancelBookingDTO{
+		PublicID: b.PublicID,
+		Seats: b.Seats,
+		Status: b.Status,
+	}, nil
+}
+
+func (s *srv) ListMine(ctx context.Context, userPublicID string, query *ListBookingsQuery) (*BookingListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	userID, err := s.usrSrv.GetUserSnapshotID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#list: %w", err)
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	filter := ListFilter{
+		UserID: *userID,
+		Status: query.Status,
+		Now: time.Now(),
+		AfterID: afterID,
+		// One extra row tells whether there is a next page.
+		Limit: limit + 1,
+	}
+	if query.When != "" {
+		upcoming := query.When == "upcoming"
+		filter.Upcoming = &upcoming
+	}
+
+	views, err := s.repo.ListByUser(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("booking#list: %w", err)
+	}
+
+	list := &BookingListDTO{
+		Bookings: make([]BookingSummaryDTO, 0, limit),
+	}
+	if len(views) > limit {
+		views = views[:limit]
+		list.NextCursor = util.EncodeCursor(views[limit-1].ID)
+	}
+	for i := range views {
+		list.Bookings = append(list.Bookings, toBookingSummaryDTO(&views[i]))
+	}
+
+	return list, nil
+}
+
+func (s *srv) GetMine(ctx context.Context, userPublicID, bookingPublicID string) (*BookingDetailDTO, error) {
+	userID, err := s.usrSrv.GetUserSnapshotID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#get: %w", err)
+	}
+
+	view, err := s.repo.FindView(ctx, bookingPublicID)
+	if err != nil {
+		if errors.Is(err, ErrBookingNotFound) {
+			return nil, errs.NewErrNotFound("booking")
+		}
+		return nil, fmt.Errorf("booking#get: %w", err)
+	}
+
+	if view.UserID != *userID {
+		return nil, errs.NewForbiddenError("booking belongs to another user")
+	}
+
+	return &BookingDetailDTO{
+		BookingSummaryDTO: toBookingSummaryDTO(view),
+		PaymentStatus: paymentStatus(view.Status),
+	}, nil
+}
+
+// paymentStatus derives the status of the payment of a booking from the
+// booking status, since payments are not recorded in this service. Only a
+// settled booking has a payment; nil means none is known.
+func paymentStatus(bookingStatus string) *string {
+	var status string
+	switch bookingStatus {
+	case StatusSuccess:
+		status = "success"
+	case StatusFailed:
+		status = "failed"
+	default:
+		return nil
+	}
+	return &status
+}
+
+func toBookingSummaryDTO(v *BookingView) BookingSummaryDTO {
+	return BookingSummaryDTO{
+		PublicID: v.PublicID,
+		Seats: v.Seats,
+		TotalPrice: v.TotalPrice,
+		Status: v.Status,
+		ExpiredAt: v.ExpiredAt,
+		CreatedAt: v.CreatedAt,
+		Event: BookingEventDTO{
+			PublicID: v.EventPublicID,
+			Title: v.EventTitle,
+			StartDate: v.EventStartDate,
+			EndDate: v.EventEndDate,
+		},
+	}
+}
+
 func (s *srv) prepareBooking(ctx context.Context, eventID uint, userID uint, seats uint) (*Booking, error) {
 	publicID, err := util.GeneratePublicID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("booking#create: generate public id: %w", err)
 	}
 
-	expiredAt := time.Now().Add(5 * time.Minute)
+	expiredAt := time.Now().Add(s.holdDuration)
 
 	return &Booking{
 		PublicID: publicID,
@@ -110,6 +266,7 @@ func (s *srv) prepareBookingDTO(booking *Booking, eventPublicID string, userPubl
 		EventPublicID: eventPublicID,
 		UserID: userPublicID,
 		Seats: booking.Seats,
+		TotalPrice: booking.TotalPrice,
 		Status: booking.Status,
 		ExpiredAt: booking.ExpiredAt,
 	}
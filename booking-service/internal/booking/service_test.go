@@ -0,0 +1,267 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"quicket/booking-service/pkg/errs"
+	"quicket/booking-service/pkg/util"
+
+	"github.com/rs/zerolog"
+)
+
+// statusOf is the HTTP status err maps to, or 0 when it is not an AppError.
+func statusOf(err error) int {
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Status
+	}
+	return 0
+}
+
+// bookingRepo stands in for the booking repository. Only what the tests
+// call is implemented.
+type bookingRepo struct {
+	RepositoryInterface
+	// cancelErr is what Cancel returns.
+	cancelErr error
+	cancelled *Booking
+	// views is what ListByUser returns, cut to the filter's limit, and what
+	// FindView looks bookings up in; filter is the last filter it was
+	// called with.
+	views  []BookingView
+	filter ListFilter
+}
+
+func (r *bookingRepo) Cancel(ctx context.Context, publicID string, userID uint, now time.Time) (*Booking, error) {
+	if r.cancelErr != nil {
+		return nil, r.cancelErr
+	}
+	r.cancelled = &Booking{PublicID: publicID, UserID: userID, Seats: 2, Status: StatusCancelled}
+	return r.cancelled, nil
+}
+
+func (r *bookingRepo) ListByUser(ctx context.Context, filter ListFilter) ([]BookingView, error) {
+	r.filter = filter
+	if len(r.views) > filter.Limit {
+		return r.views[:filter.Limit], nil
+	}
+	return r.views, nil
+}
+
+func (r *bookingRepo) FindView(ctx context.Context, publicID string) (*BookingView, error) {
+	for i := range r.views {
+		if r.views[i].PublicID == publicID {
+			return &r.views[i], nil
+		}
+	}
+	return nil, ErrBookingNotFound
+}
+
+// userSnapshots finds users by public id.
+type userSnapshots map[string]uint
+
+func (u userSnapshots) GetUserSnapshotID(ctx context.Context, publicID string) (*uint, error) {
+	id, ok := u[publicID]
+	if !ok {
+		return nil, errs.NewErrNotFound("user")
+	}
+	return &id, nil
+}
+
+func (u userSnapshots) CreateUserSnapshot(ctx context.Context, userID uint, userPublicID string) error {
+	return nil
+}
+
+func (u userSnapshots) DeleteUserSnapshot(ctx context.Context, id uint) error {
+	return nil
+}
+
+var testUsers = userSnapshots{"alice": 1, "bob": 2}
+
+func newTestSrv(repo RepositoryInterface) *srv {
+	return &srv{repo: repo, usrSrv: testUsers, holdDuration: 15 * time.Minute, logger: zerolog.Nop()}
+}
+
+func TestSrv_Cancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       string
+		repoErr    error
+		wantStatus int
+	}{
+		{name: "cancelled", user: "alice"},
+		{name: "unknown booking", user: "alice", repoErr: ErrBookingNotFound, wantStatus: http.StatusNotFound},
+		{name: "booking of another user", user: "bob", repoErr: ErrNotBookingOwner, wantStatus: http.StatusForbidden},
+		{name: "booking already settled", user: "alice", repoErr: ErrBookingNotCancellable, wantStatus: http.StatusConflict},
+		{name: "event already started", user: "alice", repoErr: ErrEventAlreadyStarted, wantStatus: http.StatusConflict},
+		{name: "event gone", user: "alice", repoErr: ErrEventNotFound, wantStatus: http.StatusNotFound},
+		{name: "unknown user", user: "carol", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &bookingRepo{cancelErr: tt.repoErr}
+			s := newTestSrv(repo)
+
+			got, err := s.Cancel(context.Background(), tt.user, "b-1")
+			if tt.wantStatus != 0 {
+				if status := statusOf(err); status != tt.wantStatus {
+					t.Fatalf("Cancel() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Cancel() error = %v", err)
+			}
+			if repo.cancelled.UserID != testUsers[tt.user] {
+				t.Errorf("cancelled as user %d, want %d", repo.cancelled.UserID, testUsers[tt.user])
+			}
+			if got.PublicID != "b-1" || got.Seats != 2 || got.Status != StatusCancelled {
+				t.Errorf("Cancel() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSrv_CancelDatabaseError(t *testing.T) {
+	s := newTestSrv(&bookingRepo{cancelErr: ErrDB})
+
+	_, err := s.Cancel(context.Background(), "alice", "b-1")
+	if !errors.Is(err, ErrDB) || statusOf(err) != 0 {
+		t.Errorf("Cancel() error = %v, want ErrDB", err)
+	}
+}
+
+func TestSrv_ListMine(t *testing.T) {
+	views := []BookingView{
+		{ID: 9, PublicID: "b-9", UserID: 1, EventPublicID: "ev-1"},
+		{ID: 7, PublicID: "b-7", UserID: 1, EventPublicID: "ev-2"},
+		{ID: 4, PublicID: "b-4", UserID: 1, EventPublicID: "ev-1"},
+	}
+	upcoming, past := true, false
+
+	tests := []struct {
+		name         string
+		query        ListBookingsQuery
+		wantIDs      []string
+		wantCursor   string
+		wantAfterID  uint
+		wantLimit    int
+		wantUpcoming *bool
+		wantStatus   int
+	}{
+		{name: "first page", query: ListBookingsQuery{Limit: 2}, wantIDs: []string{"b-9", "b-7"}, wantCursor: util.EncodeCursor(7), wantLimit: 3},
+		{name: "last page has no cursor", query: ListBookingsQuery{Limit: 3}, wantIDs: []string{"b-9", "b-7", "b-4"}, wantLimit: 4},
+		{name: "default limit", wantIDs: []string{"b-9", "b-7", "b-4"}, wantLimit: defaultListLimit + 1},
+		{name: "next page", query: ListBookingsQuery{Limit: 3, Cursor: util.EncodeCursor(10)}, wantIDs: []string{"b-9", "b-7", "b-4"}, wantAfterID: 10, wantLimit: 4},
+		{name: "upcoming", query: ListBookingsQuery{Limit: 3, When: "upcoming"}, wantIDs: []string{"b-9", "b-7", "b-4"}, wantLimit: 4, wantUpcoming: &upcoming},
+		{name: "past", query: ListBookingsQuery{Limit: 3, When: "past"}, wantIDs: []string{"b-9", "b-7", "b-4"}, wantLimit: 4, wantUpcoming: &past},
+		{name: "invalid cursor", query: ListBookingsQuery{Cursor: "!!!"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &bookingRepo{views: views}
+			s := newTestSrv(repo)
+
+			list, err := s.ListMine(context.Background(), "alice", &tt.query)
+			if tt.wantStatus != 0 {
+				if status := statusOf(err); status != tt.wantStatus {
+					t.Fatalf("ListMine() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListMine() error = %v", err)
+			}
+
+			var ids []string
+			for _, b := range list.Bookings {
+				ids = append(ids, b.PublicID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("bookings = %v, want %v", ids, tt.wantIDs)
+			}
+			if list.NextCursor != tt.wantCursor {
+				t.Errorf("next cursor = %q, want %q", list.NextCursor, tt.wantCursor)
+			}
+			if repo.filter.UserID != 1 || repo.filter.AfterID != tt.wantAfterID || repo.filter.Limit != tt.wantLimit {
+				t.Errorf("filter = %+v", repo.filter)
+			}
+			if !reflect.DeepEqual(repo.filter.Upcoming, tt.wantUpcoming) {
+				t.Errorf("filter upcoming = %v, want %v", repo.filter.Upcoming, tt.wantUpcoming)
+			}
+		})
+	}
+}
+
+func TestSrv_GetMine(t *testing.T) {
+	views := []BookingView{
+		{ID: 1, PublicID: "b-1", UserID: 1, Status: StatusSuccess, EventPublicID: "ev-1"},
+		{ID: 2, PublicID: "b-2", UserID: 1, Status: StatusPending, EventPublicID: "ev-1"},
+	}
+
+	tests := []struct {
+		name        string
+		user        string
+		booking     string
+		wantPayment *string
+		wantStatus  int
+	}{
+		{name: "paid booking", user: "alice", booking: "b-1", wantPayment: ptr("success")},
+		{name: "pending booking", user: "alice", booking: "b-2"},
+		{name: "booking of another user", user: "bob", booking: "b-1", wantStatus: http.StatusForbidden},
+		{name: "unknown booking", user: "alice", booking: "b-3", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSrv(&bookingRepo{views: views})
+
+			got, err := s.GetMine(context.Background(), tt.user, tt.booking)
+			if tt.wantStatus != 0 {
+				if status := statusOf(err); status != tt.wantStatus {
+					t.Fatalf("GetMine() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetMine() error = %v", err)
+			}
+			if got.PublicID != tt.booking || got.Event.PublicID != "ev-1" {
+				t.Errorf("GetMine() = %+v", got)
+			}
+			if !reflect.DeepEqual(got.PaymentStatus, tt.wantPayment) {
+				t.Errorf("payment status = %v, want %v", got.PaymentStatus, tt.wantPayment)
+			}
+		})
+	}
+}
+
+func TestPaymentStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   *string
+	}{
+		{status: StatusSuccess, want: ptr("success")},
+		{status: StatusFailed, want: ptr("failed")},
+		{status: StatusPending},
+		{status: StatusExpired},
+		{status: StatusCancelled},
+	}
+
+	for _, tt := range tests {
+		if got := paymentStatus(tt.status); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("paymentStatus(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -1,6 +1,7 @@
 package eventsnapshot
 
 import (
+	"quicket/booking-service/pkg/money"
 	"time"
 )
 
@@ -13,8 +14,32 @@ type EventSnapshot struct {
 	AvailableSeats 	uint64 		`gorm:"column:available_seats"`
 	UpdatedAt 		time.Time
 	Version			uint		`gorm:"column:version"`
+	// SeatsVersion numbers the seat changes made here to the event, see
+	// booking's publishSeatsDelta. It is read only, so saving a snapshot
+	// received from the event service keeps it.
+	SeatsVersion 	uint 		`gorm:"column:seats_version;->"`
+	Currency 		money.Currency `gorm:"column:currency;type:char(3);not null"`
+	TicketTypes 	[]TicketTypeSnapshot `gorm:"foreignKey:EventID"`
 }
 
 func (e *EventSnapshot) TableName() string {
 	return "events_snapshot"
+}
+
+// TicketTypeSnapshot is the local copy of an event's ticket type. Available
+// is decremented here as bookings take seats.
+type TicketTypeSnapshot struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	EventID 		uint 		`gorm:"column:event_id;not null;index"`
+	Name 			string 		`gorm:"column:name;size:100;not null"`
+	Price 			money.Money `gorm:"column:price;not null"`
+	Currency 		money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Available 		uint64 		`gorm:"column:available;not null"`
+	MaxPerOrder 	uint 		`gorm:"column:max_per_order;not null"`
+	UpdatedAt 		time.Time
+}
+
+func (t *TicketTypeSnapshot) TableName() string {
+	return "ticket_types_snapshot"
 }
\ No newline at end of file
@@ -7,13 +7,14 @@ import (
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	Find(ctx context.Context, id uint) (*EventSnapshot, error)
 	Create(ctx context.Context, ev *EventSnapshot) error
 	Update(ctx context.Context, ev *EventSnapshot) error
-	UpdateSeats(ctx context.Context, evID uint, available_seats, version int) error
+	UpdateSeats(ctx context.Context, evID uint, version uint) error
 	Delete(ctx context.Context, id uint) error
 	GetEventDateTimeAndSeats(ctx context.Context, publicID string) (*EventDateTimeAndSeats, error)
 }
@@ -39,29 +40,33 @@ func (r *EvSnapshotRepo) Find(ctx context.Context, id uint) (*EventSnapshot, err
 	return &ev, nil
 }
 
+// Create stores the snapshot. A snapshot that already exists is left as it
+// is, since event messages can be delivered more than once.
 func (r *EvSnapshotRepo) Create(ctx context.Context, ev *EventSnapshot) error {
-	err := r.db.WithContext(ctx).Create(ev).Error
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(ev).Error
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Update saves the event and upserts its ticket types.
 func (r *EvSnapshotRepo) Update(ctx context.Context, ev *EventSnapshot) error {
-	err := r.db.WithContext(ctx).Save(ev).Error
+	err := r.db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Save(ev).Error
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *EvSnapshotRepo) UpdateSeats(ctx context.Context, evID uint, available_seats, version int) error {
-	var ev EventSnapshot
-	err := r.db.First(&ev, evID).Error
-	if err != nil {
-		return fmt.Errorf("failed to select event: %w", err)
-	}
-	err = r.db.Model(&ev).Select("available_seats", "version").Updates(EventSnapshot{AvailableSeats: uint64(available_seats), Version: ev.Version + 1}).Error
+// UpdateSeats moves the snapshot up to version, ignoring versions it has
+// already seen. The seats are left alone: event seat changes echo the
+// bookings made here, which the snapshot counts as they happen, and may
+// trail bookings taken since.
+func (r *EvSnapshotRepo) UpdateSeats(ctx context.Context, evID uint, version uint) error {
+	err := r.db.WithContext(ctx).Model(&EventSnapshot{}).
+		Where("id = ? AND version < ?", evID, version).
+		UpdateColumn("version", version).Error
 	if err != nil {
 		return fmt.Errorf("failed to update an event snapshot: %w", err)
 	}
@@ -9,7 +9,7 @@ import (
 type Service interface {
 	CreateSnapshot(ctx context.Context, ev *EventSnapshot) error
 	UpdateSnapshot(ctx context.Context, ev *EventSnapshot) error
-	UpdateSeatsSnapshot(ctx context.Context, evID uint, available_seats, version int) error
+	UpdateSeatsSnapshot(ctx context.Context, evID uint, version uint) error
 	DeleteSnapshot(ctx context.Context, id uint) error
 	GetEventSnapshotDateTimeAndSeats(ctx context.Context, publicID string) (*EventDateTimeAndSeats, error)
 }
@@ -36,8 +36,8 @@ func (s *srv) UpdateSnapshot(ctx context.Context, ev *EventSnapshot) error {
 	return err
 }
 
-func (s *srv) UpdateSeatsSnapshot(ctx context.Context, evID uint, available_seats, version int) error {
-	err := s.repo.UpdateSeats(ctx, evID, available_seats, version)
+func (s *srv) UpdateSeatsSnapshot(ctx context.Context, evID uint, version uint) error {
+	err := s.repo.UpdateSeats(ctx, evID, version)
 	return err
 }
 
@@ -0,0 +1,8 @@
+package idempotency
+
+import "errors"
+
+var (
+	ErrKeyInProgress = errors.New("idempotency key is in progress")
+	ErrKeyMismatch   = errors.New("idempotency key was used with a different request")
+)
\ No newline at end of file
@@ -0,0 +1,183 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"quicket/booking-service/pkg/config"
+	"quicket/booking-service/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+const testKeyTTL = time.Hour
+
+func setupRouter(client *memoryClient, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Idempotency: &config.IdempotencyConfig{KeyTTL: testKeyTTL}}
+	m := NewMiddleware(&RedisStore{client: client}, cfg, zerolog.Nop())
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.POST("/bookings", func(c *gin.Context) {
+		c.Set("publicID", "user-1")
+	}, m.Handle(), handler)
+	return r
+}
+
+func doRequest(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	r := setupRouter(newMemoryClient(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := doRequest(r, "key-1", `{"seats":2}`)
+	second := doRequest(r, "key-1", `{"seats":2}`)
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Errorf("status = %d, %d, want %d", first.Code, second.Code, http.StatusCreated)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Errorf("replayed body = %s, want %s", second.Body, first.Body)
+	}
+	if got := second.Header().Get(HeaderReplayed); got != "true" {
+		t.Errorf("%s = %q, want \"true\"", HeaderReplayed, got)
+	}
+}
+
+func TestMiddleware_ReclaimsExpiredKey(t *testing.T) {
+	client := newMemoryClient()
+	calls := 0
+	r := setupRouter(client, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	doRequest(r, "key-1", `{"seats":2}`)
+	client.advance(testKeyTTL)
+	w := doRequest(r, "key-1", `{"seats":3}`)
+
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+	if w.Code != http.StatusCreated || w.Header().Get(HeaderReplayed) != "" {
+		t.Errorf("status = %d, replayed = %q, want a fresh %d", w.Code, w.Header().Get(HeaderReplayed), http.StatusCreated)
+	}
+}
+
+func TestMiddleware_ReleasesKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(c *gin.Context)
+		wantCode int
+	}{
+		{
+			name:     "handler error",
+			handler:  func(c *gin.Context) { c.Error(errors.New("boom")) },
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "server error response",
+			handler:  func(c *gin.Context) { c.JSON(http.StatusServiceUnavailable, gin.H{}) },
+			wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "nothing written",
+			handler:  func(c *gin.Context) {},
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := setupRouter(newMemoryClient(), func(c *gin.Context) {
+				calls++
+				tt.handler(c)
+			})
+
+			first := doRequest(r, "key-1", `{"seats":2}`)
+			second := doRequest(r, "key-1", `{"seats":2}`)
+
+			if calls != 2 {
+				t.Errorf("handler ran %d times, want 2", calls)
+			}
+			if first.Code != tt.wantCode || second.Code != tt.wantCode {
+				t.Errorf("status = %d, %d, want %d", first.Code, second.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestMiddleware_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		body     string
+		wantCode int
+	}{
+		{name: "different body", key: "key-1", body: `{"seats":3}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "key still in progress", key: "key-2", body: `{"seats":2}`, wantCode: http.StatusConflict},
+		{name: "key too long", key: strings.Repeat("k", maxKeyLength+1), body: `{"seats":2}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient()
+			calls := 0
+			r := setupRouter(client, func(c *gin.Context) {
+				calls++
+				c.JSON(http.StatusCreated, gin.H{})
+			})
+			doRequest(r, "key-1", `{"seats":2}`)
+			req := httptest.NewRequest(http.MethodPost, "/bookings", nil)
+			store := &RedisStore{client: client}
+			if _, err := store.Begin(req.Context(), "user-1", "key-2", requestHash(req, []byte(`{"seats":2}`)), testKeyTTL); err != nil {
+				t.Fatal(err)
+			}
+
+			w := doRequest(r, tt.key, tt.body)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if calls != 1 {
+				t.Errorf("handler ran %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestMiddleware_WithoutKey(t *testing.T) {
+	calls := 0
+	r := setupRouter(newMemoryClient(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	doRequest(r, "", `{"seats":2}`)
+	doRequest(r, "", `{"seats":2}`)
+
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,9 @@
+package idempotency
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	NewRedisStore,
+	NewMiddleware,
+	wire.Bind(new(Store), new(*RedisStore)),
+)
\ No newline at end of file
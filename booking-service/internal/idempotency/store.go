@@ -0,0 +1,120 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"quicket/booking-service/pkg/database"
+	"time"
+)
+
+const (
+	statusInProgress = "in_progress"
+	statusCompleted  = "completed"
+	keyPrefix        = "idempotency"
+)
+
+// Record is a completed response stored under an idempotency key.
+type Record struct {
+	ResponseCode int
+	ResponseBody []byte
+}
+
+type Store interface {
+	// Begin claims key within scope for a request with the given hash. It
+	// returns a nil record when the caller owns the key and should run the
+	// request, or the stored record when the request already completed.
+	Begin(ctx context.Context, scope, key, requestHash string, ttl time.Duration) (*Record, error)
+	Complete(ctx context.Context, scope, key string, code int, body []byte, ttl time.Duration) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type entry struct {
+	RequestHash  string `json:"request_hash"`
+	Status       string `json:"status"`
+	ResponseCode int    `json:"response_code,omitempty"`
+	ResponseBody []byte `json:"response_body,omitempty"`
+}
+
+// redisClient is the part of database.Client the store uses.
+type redisClient interface {
+	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string, dest any) error
+	CompareAndSet(ctx context.Context, key string, old, value any, ttl time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+type RedisStore struct {
+	client redisClient
+}
+
+func NewRedisStore(client *database.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+	}
+}
+
+func (s *RedisStore) Begin(ctx context.Context, scope, key, requestHash string, ttl time.Duration) (*Record, error) {
+	k := redisKey(scope, key)
+	claimed, err := s.client.SetNX(ctx, k, entry{RequestHash: requestHash, Status: statusInProgress}, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency#begin: %w", err)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	var existing entry
+	if err := s.client.Get(ctx, k, &existing); err != nil {
+		return nil, fmt.Errorf("idempotency#begin: %w", err)
+	}
+
+	switch {
+	case existing.RequestHash == "":
+		// Released or expired between SetNX and Get.
+		return nil, ErrKeyInProgress
+	case existing.RequestHash != requestHash:
+		return nil, ErrKeyMismatch
+	case existing.Status != statusCompleted:
+		return nil, ErrKeyInProgress
+	}
+
+	return &Record{
+		ResponseCode: existing.ResponseCode,
+		ResponseBody: existing.ResponseBody,
+	}, nil
+}
+
+// Complete stores the response under a key the request still holds. A key
+// that expired or was released meanwhile is not brought back: it would
+// carry no request hash and turn every retry away until it expired.
+func (s *RedisStore) Complete(ctx context.Context, scope, key string, code int, body []byte, ttl time.Duration) error {
+	k := redisKey(scope, key)
+	var existing entry
+	if err := s.client.Get(ctx, k, &existing); err != nil {
+		return fmt.Errorf("idempotency#complete: %w", err)
+	}
+	if existing.RequestHash == "" || existing.Status != statusInProgress {
+		return nil
+	}
+
+	completed := existing
+	completed.Status = statusCompleted
+	completed.ResponseCode = code
+	completed.ResponseBody = body
+	// The write only happens while the key still holds the entry read above.
+	if _, err := s.client.CompareAndSet(ctx, k, existing, completed, ttl); err != nil {
+		return fmt.Errorf("idempotency#complete: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, scope, key string) error {
+	if err := s.client.Del(ctx, redisKey(scope, key)); err != nil {
+		return fmt.Errorf("idempotency#release: %w", err)
+	}
+	return nil
+}
+
+func redisKey(scope, key string) string {
+	return fmt.Sprintf("%s:%s:%s", keyPrefix, scope, key)
+}
\ No newline at end of file
@@ -0,0 +1,199 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryClient is an in-memory Redis with expiring keys. Time only moves
+// when the test advances it.
+type memoryClient struct {
+	mu     sync.Mutex
+	now    time.Time
+	values map[string]memoryValue
+}
+
+type memoryValue struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{
+		now:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		values: map[string]memoryValue{},
+	}
+}
+
+func (m *memoryClient) advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+// load returns the live value of key. m.mu must be held.
+func (m *memoryClient) load(key string) ([]byte, bool) {
+	v, ok := m.values[key]
+	if !ok || !m.now.Before(v.expiresAt) {
+		return nil, false
+	}
+	return v.data, true
+}
+
+func (m *memoryClient) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.load(key); ok {
+		return false, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	m.values[key] = memoryValue{data: data, expiresAt: m.now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memoryClient) Get(ctx context.Context, key string, dest any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.load(key)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m *memoryClient) CompareAndSet(ctx context.Context, key string, old, value any, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldData, err := json.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	current, ok := m.load(key)
+	if !ok || !bytes.Equal(current, oldData) {
+		return false, nil
+	}
+	m.values[key] = memoryValue{data: data, expiresAt: m.now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memoryClient) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func TestRedisStore(t *testing.T) {
+	const ttl = time.Hour
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// setup runs against the store before the checked Begin of
+		// "user-1", "key-1" with hash "hash-1".
+		setup      func(s *RedisStore, client *memoryClient)
+		wantRecord *Record
+		wantErr    error
+	}{
+		{
+			name:  "new key is claimed",
+			setup: func(s *RedisStore, client *memoryClient) {},
+		},
+		{
+			name: "key in progress",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+			},
+			wantErr: ErrKeyInProgress,
+		},
+		{
+			name: "key used for another request",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-2", ttl)
+			},
+			wantErr: ErrKeyMismatch,
+		},
+		{
+			name: "completed key replays its response",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+				s.Complete(ctx, "user-1", "key-1", 201, []byte(`{"id":1}`), ttl)
+			},
+			wantRecord: &Record{ResponseCode: 201, ResponseBody: []byte(`{"id":1}`)},
+		},
+		{
+			name: "released key is claimed again",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+				s.Release(ctx, "user-1", "key-1")
+			},
+		},
+		{
+			name: "expired key is claimed again",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-2", ttl)
+				s.Complete(ctx, "user-1", "key-1", 201, []byte(`{"id":1}`), ttl)
+				client.advance(ttl)
+			},
+		},
+		{
+			name: "key that expired in progress is not completed",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+				client.advance(ttl)
+				s.Complete(ctx, "user-1", "key-1", 201, []byte(`{"id":1}`), ttl)
+			},
+		},
+		{
+			name: "released key is not completed",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+				s.Release(ctx, "user-1", "key-1")
+				s.Complete(ctx, "user-1", "key-1", 201, []byte(`{"id":1}`), ttl)
+			},
+		},
+		{
+			name: "keys are scoped per user",
+			setup: func(s *RedisStore, client *memoryClient) {
+				s.Begin(ctx, "user-2", "key-1", "hash-2", ttl)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient()
+			s := &RedisStore{client: client}
+			tt.setup(s, client)
+
+			record, err := s.Begin(ctx, "user-1", "key-1", "hash-1", ttl)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Begin() error = %v, want %v", err, tt.wantErr)
+			}
+			if (record == nil) != (tt.wantRecord == nil) {
+				t.Fatalf("Begin() record = %+v, want %+v", record, tt.wantRecord)
+			}
+			if record != nil && (record.ResponseCode != tt.wantRecord.ResponseCode || !bytes.Equal(record.ResponseBody, tt.wantRecord.ResponseBody)) {
+				t.Errorf("Begin() record = %+v, want %+v", record, tt.wantRecord)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	eventsnapshot "quicket/booking-service/internal/event_snapshot"
+	"quicket/booking-service/pkg/money"
 	"quicket/booking-service/pkg/mq/rabbitmq"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -30,6 +31,8 @@ func NewEventConsumer(consumer *rabbitmq.Consumer, logger zerolog.Logger, evSrv
 	}
 }
 
+// Start sets up the events queue and consumes it until ctx is cancelled and
+// the message in hand has been handled.
 func (c *EventConsumer) Start(ctx context.Context) error {
 	if err := c.rabbitConsumer.DeclareExchange(exchangeName, "topic"); err != nil {
 		return fmt.Errorf("failed to declare event exchange: %w", err)
@@ -141,6 +144,8 @@ func (c *EventConsumer) handleEventCreated(msg amqp.Delivery) error {
         AvailableSeats: eventMsg.AvailableSeats,
         Version:        eventMsg.Version,
         UpdatedAt:    	eventMsg.CreatedAt,
+        Currency:       currencyOf(&eventMsg),
+        TicketTypes:    toTicketTypeSnapshots(&eventMsg),
     }
 
     if err := c.evSrv.CreateSnapshot(context.Background(), &eventSnapshot); err != nil {
@@ -177,6 +182,8 @@ func (c *EventConsumer) handleEventUpdated(msg amqp.Delivery) error {
         AvailableSeats: eventMsg.AvailableSeats,
         Version:        eventMsg.Version,
         UpdatedAt:    	eventMsg.CreatedAt,
+        Currency:       currencyOf(&eventMsg.EventCreatedMessage),
+        TicketTypes:    toTicketTypeSnapshots(&eventMsg.EventCreatedMessage),
     }
 
     if err := c.evSrv.UpdateSnapshot(context.Background(), &eventSnapshot); err != nil {
@@ -213,12 +220,7 @@ func (c *EventConsumer) handleEventDeleted(msg amqp.Delivery) error {
 
 // handleSeatsUpdated handles seat availability updates
 func (c *EventConsumer) handleSeatsUpdated(msg amqp.Delivery) error {
-    var seatsMsg struct {
-        EventID        uint `json:"event_id"`
-        AvailableSeats int    `json:"available_seats"`
-        Version        int    `json:"version"`
-    }
-
+    var seatsMsg EventSeatsUpdatedMessage
     if err := json.Unmarshal(msg.Body, &seatsMsg); err != nil {
         return fmt.Errorf("failed to unmarshal seats message: %w", err)
     }
@@ -226,7 +228,6 @@ func (c *EventConsumer) handleSeatsUpdated(msg amqp.Delivery) error {
     if err := c.evSrv.UpdateSeatsSnapshot(
         context.Background(),
         seatsMsg.EventID,
-        seatsMsg.AvailableSeats,
         seatsMsg.Version,
     ); err != nil {
         return fmt.Errorf("failed to update event seats: %w", err)
@@ -234,14 +235,39 @@ func (c *EventConsumer) handleSeatsUpdated(msg amqp.Delivery) error {
 
     c.logger.Info().
         Uint("event_id", seatsMsg.EventID).
-        Int("available_seats", seatsMsg.AvailableSeats).
-        Int("version", seatsMsg.Version).
+        Uint64("available_seats", seatsMsg.AvailableSeats).
+        Uint("version", seatsMsg.Version).
         Msg("Event seats updated successfully")
 
     return nil
 }
 
-// Stop gracefully stops the consumer
-func (c *EventConsumer) Stop() {
-    c.logger.Info().Msg("Event consumer stopping")
+// toTicketTypeSnapshots maps the ticket types carried by an event message
+// to their local snapshots.
+func toTicketTypeSnapshots(msg *EventCreatedMessage) []eventsnapshot.TicketTypeSnapshot {
+    snapshots := make([]eventsnapshot.TicketTypeSnapshot, 0, len(msg.TicketTypes))
+    for _, tt := range msg.TicketTypes {
+        currency := currencyOf(msg)
+        if tt.Currency != "" {
+            currency = money.Currency(tt.Currency)
+        }
+        snapshots = append(snapshots, eventsnapshot.TicketTypeSnapshot{
+            ID:          tt.ID,
+            PublicID:    tt.PublicID,
+            EventID:     msg.ID,
+            Name:        tt.Name,
+            Price:       money.New(tt.Amount, currency),
+            Currency:    currency,
+            Available:   tt.Available,
+            MaxPerOrder: tt.MaxPerOrder,
+        })
+    }
+    return snapshots
+}
+
+func currencyOf(msg *EventCreatedMessage) money.Currency {
+    if msg.Currency == "" {
+        return money.DefaultCurrency
+    }
+    return money.Currency(msg.Currency)
 }
\ No newline at end of file
@@ -14,12 +14,35 @@ type EventCreatedMessage struct {
 	CreatedAt 		time.Time		`json:"created_at"`
 	UpdatedAt 		time.Time		`json:"updated_at"`
 	Version       	uint       		`json:"version"`
+	// Currency of the event; IDR when the event service leaves it out.
+	Currency 		string 			`json:"currency"`
+	TicketTypes 	[]TicketTypeMessage `json:"ticket_types"`
+}
+
+type TicketTypeMessage struct {
+	ID 				uint 		`json:"id"`
+	PublicID 		string 		`json:"public_id"`
+	Name 			string 		`json:"name"`
+	// Amount is the price in minor units of Currency, e.g. 1250 for
+	// 12.50 IDR. Currency falls back to that of the event.
+	Amount 			int64 		`json:"amount"`
+	Currency 		string 		`json:"currency"`
+	Available 		uint64 		`json:"available"`
+	MaxPerOrder 	uint 		`json:"max_per_order"`
 }
 
 type EventUpdatedMessage struct {
 	EventCreatedMessage `json:",inline"`
 }
 
+// EventSeatsUpdatedMessage carries the seats of an event left after the
+// event service applied a booking change.
+type EventSeatsUpdatedMessage struct {
+	EventID 		uint 		`json:"event_id"`
+	AvailableSeats 	uint64 		`json:"available_seats"`
+	Version 		uint 		`json:"version"`
+}
+
 type UserCreatedMessage struct {
 	ID 			uint 	`json:"id"`
 	PublicID 	string 	`json:"public_id"`
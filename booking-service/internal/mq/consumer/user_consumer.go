@@ -3,6 +3,7 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	usersnapshot "quicket/booking-service/internal/user_snapshot"
 	"quicket/booking-service/pkg/mq/rabbitmq"
@@ -25,17 +26,26 @@ func NewUserConsumer(rabbitConsumer *rabbitmq.Consumer, logger zerolog.Logger, u
 	}
 }
 
+// errMalformedMessage marks a message that can never be handled, however
+// often it is delivered.
+var errMalformedMessage = errors.New("malformed message")
+
 const (
 	userExchangeName = "user.exchange"
 	userQueueName = "booking-service.users.changes"
+
+	routingKeyUserCreated = "user.created"
+	routingKeyUserDeleted = "user.deleted"
 )
 
+// Start sets up the users queue and consumes it until ctx is cancelled and
+// the message in hand has been handled.
 func (u *UserConsumer) Start(ctx context.Context) error {
 	if err := u.rabbitConsumer.DeclareExchange(userExchangeName, "topic"); err != nil {
 		return fmt.Errorf("failed to declare user exchange: %w", err)
 	}
 
-	queueConfig := rabbitmq.DefaultQueueConfig(userExchangeName).WithDLQ("users.dlx")
+	queueConfig := rabbitmq.DefaultQueueConfig(userQueueName).WithDLQ("users.dlx")
 
 	queue, err := u.rabbitConsumer.DeclareQueue(queueConfig)
 	if err != nil {
@@ -43,8 +53,8 @@ func (u *UserConsumer) Start(ctx context.Context) error {
 	}
 
 	routingKeys := []string{
-		"users.created",
-		"users.deleted",
+		routingKeyUserCreated,
+		routingKeyUserDeleted,
 	}
 
 	for _, routingKey := range routingKeys {
@@ -62,6 +72,9 @@ func (u *UserConsumer) Start(ctx context.Context) error {
 	return u.rabbitConsumer.StartConsuming(ctx, queue.Name, u.handleMessage)
 }
 
+// handleMessage acks a message once handled. A malformed one is
+// dead-lettered, since it would fail on every delivery; one that failed for
+// a reason that may pass, such as the database, is requeued.
 func (u *UserConsumer) handleMessage(msg amqp.Delivery) {
 	log := u.logger.With().
 		Str("routing_key", msg.RoutingKey).
@@ -76,32 +89,31 @@ func (u *UserConsumer) handleMessage(msg amqp.Delivery) {
 		}
 	}()
 
+	var err error
 	switch msg.RoutingKey {
-	case "user.created": 
-		if err := u.handleCreatedMessage(msg); err != nil {
-			log.Error().Err(err).Msg("failed to handle user creation")
-			msg.Nack(false, true)
-			return 
-		}
-	
-	case "user.deletion": 
-		if err := u.handleDeletedMessage(msg); err != nil {
-			log.Error().Err(err).Msg("failed to handle user deletion")
-			msg.Nack(false, true)
-			return
-		}
-	
+	case routingKeyUserCreated: 
+		err = u.handleCreatedMessage(msg)
+	case routingKeyUserDeleted: 
+		err = u.handleDeletedMessage(msg)
 	default: 
 		log.Warn().Msg("unknown routing key, acknowleging and ignoring")
 	}
-	
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to handle user message")
+		msg.Nack(false, !errors.Is(err, errMalformedMessage))
+		return
+	}
 	msg.Ack(false)
 }
 
 func (u *UserConsumer) handleCreatedMessage(msg amqp.Delivery) error {
 	var userMsg UserCreatedMessage	
 	if err := json.Unmarshal(msg.Body, &userMsg); err != nil {
-		return fmt.Errorf("failed to unmarshal user message: %w", err)
+		return fmt.Errorf("%w: %v", errMalformedMessage, err)
+	}
+	if userMsg.ID == 0 || userMsg.PublicID == "" {
+		return fmt.Errorf("%w: user id and public id are required", errMalformedMessage)
 	}
 
 	log := u.logger.With().
@@ -123,7 +135,10 @@ func (u *UserConsumer) handleCreatedMessage(msg amqp.Delivery) error {
 func (u *UserConsumer) handleDeletedMessage(msg amqp.Delivery) error {
 	var userMsg UserDeletedMessage
 	if err := json.Unmarshal(msg.Body, &userMsg); err != nil {
-		return fmt.Errorf("failed to unmarshal user message: %w", err)
+		return fmt.Errorf("%w: %v", errMalformedMessage, err)
+	}
+	if userMsg.ID == 0 {
+		return fmt.Errorf("%w: user id is required", errMalformedMessage)
 	}
 
 	log := u.logger.With().
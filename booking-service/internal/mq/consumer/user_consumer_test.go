@@ -0,0 +1,92 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+// acknowledger records how a delivery was settled.
+type acknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// userSnapshots stands in for the user snapshot service; err is what its
+// writes return.
+type userSnapshots struct {
+	err error
+}
+
+func (s *userSnapshots) GetUserSnapshotID(ctx context.Context, publicID string) (*uint, error) {
+	return nil, s.err
+}
+
+func (s *userSnapshots) CreateUserSnapshot(ctx context.Context, userID uint, userPublicID string) error {
+	return s.err
+}
+
+func (s *userSnapshots) DeleteUserSnapshot(ctx context.Context, id uint) error {
+	return s.err
+}
+
+func TestUserConsumer_HandleMessage(t *testing.T) {
+	errDB := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		routingKey string
+		body       string
+		srvErr     error
+		wantAck    bool
+		wantNack   bool
+		requeue    bool
+	}{
+		{name: "created", routingKey: routingKeyUserCreated, body: `{"id":1,"public_id":"u-1"}`, wantAck: true},
+		{name: "deleted", routingKey: routingKeyUserDeleted, body: `{"id":1}`, wantAck: true},
+		{name: "unknown routing key", routingKey: "user.renamed", body: `{}`, wantAck: true},
+		{name: "undecodable body", routingKey: routingKeyUserCreated, body: `{"id":`, wantNack: true, requeue: false},
+		{name: "created without public id", routingKey: routingKeyUserCreated, body: `{"id":1}`, wantNack: true, requeue: false},
+		{name: "deleted without id", routingKey: routingKeyUserDeleted, body: `{}`, wantNack: true, requeue: false},
+		{name: "database error", routingKey: routingKeyUserDeleted, body: `{"id":1}`, srvErr: errDB, wantNack: true, requeue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ack := &acknowledger{}
+			u := NewUserConsumer(nil, zerolog.Nop(), &userSnapshots{err: tt.srvErr})
+
+			u.handleMessage(amqp.Delivery{
+				Acknowledger: ack,
+				RoutingKey:   tt.routingKey,
+				Body:         []byte(tt.body),
+			})
+
+			if ack.acked != tt.wantAck || ack.nacked != tt.wantNack {
+				t.Fatalf("acked = %v, nacked = %v, want %v, %v", ack.acked, ack.nacked, tt.wantAck, tt.wantNack)
+			}
+			if ack.nacked && ack.requeue != tt.requeue {
+				t.Errorf("requeue = %v, want %v", ack.requeue, tt.requeue)
+			}
+		})
+	}
+}
@@ -2,46 +2,45 @@ package producer
 
 import (
 	"fmt"
-	"quicket/booking-service/internal/mq"
-	"quicket/booking-service/pkg/mq/rabbitmq"
+	"quicket/booking-service/pkg/outbox"
 
 	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
+const (
+	bookingExchangeName = "booking.exchange"
+	routingKeySeatsUpdated = "bookings.seats.updated"
+)
+
+// EventProducer tells event-service about the seats bookings take and give
+// back. Messages go through the outbox: they are written in the
+// transaction that changes the seats and relayed once it commits.
 type EventProducer struct {
-	publisher *rabbitmq.Publisher
 	logger zerolog.Logger
 }
 
-func NewEventProducer(publisher *rabbitmq.Publisher) *EventProducer {
-	return &EventProducer{publisher: publisher}
+func NewEventProducer(logger zerolog.Logger) *EventProducer {
+	return &EventProducer{logger: logger}
 }
 
-func (evp *EventProducer) PublishAvailableSeatsUpdate(eventID, seats uint) error {
-	log := evp.logger.With().
-		Str("producer", "event_producer").
-		Str("action", "publish_available_seats_update").
-		Uint("event_id", eventID).
-		Uint("seats", seats).
-		Logger()
-
-	exchange := "booking.exchange"
-
-	err := evp.publisher.DeclareExchange(exchange, "topic")
-	if err != nil {
-		log.Error().Err(err).Str("exchange", exchange).Msg("failed to declare exchange")
-		return fmt.Errorf("%w: %v", mq.ErrFailedToDeclareExchange, err)
+// PublishAvailableSeatsUpdate queues, within tx, a change of delta seats
+// for the event and, when ticketTypeID is set, for that ticket type. delta
+// is negative when seats are taken. version is the number of the change
+// among those made to the event.
+func (evp *EventProducer) PublishAvailableSeatsUpdate(tx *gorm.DB, eventID uint, ticketTypeID *uint, delta int64, version uint) error {
+	msg := SeatsUpdatedMessage{
+		EventID: eventID,
+		TicketTypeID: ticketTypeID,
+		Delta: delta,
+		Version: version,
 	}
-
-	defer evp.publisher.Channel.Close()
-	body := fmt.Appendf(nil, `{"event_id": %d, "available_seats": %d}`, eventID, seats)
-
-	err = evp.publisher.Publish(exchange, "bookings.seats.updated", body)
-	if err != nil {
-		log.Error().Err(err).Str("exchange", exchange).Msg("failed to publish message")
-		return err
+	if err := outbox.Add(tx, bookingExchangeName, routingKeySeatsUpdated, msg); err != nil {
+		evp.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Int64("delta", delta).
+			Msg("failed to queue seats update")
+		return fmt.Errorf("failed to queue seats update: %w", err)
 	}
-
-	log.Info().Msgf("Published seats updated: %s", string(body))
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,33 @@
+package producer
+
+import (
+	"fmt"
+	"quicket/booking-service/pkg/outbox"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const routingKeyRefundRequested = "bookings.refund.requested"
+
+// PaymentProducer asks the payment service for refunds. Like seat updates,
+// the requests go through the outbox, so one is sent exactly when the
+// transaction that cancels the booking commits.
+type PaymentProducer struct {
+	logger zerolog.Logger
+}
+
+func NewPaymentProducer(logger zerolog.Logger) *PaymentProducer {
+	return &PaymentProducer{logger: logger}
+}
+
+// RequestRefund queues msg within tx.
+func (p *PaymentProducer) RequestRefund(tx *gorm.DB, msg RefundRequestedMessage) error {
+	if err := outbox.Add(tx, bookingExchangeName, routingKeyRefundRequested, msg); err != nil {
+		p.logger.Error().Err(err).
+			Uint("booking_id", msg.BookingID).
+			Msg("failed to queue refund request")
+		return fmt.Errorf("failed to queue refund request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package producer
+
+// SeatsUpdatedMessage moves the available seats of an event, and of the
+// ticket type when set, by Delta: negative when a booking takes seats,
+// positive when a cancelled or expired one gives them back. Version numbers
+// the changes made to the event here, starting at 1, so the event service
+// applies them one after the other.
+type SeatsUpdatedMessage struct {
+	EventID 		uint 	`json:"event_id"`
+	TicketTypeID 	*uint 	`json:"ticket_type_id,omitempty"`
+	Delta 			int64 	`json:"delta"`
+	Version 		uint 	`json:"version"`
+}
+// RefundRequestedMessage asks the payment service to give back the payment
+// of a booking that was cancelled after it was paid. Amount is in minor
+// units of Currency.
+type RefundRequestedMessage struct {
+	BookingID 		uint 	`json:"booking_id"`
+	BookingPublicID string 	`json:"booking_public_id"`
+	UserID 			uint 	`json:"user_id"`
+	Amount 			int64 	`json:"amount"`
+	Currency 		string 	`json:"currency"`
+	Reason 			string 	`json:"reason"`
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -42,8 +43,10 @@ func (r *repo) GetUserID(ctx context.Context, publicID string) (*uint, error) {
 	return usr, nil
 }
 
+// Create stores the snapshot. A snapshot that already exists is left as it
+// is, since user messages can be delivered more than once.
 func (r *repo) Create(ctx context.Context, usr *UserSnapshot) error {
-	err := r.db.WithContext(ctx).Create(usr).Error
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(usr).Error
 	return err
 }
 
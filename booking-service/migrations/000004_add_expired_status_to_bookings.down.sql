@@ -0,0 +1,4 @@
+UPDATE bookings SET status = 'failed' WHERE status = 'expired';
+ALTER TABLE bookings
+    DROP INDEX `idx_bookings_status_expired_at`,
+    MODIFY status ENUM('success', 'failed', 'pending') NOT NULL DEFAULT 'pending';
\ No newline at end of file
@@ -0,0 +1,3 @@
+UPDATE bookings SET status = 'failed' WHERE status = 'cancelled';
+ALTER TABLE bookings
+    MODIFY status ENUM('success', 'failed', 'pending', 'expired') NOT NULL DEFAULT 'pending';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE bookings
+    MODIFY status ENUM('success', 'failed', 'pending', 'expired', 'cancelled') NOT NULL DEFAULT 'pending';
\ No newline at end of file
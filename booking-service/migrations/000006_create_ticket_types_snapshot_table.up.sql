@@ -0,0 +1,12 @@
+CREATE TABLE ticket_types_snapshot (
+    id BIGINT UNSIGNED PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    event_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    available BIGINT UNSIGNED NOT NULL,
+    max_per_order INT UNSIGNED NOT NULL,
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (event_id) REFERENCES events_snapshot(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX `idx_ticket_types_snapshot_event_id` (`event_id`)
+) ENGINE = InnoDB;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE bookings
+    DROP FOREIGN KEY `fk_bookings_ticket_type_id`,
+    DROP COLUMN ticket_type_id;
\ No newline at end of file
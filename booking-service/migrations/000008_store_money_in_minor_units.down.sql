@@ -0,0 +1,9 @@
+ALTER TABLE bookings MODIFY total_price DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE bookings SET total_price = total_price / 100;
+ALTER TABLE bookings MODIFY total_price DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE ticket_types_snapshot MODIFY price DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE ticket_types_snapshot SET price = price / 100;
+ALTER TABLE ticket_types_snapshot MODIFY price DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE events_snapshot DROP COLUMN currency;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE events_snapshot
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE ticket_types_snapshot
+    MODIFY price DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER price;
+UPDATE ticket_types_snapshot SET price = price * 100;
+ALTER TABLE ticket_types_snapshot MODIFY price BIGINT NOT NULL;
+
+ALTER TABLE bookings
+    MODIFY total_price DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER total_price;
+UPDATE bookings SET total_price = total_price * 100;
+ALTER TABLE bookings MODIFY total_price BIGINT NOT NULL;
\ No newline at end of file
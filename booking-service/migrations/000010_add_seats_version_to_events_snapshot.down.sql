@@ -0,0 +1,2 @@
+ALTER TABLE `events_snapshot`
+    DROP COLUMN `seats_version`;
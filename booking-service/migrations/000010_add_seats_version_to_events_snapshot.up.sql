@@ -0,0 +1,2 @@
+ALTER TABLE `events_snapshot`
+    ADD COLUMN `seats_version` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `version`;
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type BookingConfig struct {
+	HoldDuration    time.Duration `mapstructure:"BOOKING_HOLD_DURATION"`
+	ExpiryInterval  time.Duration `mapstructure:"BOOKING_EXPIRY_INTERVAL"`
+	ExpiryBatchSize int           `mapstructure:"BOOKING_EXPIRY_BATCH_SIZE"`
+}
+
+func (b *BookingConfig) Validate() error {
+	if b.HoldDuration <= 0 {
+		return errors.New("booking hold duration must be greater than zero")
+	}
+	if b.ExpiryInterval <= 0 {
+		return errors.New("booking expiry interval must be greater than zero")
+	}
+	if b.ExpiryBatchSize <= 0 {
+		return errors.New("booking expiry batch size must be greater than zero")
+	}
+	return nil
+}
\ No newline at end of file
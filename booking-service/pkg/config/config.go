@@ -8,4 +8,7 @@ type Config struct {
 	Server   *ServerConfig
 	Clients  *ClientServices
 	RabbitMQ *RabbitMQConfig
+	Booking  *BookingConfig
+	Idempotency *IdempotencyConfig
+	Outbox   *OutboxConfig
 }
\ No newline at end of file
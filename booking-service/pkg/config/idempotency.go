@@ -0,0 +1,17 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type IdempotencyConfig struct {
+	KeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+}
+
+func (i *IdempotencyConfig) Validate() error {
+	if i.KeyTTL <= 0 {
+		return errors.New("idempotency key ttl must be greater than zero")
+	}
+	return nil
+}
\ No newline at end of file
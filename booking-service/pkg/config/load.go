@@ -12,6 +12,15 @@ func Load() (*Config, error) {
 	viper.AddConfigPath("../..")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "10s")
+	viper.SetDefault("BOOKING_HOLD_DURATION", "5m")
+	viper.SetDefault("BOOKING_EXPIRY_INTERVAL", "30s")
+	viper.SetDefault("BOOKING_EXPIRY_BATCH_SIZE", 100)
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "5m")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, err
@@ -53,6 +62,21 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	var bookingConfig BookingConfig
+	if err := viper.Unmarshal(&bookingConfig); err != nil {
+		return nil, err
+	}
+
+	var idempotencyConfig IdempotencyConfig
+	if err := viper.Unmarshal(&idempotencyConfig); err != nil {
+		return nil, err
+	}
+
+	var outboxConfig OutboxConfig
+	if err := viper.Unmarshal(&outboxConfig); err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		MySQL:  &mysqlConfig,
 		Log:    &logConfig,
@@ -61,6 +85,9 @@ func Load() (*Config, error) {
 		Server: &serverConfig,
 		Clients: &clientsConfig,
 		RabbitMQ: &rabbitMQConfig,
+		Booking: &bookingConfig,
+		Idempotency: &idempotencyConfig,
+		Outbox: &outboxConfig,
 	}
 
 	if err := validateConfig(cfg); err != nil {
@@ -88,5 +115,14 @@ func validateConfig(config *Config) error {
 	if err := config.RabbitMQ.Validate(); err != nil {
 		return err
 	}
+	if err := config.Booking.Validate(); err != nil {
+		return err
+	}
+	if err := config.Idempotency.Validate(); err != nil {
+		return err
+	}
+	if err := config.Outbox.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
\ No newline at end of file
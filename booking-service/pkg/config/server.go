@@ -1,14 +1,23 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type ServerConfig struct {
-	Port string `mapstructure:"SERVER_PORT"`
+	Port            string        `mapstructure:"SERVER_PORT"`
+	// ShutdownTimeout bounds draining requests, stopping the consumers and
+	// closing connections once the server is told to stop.
+	ShutdownTimeout time.Duration `mapstructure:"SERVER_SHUTDOWN_TIMEOUT"`
 }
 
 func (s *ServerConfig) Validate() error {
 	if s.Port == "" {
 		return errors.New("server port has not been set")
 	}
+	if s.ShutdownTimeout <= 0 {
+		return errors.New("server shutdown timeout must be greater than zero")
+	}
 	return nil
 }
\ No newline at end of file
@@ -53,4 +53,51 @@ func (c *Client) Set(ctx context.Context, key string, value any, ttl time.Durati
 	}
 
 	return c.client.Set(ctx, key, data, ttl).Err()
+}
+
+// SetNX stores value under key only when the key does not exist yet and
+// reports whether it was stored.
+func (c *Client) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	return c.client.SetNX(ctx, key, data, ttl).Result()
+}
+
+// compareAndSetScript replaces the value of KEYS[1] with ARGV[2], expiring
+// in ARGV[3] milliseconds, only while it still holds ARGV[1].
+var compareAndSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+end
+return false
+`)
+
+// CompareAndSet stores value under key only while the key still holds old,
+// as it was stored, and reports whether it was stored. A key that expired or
+// was deleted is left alone.
+func (c *Client) CompareAndSet(ctx context.Context, key string, old, value any, ttl time.Duration) (bool, error) {
+	oldData, err := json.Marshal(old)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	err = compareAndSetScript.Run(ctx, c.client, []string{key}, oldData, data, ttl.Milliseconds()).Err()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("redis compare and set failed: %w", err)
+	}
+	return true, nil
+}
+
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
 }
\ No newline at end of file
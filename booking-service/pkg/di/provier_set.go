@@ -2,6 +2,7 @@ package di
 
 import (
 	"quicket/booking-service/internal/booking"
+	"quicket/booking-service/internal/idempotency"
 	eventsnapshot "quicket/booking-service/internal/event_snapshot"
 	"quicket/booking-service/internal/mq/consumer"
 	"quicket/booking-service/internal/mq/producer"
@@ -10,6 +11,7 @@ import (
 	"quicket/booking-service/pkg/config"
 	"quicket/booking-service/pkg/database"
 	"quicket/booking-service/pkg/mq/rabbitmq"
+	"quicket/booking-service/pkg/outbox"
 
 	"github.com/google/wire"
 )
@@ -19,6 +21,7 @@ var (
 		config.Load,
 		config.NewZerolog,
 		database.ConnectMySQL,
+		database.NewRedisClient,
 	)
 	SnapshotSet = wire.NewSet(
 		eventsnapshot.ProviderSet,
@@ -27,8 +30,10 @@ var (
 	RabbitMQSet = wire.NewSet(
 		rabbitmq.SetUpProviderSet,
 		producer.NewEventProducer,
+		producer.NewPaymentProducer,
 		consumer.NewEventConsumer,
 		consumer.NewUserConsumer,
+		outbox.NewRelay,
 	)
 	AppProviderSet = wire.NewSet(
 		ConfigSet,
@@ -36,6 +41,7 @@ var (
 		clients.ClientServices,
 		SnapshotSet,
 		booking.ProviderSet,
+		idempotency.ProviderSet,
 		wire.Struct(new(App), "*"),
 	)
 )
\ No newline at end of file
@@ -2,13 +2,29 @@ package di
 
 import (
 	"quicket/booking-service/internal/booking"
+	"quicket/booking-service/internal/idempotency"
 	"quicket/booking-service/internal/mq/consumer"
 	"quicket/booking-service/pkg/config"
+	"quicket/booking-service/pkg/database"
+	"quicket/booking-service/pkg/mq/rabbitmq"
+	"quicket/booking-service/pkg/outbox"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
 type App struct {
 	Config *config.Config
+	Logger zerolog.Logger
+	DB *gorm.DB
+	Redis *database.Client
+	RabbitMQ *rabbitmq.Client
+	Consumer *rabbitmq.Consumer
+	Publisher *rabbitmq.Publisher
+	Relay *outbox.Relay
 	Handler *booking.Handler
+	Expirer *booking.Expirer
 	EventConsumer *consumer.EventConsumer
 	UserConsumer *consumer.UserConsumer
+	Idempotency *idempotency.Middleware
 }
\ No newline at end of file
@@ -9,11 +9,14 @@ package di
 import (
 	"quicket/booking-service/internal/booking"
 	"quicket/booking-service/internal/event_snapshot"
+	"quicket/booking-service/internal/idempotency"
 	"quicket/booking-service/internal/mq/consumer"
+	"quicket/booking-service/internal/mq/producer"
 	"quicket/booking-service/internal/user_snapshot"
 	"quicket/booking-service/pkg/config"
 	"quicket/booking-service/pkg/database"
 	"quicket/booking-service/pkg/mq/rabbitmq"
+	"quicket/booking-service/pkg/outbox"
 )
 
 // Injectors from wire.go:
@@ -28,13 +31,16 @@ func InitializeApp() (*App, error) {
 		return nil, err
 	}
 	logger := config.NewZerolog(configConfig)
-	repo := booking.NewRepo(db, logger)
+	eventProducer := producer.NewEventProducer(logger)
+	paymentProducer := producer.NewPaymentProducer(logger)
+	repo := booking.NewRepo(db, logger, eventProducer, paymentProducer)
 	evSnapshotRepo := eventsnapshot.NewEvSnapshotRepo(db, logger)
 	srv := eventsnapshot.NewEvSnapshotSrv(evSnapshotRepo, logger)
 	usersnapshotRepo := usersnapshot.NewRepo(db, logger)
 	usersnapshotSrv := usersnapshot.NewSrv(usersnapshotRepo, logger)
-	bookingSrv := booking.Newsrv(repo, srv, usersnapshotSrv, logger)
+	bookingSrv := booking.Newsrv(repo, srv, usersnapshotSrv, configConfig, logger)
 	handler := booking.NewHandler(bookingSrv)
+	expirer := booking.NewExpirer(repo, configConfig, logger)
 	client, err := rabbitmq.NewClient(configConfig, logger)
 	if err != nil {
 		return nil, err
@@ -45,11 +51,25 @@ func InitializeApp() (*App, error) {
 	}
 	eventConsumer := consumer.NewEventConsumer(rabbitmqConsumer, logger, srv)
 	userConsumer := consumer.NewUserConsumer(rabbitmqConsumer, logger, usersnapshotSrv)
+	publisher := rabbitmq.NewPublisher(client)
+	relay := outbox.NewRelay(db, publisher, configConfig, logger)
+	databaseClient := database.NewRedisClient(configConfig)
+	redisStore := idempotency.NewRedisStore(databaseClient)
+	middleware := idempotency.NewMiddleware(redisStore, configConfig, logger)
 	app := &App{
 		Config:        configConfig,
+		Logger:        logger,
+		DB:            db,
+		Redis:         databaseClient,
+		RabbitMQ:      client,
+		Consumer:      rabbitmqConsumer,
+		Publisher:     publisher,
+		Relay:         relay,
 		Handler:       handler,
+		Expirer:       expirer,
 		EventConsumer: eventConsumer,
 		UserConsumer:  userConsumer,
+		Idempotency:   middleware,
 	}
 	return app, nil
 }
@@ -0,0 +1,114 @@
+package money
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm/schema"
+)
+
+// Money and Currency are GORM serializers, so model fields of these types
+// need no tags. Money is stored as its minor units in a BIGINT column and
+// takes its currency from the Currency field of the same row, e.g.
+//
+//	TotalPrice money.Money    `gorm:"column:total_price;not null"`
+//	Currency   money.Currency `gorm:"column:currency;not null"`
+//
+// GORM sets the fields of a row in column order, so whichever of the two
+// comes second fills in the currency of the Money fields.
+
+var (
+	moneyType = reflect.TypeOf(Money{})
+	currencyType = reflect.TypeOf(Currency(""))
+)
+
+func (m *Money) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	amount, err := toInt64(dbValue)
+	if err != nil {
+		return fmt.Errorf("money: scan %s: %w", field.DBName, err)
+	}
+
+	m.Amount = amount
+	if f := siblingOfType(field, currencyType); f != nil {
+		m.Currency = f.ReflectValueOf(ctx, dst).Interface().(Currency)
+	}
+	return nil
+}
+
+func (m *Money) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	switch v := fieldValue.(type) {
+	case Money:
+		return v.Amount, nil
+	case *Money:
+		if v == nil {
+			return nil, nil
+		}
+		return v.Amount, nil
+	default:
+		return nil, fmt.Errorf("money: unexpected value %T for %s", fieldValue, field.DBName)
+	}
+}
+
+func (c *Currency) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	switch v := dbValue.(type) {
+	case nil:
+		*c = ""
+	case []byte:
+		*c = Currency(v)
+	case string:
+		*c = Currency(v)
+	default:
+		return fmt.Errorf("money: scan %s: unexpected currency %T", field.DBName, dbValue)
+	}
+
+	for _, f := range field.Schema.Fields {
+		if f.FieldType != moneyType {
+			continue
+		}
+		rv := f.ReflectValueOf(ctx, dst)
+		if rv.CanSet() {
+			rv.FieldByName("Currency").Set(reflect.ValueOf(*c))
+		}
+	}
+	return nil
+}
+
+func (c *Currency) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	switch v := fieldValue.(type) {
+	case Currency:
+		return string(v), nil
+	case *Currency:
+		if v == nil {
+			return nil, nil
+		}
+		return string(*v), nil
+	default:
+		return nil, fmt.Errorf("money: unexpected currency %T for %s", fieldValue, field.DBName)
+	}
+}
+
+func siblingOfType(field *schema.Field, t reflect.Type) *schema.Field {
+	for _, f := range field.Schema.Fields {
+		if f.FieldType == t {
+			return f
+		}
+	}
+	return nil
+}
+
+func toInt64(v any) (int64, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected amount %T", v)
+	}
+}
@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
 	"context"
+	"fmt"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rs/zerolog"
@@ -82,11 +83,18 @@ func (c *Consumer) BindQueue(exchange, queue, routingKey string) error {
 	)
 }
 
+// StartConsuming hands the messages of a queue to handler one at a time
+// and blocks until ctx is cancelled or the channel closes. On cancellation
+// the broker stops delivering, the message being handled is finished and
+// any message already delivered but not handled yet is requeued.
 func (c *Consumer) StartConsuming(ctx context.Context, queueName string, handler func(amqp.Delivery)) error {
-    // Set up consumer
+    // The queue name doubles as the consumer tag; a queue is consumed once
+    // per channel.
+    tag := queueName
+
     messages, err := c.Channel.Consume(
         queueName,
-        "",    // consumer tag
+        tag,
         false, // auto-ack
         false, // exclusive
         false, // no-local
@@ -97,14 +105,30 @@ func (c *Consumer) StartConsuming(ctx context.Context, queueName string, handler
         c.Channel.Close() // Close Channel if setup fails
         return err
     }
-    
-    // Start message processing in goroutine
+
+    done := make(chan struct{})
     go func() {
+        defer close(done)
         for msg := range messages {
+            if ctx.Err() != nil {
+                msg.Nack(false, true)
+                continue
+            }
             handler(msg)
         }
     }()
-    
+
+    select {
+    case <-done:
+        return fmt.Errorf("deliveries of queue %s stopped: %w", queueName, amqp.ErrClosed)
+    case <-ctx.Done():
+    }
+
+    if err := c.Channel.Cancel(tag, false); err != nil {
+        c.logger.Warn().Err(err).Str("queue", queueName).Msg("failed to cancel consumer")
+    }
+    <-done
+    c.logger.Info().Str("queue", queueName).Msg("consumer stopped")
     return nil
 }
 
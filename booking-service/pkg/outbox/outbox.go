@@ -0,0 +1,269 @@
+// Package outbox stores outgoing messages in the same transaction as the
+// change they announce and relays them to RabbitMQ once committed, so a
+// message goes out if and only if its change was saved.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"quicket/booking-service/pkg/config"
+	"quicket/booking-service/pkg/mq/rabbitmq"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// leaseDuration is how long a relay has to publish the messages it leased
+// before another relay may take them over.
+const leaseDuration = time.Minute
+
+// Message is a message waiting in the outbox, or already relayed when
+// SentAt is set. MessageID goes out as the AMQP message id.
+type Message struct {
+	ID          uint64     `gorm:"primaryKey"`
+	MessageID   string     `gorm:"column:message_id;type:char(36);uniqueIndex"`
+	Exchange    string     `gorm:"column:exchange;size:100;not null"`
+	RoutingKey  string     `gorm:"column:routing_key;size:100;not null"`
+	Payload     []byte     `gorm:"column:payload;type:json;not null"`
+	Attempts    uint       `gorm:"column:attempts;not null"`
+	LastError   *string    `gorm:"column:last_error;type:text"`
+	AvailableAt time.Time  `gorm:"column:available_at;not null"`
+	LeasedUntil *time.Time `gorm:"column:leased_until"`
+	SentAt      *time.Time `gorm:"column:sent_at"`
+	CreatedAt   time.Time
+}
+
+func (m *Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Add stores payload, encoded as JSON, to be published to exchange with
+// routingKey. tx should be the transaction that makes the change the
+// message announces.
+func Add(tx *gorm.DB, exchange, routingKey string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %w", routingKey, err)
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	msg := Message{
+		MessageID:   id.String(),
+		Exchange:    exchange,
+		RoutingKey:  routingKey,
+		Payload:     body,
+		AvailableAt: time.Now(),
+	}
+	if err := tx.Create(&msg).Error; err != nil {
+		return fmt.Errorf("failed to store %s message: %w", routingKey, err)
+	}
+	return nil
+}
+
+// Relay publishes the outbox in order of insertion. A message that fails
+// to publish is tried again after a backoff that doubles up to MaxBackoff,
+// and holds back the messages after it until then so consumers see the
+// changes of an entity in order. Relays of several replicas take turns: one
+// leases the oldest messages and publishes them outside of any transaction,
+// and the others leave the outbox alone while the head of it is leased.
+// Delivery is at least once: a message can go out again when marking it
+// sent fails or its lease runs out.
+type Relay struct {
+	db         *gorm.DB
+	publisher  *rabbitmq.Publisher
+	interval   time.Duration
+	batchSize  int
+	maxBackoff time.Duration
+	logger     zerolog.Logger
+}
+
+func NewRelay(db *gorm.DB, publisher *rabbitmq.Publisher, cfg *config.Config, logger zerolog.Logger) *Relay {
+	return &Relay{
+		db:         db,
+		publisher:  publisher,
+		interval:   cfg.Outbox.PollInterval,
+		batchSize:  cfg.Outbox.BatchSize,
+		maxBackoff: cfg.Outbox.MaxBackoff,
+		logger:     logger,
+	}
+}
+
+// Run relays the outbox until ctx is cancelled. A full batch is followed
+// by the next one straight away; otherwise the relay waits PollInterval.
+func (r *Relay) Run(ctx context.Context) error {
+	r.logger.Info().Dur("interval", r.interval).Msg("outbox relay started")
+
+	for {
+		sent, err := r.relay(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error().Err(err).Msg("outbox relay failed")
+		}
+
+		wait := r.interval
+		if err == nil && sent == r.batchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg("outbox relay stopped")
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// relay publishes the messages it leases and returns how many went out.
+// Marking a message is not cancelled with ctx, so what was published before
+// shutdown is not published again; what was not is handed back.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	db := r.db.WithContext(context.WithoutCancel(ctx))
+	batch, err := r.lease(db)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range batch {
+		msg := &batch[i]
+		if ctx.Err() != nil {
+			return i, r.release(db, batch[i:])
+		}
+
+		err := r.publisher.Publish(ctx, rabbitmq.Message{
+			ID:         msg.MessageID,
+			Exchange:   msg.Exchange,
+			RoutingKey: msg.RoutingKey,
+			Body:       msg.Payload,
+			Timestamp:  msg.CreatedAt,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				// Cut off by shutdown; not the message's fault.
+				return i, r.release(db, batch[i:])
+			}
+			if err := r.retryLater(db, msg, err); err != nil {
+				return i, err
+			}
+			return i, r.release(db, batch[i+1:])
+		}
+
+		if err := db.Model(msg).Updates(map[string]any{
+			"sent_at":      time.Now(),
+			"leased_until": nil,
+		}).Error; err != nil {
+			return i, fmt.Errorf("failed to mark message %s sent: %w", msg.MessageID, err)
+		}
+	}
+	return len(batch), nil
+}
+
+// lease takes the oldest unsent messages that are due, up to the first one
+// that is not, for leaseDuration. The rows are locked only while leasing, so
+// publishing holds no connection or lock.
+func (r *Relay) lease(db *gorm.DB) ([]Message, error) {
+	var leased []Message
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var batch []Message
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("sent_at IS NULL").
+			Order("id").
+			Limit(r.batchSize).
+			Find(&batch).Error; err != nil {
+			return fmt.Errorf("failed to select outbox messages: %w", err)
+		}
+
+		now := time.Now()
+		leased = due(batch, now)
+		if len(leased) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(leased))
+		for _, msg := range leased {
+			ids = append(ids, msg.ID)
+		}
+		if err := tx.Model(&Message{}).
+			Where("id IN ?", ids).
+			Update("leased_until", now.Add(leaseDuration)).Error; err != nil {
+			return fmt.Errorf("failed to lease outbox messages: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leased, nil
+}
+
+// due returns the leading messages of batch, oldest first, that can be
+// published at now. It stops at a message waiting out its backoff, since the
+// messages after it wait too, and at one another relay holds a lease on.
+func due(batch []Message, now time.Time) []Message {
+	for i, msg := range batch {
+		if msg.AvailableAt.After(now) || (msg.LeasedUntil != nil && msg.LeasedUntil.After(now)) {
+			return batch[:i]
+		}
+	}
+	return batch
+}
+
+// release hands back the lease on messages that were not published.
+func (r *Relay) release(db *gorm.DB, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(msgs))
+	for _, msg := range msgs {
+		ids = append(ids, msg.ID)
+	}
+	if err := db.Model(&Message{}).
+		Where("id IN ?", ids).
+		Update("leased_until", nil).Error; err != nil {
+		return fmt.Errorf("failed to release outbox messages: %w", err)
+	}
+	return nil
+}
+
+// retryLater records the failed attempt on msg, hands back its lease and
+// pushes it back by the backoff.
+func (r *Relay) retryLater(db *gorm.DB, msg *Message, cause error) error {
+	attempts := msg.Attempts + 1
+	backoff := r.backoff(attempts)
+
+	r.logger.Warn().Err(cause).
+		Str("message_id", msg.MessageID).
+		Str("routing_key", msg.RoutingKey).
+		Uint("attempts", attempts).
+		Dur("backoff", backoff).
+		Msg("publishing outbox message failed")
+
+	lastError := cause.Error()
+	if err := db.Model(msg).Updates(map[string]any{
+		"attempts":     attempts,
+		"last_error":   lastError,
+		"available_at": time.Now().Add(backoff),
+		"leased_until": nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to record attempt of message %s: %w", msg.MessageID, err)
+	}
+	return nil
+}
+
+// backoff is how long a message waits after its attempts-th failed attempt:
+// the poll interval, doubled for every attempt before it, up to maxBackoff.
+func (r *Relay) backoff(attempts uint) time.Duration {
+	backoff := r.interval << min(attempts-1, 20)
+	if backoff <= 0 || backoff > r.maxBackoff {
+		return r.maxBackoff
+	}
+	return backoff
+}
@@ -54,6 +54,9 @@ func registerRoutes(r *gin.Engine, app *di.App) {
 	protected := r.Group("/api/v1/bookings")
 	protected.Use(middleware.JWTAuthMiddleware(app.Config.JWT.JWTSecret))
 	{
-		protected.POST("/", app.Handler.CreateBooking)
+		protected.POST("/", app.Idempotency.Handle(), app.Handler.CreateBooking)
+		protected.POST("/:id/cancel", app.Handler.CancelBooking)
+		protected.GET("", app.Handler.ListBookings)
+		protected.GET("/:publicID", app.Handler.GetBooking)
 	}
 }
\ No newline at end of file
@@ -3,8 +3,13 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
+	// Venue time zones are loaded from the embedded zone database, so they
+	// do not depend on the image shipping one.
+	_ "time/tzdata"
 
 	"github.com/anrisys/quicket/event-service/pkg/di"
+	"github.com/anrisys/quicket/event-service/pkg/lifecycle"
 	"github.com/anrisys/quicket/event-service/router"
 )
 
@@ -31,11 +36,30 @@ func main() {
     if err != nil {
         log.Fatalf("Failed to initialize app: %v", err)
     }
+
+    lc := lifecycle.New(app.Config.Server.ShutdownTimeout, app.Logger)
+
+    sqlDB, err := app.DB.DB()
+    if err != nil {
+        log.Fatalf("Failed to get database pool: %v", err)
+    }
+    lc.OnClose("mysql", sqlDB.Close)
+    lc.OnClose("redis", app.Redis.Close)
+    lc.OnClose("rabbitmq", app.RabbitMQ.Close)
+    lc.OnClose("rabbitmq publisher", app.Publisher.Close)
+
+    lc.Go("outbox relay", app.Relay.Run)
+    lc.Go("seats consumer", app.SeatsConsumer.Start)
+    lc.Go("event series materializer", app.Materializer.Run)
     
     r := router.SetupRouter(app)
     
-    addr := fmt.Sprintf(":%s", app.Config.Server.Port)
-	if err := r.Run(addr); err != nil {
-        log.Fatalf("Server failed :%v", err)
+    lc.Serve(&http.Server{
+        Addr: fmt.Sprintf(":%s", app.Config.Server.Port),
+        Handler: r,
+    })
+
+    if err := lc.Wait(); err != nil {
+        log.Fatalf("Server stopped with errors: %v", err)
     }
 }
\ No newline at end of file
@@ -2,6 +2,14 @@ package internal
 
 import "time"
 
+// EventVenueDTO is the venue an event is held at. The event's times are
+// given in its time zone.
+type EventVenueDTO struct {
+	PublicID string `json:"public_id"`
+	Name     string `json:"name"`
+	TimeZone string `json:"time_zone" example:"Asia/Jakarta"`
+}
+
 type EventDTO struct {
 	PublicID       string
 	Title          string
@@ -10,8 +18,13 @@ type EventDTO struct {
 	EndDate        time.Time
 	MaxSeats       uint64
 	AvailableSeats uint64
+	Venue          *EventVenueDTO `json:",omitempty"`
+	TicketTypes    []TicketTypeDTO
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	// DeletedAt is set on an event that was soft-deleted because it has
+	// sold seats.
+	DeletedAt      *time.Time `json:",omitempty"`
 }
 
 type EventDTOWithID struct {
@@ -19,19 +32,175 @@ type EventDTOWithID struct {
 	EventDTO
 }
 
+type EventListDTO struct {
+	Events     []EventDTO `json:"events"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type EventSeriesDTO struct {
+	PublicID          string    `json:"public_id"`
+	Title             string    `json:"title"`
+	Description       *string   `json:"description,omitempty"`
+	RRule             string    `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO;COUNT=10"`
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	MaxSeats          uint64    `json:"max_seats"`
+	Venue             *EventVenueDTO `json:"venue,omitempty"`
+	// MaterializedUntil is how far ahead the occurrences exist.
+	MaterializedUntil time.Time `json:"materialized_until"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type EventSeriesListDTO struct {
+	Series     []EventSeriesDTO `json:"series"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
 type SimpleEventDTO struct {
 	PublicID  string    `json:"public_id" example:"evt_123"`
 	Title     string    `json:"title" example:"Concert Night"`
 	StartDate time.Time `json:"start_date" example:"2023-12-31T20:00:00Z"`
 	EndDate   time.Time `json:"end_date" example:"2023-12-31T23:59:59Z"`
+	Venue     *EventVenueDTO `json:"venue,omitempty"`
 }
 
+type TicketTypeDTO struct {
+	PublicID    string  `json:"id"`
+	Name        string  `json:"name"`
+	Price       float32 `json:"price"`
+	Quota       uint64  `json:"quota"`
+	Available   uint64  `json:"available"`
+	MaxPerOrder uint    `json:"max_per_order"`
+}
+
+// CreateEventRequest creates an event. StartDate has to fall after today in
+// the time zone of the venue, or UTC without one. MaxSeats defaults to the
+// capacity of the venue.
 type CreateEventRequest struct {
 	Title     string 	`json:"title" binding:"required,min=3,max=256"`
-	StartDate time.Time `json:"start_date" binding:"required,gttoday"`
+	StartDate time.Time `json:"start_date" binding:"required"`
 	EndDate time.Time `json:"end_date" binding:"required,gtefield=StartDate"`
 	Description string `json:"description" binding:"max=2000,omitempty"`
-	MaxSeats uint64 `json:"max_seats" binding:"required,gt=0"`
+	// VenueID is the public ID of the venue the event is held at.
+	VenueID string `json:"venue_id" binding:"omitempty,max=36"`
+	MaxSeats uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	TicketTypes []CreateTicketTypeRequest `json:"ticket_types" binding:"omitempty,dive"`
+}
+
+// UpdateEventRequest holds the fields of an event to change; omitted fields
+// are left as they are.
+type UpdateEventRequest struct {
+	Title     *string 	`json:"title" binding:"omitempty,min=3,max=256"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate *time.Time `json:"end_date"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	MaxSeats *uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	VenueID *string `json:"venue_id" binding:"omitempty,max=36"`
+}
+
+// UpdateEventQuery tells which events of a series an update applies to:
+// this one only, the default, or this one and the ones that follow.
+type UpdateEventQuery struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=this following"`
+}
+
+// CreateEventSeriesRequest creates a recurring event. RRule is a recurrence
+// rule such as FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10, and StartDate and EndDate
+// are those of its first occurrence. The rule is expanded in the time zone
+// of the venue, so occurrences keep their local time across DST changes.
+type CreateEventSeriesRequest struct {
+	Title     string 	`json:"title" binding:"required,min=3,max=256"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate time.Time `json:"end_date" binding:"required,gtefield=StartDate"`
+	Description string `json:"description" binding:"max=2000,omitempty"`
+	VenueID string `json:"venue_id" binding:"omitempty,max=36"`
+	MaxSeats uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	RRule string `json:"rrule" binding:"required,max=256"`
+}
+
+// ListEventSeriesQuery pages the series listing, optionally of one
+// organizer.
+type ListEventSeriesQuery struct {
+	Organizer string `form:"organizer"`
+	Cursor    string `form:"cursor"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ListEventsQuery filters and pages the event listing. Q is matched
+// against the title and description; From and To bound the start and end
+// dates; Organizer is the public ID of the organizing user; Series keeps
+// the occurrences of the series with that public ID.
+type ListEventsQuery struct {
+	Q         string    `form:"q" binding:"omitempty,max=100"`
+	From      time.Time `form:"from"`
+	To        time.Time `form:"to"`
+	Available bool      `form:"available"`
+	Organizer string    `form:"organizer"`
+	Series    string    `form:"series"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=start_date -start_date created_at -created_at"`
+	Cursor    string    `form:"cursor"`
+	Limit     int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type CreateTicketTypeRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Price       float32 `json:"price" binding:"gte=0"`
+	Quota       uint64  `json:"quota" binding:"required,gt=0"`
+	MaxPerOrder uint    `json:"max_per_order" binding:"required,gt=0,ltefield=Quota"`
+}
+
+type VenueDTO struct {
+	PublicID  string    `json:"public_id"`
+	Name      string    `json:"name" example:"Jakarta International Expo"`
+	Address   string    `json:"address"`
+	TimeZone  string    `json:"time_zone" example:"Asia/Jakarta"`
+	Capacity  uint64    `json:"capacity" example:"5000"`
+	Latitude  *float64  `json:"latitude,omitempty" example:"-6.1466"`
+	Longitude *float64  `json:"longitude,omitempty" example:"106.8456"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type VenueListDTO struct {
+	Venues     []VenueDTO `json:"venues"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// CreateVenueRequest creates a venue. TimeZone is an IANA zone name such as
+// Asia/Jakarta. Latitude and Longitude are given together or not at all.
+type CreateVenueRequest struct {
+	Name      string   `json:"name" binding:"required,min=3,max=256"`
+	Address   string   `json:"address" binding:"required,max=512"`
+	TimeZone  string   `json:"time_zone" binding:"required,timezone"`
+	Capacity  uint64   `json:"capacity" binding:"required,gt=0"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// UpdateVenueRequest holds the fields of a venue to change; omitted fields
+// are left as they are.
+type UpdateVenueRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=3,max=256"`
+	Address   *string  `json:"address" binding:"omitempty,max=512"`
+	TimeZone  *string  `json:"time_zone" binding:"omitempty,timezone"`
+	Capacity  *uint64  `json:"capacity" binding:"omitempty,gt=0"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// ListVenuesQuery pages the venue listing, optionally of one owner.
+type ListVenuesQuery struct {
+	Owner  string `form:"owner"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UpdateTicketTypeRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Price       *float32 `json:"price" binding:"omitempty,gte=0"`
+	Quota       *uint64  `json:"quota" binding:"omitempty,gt=0"`
+	MaxPerOrder *uint    `json:"max_per_order" binding:"omitempty,gt=0"`
 }
 
 type ResponseSuccess struct {
@@ -42,6 +211,42 @@ type ResponseSuccess struct {
 type CreateEventSuccessResponse struct {
 	ResponseSuccess `json:",inline"`
 	Event           SimpleEventDTO `json:"event"`
+	TicketTypes     []TicketTypeDTO `json:"ticket_types,omitempty"`
+}
+
+type TicketTypeSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	TicketType      TicketTypeDTO `json:"ticket_type"`
+}
+
+type EventSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Event           EventDTO `json:"event"`
+}
+
+type EventSeriesSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Series          EventSeriesDTO `json:"series"`
+}
+
+type ListEventSeriesSuccessResponse struct {
+	ResponseSuccess    `json:",inline"`
+	EventSeriesListDTO `json:",inline"`
+}
+
+type VenueSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Venue           VenueDTO `json:"venue"`
+}
+
+type ListVenuesSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	VenueListDTO    `json:",inline"`
+}
+
+type ListEventsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	EventListDTO    `json:",inline"`
 }
 
 type EventDateTimeAndSeats struct {
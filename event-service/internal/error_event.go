@@ -5,5 +5,18 @@ import "errors"
 var (
 	ErrEventAlreadyExist = errors.New("event already exists")
 	ErrEventNotFound = errors.New("event not found")
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrQuotaExceedsSeats = errors.New("sum of ticket type quotas exceeds max seats")
+	ErrQuotaBelowSold = errors.New("ticket type quota below seats already sold")
+	ErrMaxPerOrderAboveQuota = errors.New("ticket type max per order above quota")
+	ErrMaxSeatsBelowSold = errors.New("event max seats below seats already sold")
+	ErrInvalidDates = errors.New("event end date before start date")
+	ErrSeriesNotFound = errors.New("event series not found")
+	ErrSeriesChanged = errors.New("event series changed since it was read")
+	ErrVenueNotFound = errors.New("venue not found")
+	ErrVenueInUse = errors.New("venue has events")
+	ErrSeatsOutOfRange = errors.New("seat change takes available seats out of range")
+	ErrDuplicateMessage = errors.New("message already applied")
+	ErrSeatsVersionGap = errors.New("seat change does not follow the last one applied")
 	ErrDB = errors.New("database error")
 )
\ No newline at end of file
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// seriesRepo records the occurrences the materializer adds. It reports none
+// created, so the listing cache is left alone.
+type seriesRepo struct {
+	SeriesRepositoryInterface
+	added    []Event
+	until    time.Time
+	finished bool
+	calls    int
+}
+
+func (r *seriesRepo) AddOccurrences(ctx context.Context, series *EventSeries, occurrences []Event, until time.Time, finished bool) (int, error) {
+	r.calls++
+	r.added = occurrences
+	r.until = until
+	r.finished = finished
+	return 0, nil
+}
+
+func TestMaterializer_Materialize(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	// 19:00 in Jakarta on Monday 4 May.
+	start := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+	venue := &Venue{TimeZone: "Asia/Jakarta"}
+
+	tests := []struct {
+		name         string
+		series       EventSeries
+		wantStarts   []time.Time
+		wantFinished bool
+		wantCalled   bool
+	}{
+		{
+			name:   "unbounded rule up to the horizon",
+			series: EventSeries{RRule: "FREQ=WEEKLY", StartDate: start, EndDate: start.Add(2 * time.Hour), Venue: venue},
+			wantStarts: []time.Time{
+				time.Date(2026, 5, 4, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 11, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 18, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 25, 19, 0, 0, 0, jakarta),
+			},
+			wantCalled: true,
+		},
+		{
+			name:   "starts from the watermark",
+			series: EventSeries{RRule: "FREQ=WEEKLY", StartDate: start, EndDate: start.Add(2 * time.Hour), Venue: venue, MaterializedUntil: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC)},
+			wantStarts: []time.Time{
+				time.Date(2026, 5, 18, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 25, 19, 0, 0, 0, jakarta),
+			},
+			wantCalled: true,
+		},
+		{
+			name:   "bounded rule ending inside the horizon is finished",
+			series: EventSeries{RRule: "FREQ=WEEKLY;COUNT=2", StartDate: start, EndDate: start.Add(2 * time.Hour), Venue: venue},
+			wantStarts: []time.Time{
+				time.Date(2026, 5, 4, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 11, 19, 0, 0, 0, jakarta),
+			},
+			wantFinished: true,
+			wantCalled:   true,
+		},
+		{
+			name:   "finished series is skipped",
+			series: EventSeries{RRule: "FREQ=WEEKLY", StartDate: start, EndDate: start.Add(2 * time.Hour), Venue: venue, Finished: true},
+		},
+		{
+			name:   "series already up to the horizon is skipped",
+			series: EventSeries{RRule: "FREQ=WEEKLY", StartDate: start, EndDate: start.Add(2 * time.Hour), Venue: venue, MaterializedUntil: now.AddDate(0, 1, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &seriesRepo{}
+			m := &Materializer{repo: repo, horizon: 30 * 24 * time.Hour, logger: zerolog.Nop()}
+
+			if _, err := m.Materialize(context.Background(), &tt.series, now); err != nil {
+				t.Fatalf("Materialize() error = %v", err)
+			}
+
+			if called := repo.calls > 0; called != tt.wantCalled {
+				t.Fatalf("AddOccurrences called = %v, want %v", called, tt.wantCalled)
+			}
+			if !tt.wantCalled {
+				return
+			}
+			var starts []time.Time
+			for _, ev := range repo.added {
+				starts = append(starts, ev.StartDate.In(jakarta))
+				if got := ev.EndDate.Sub(ev.StartDate); got != 2*time.Hour {
+					t.Errorf("occurrence lasts %v, want 2h", got)
+				}
+				if ev.AvailableSeats != ev.MaxSeats {
+					t.Errorf("occurrence has %d of %d seats available", ev.AvailableSeats, ev.MaxSeats)
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.wantStarts) {
+				t.Errorf("occurrence starts = %v, want %v", starts, tt.wantStarts)
+			}
+			if repo.finished != tt.wantFinished {
+				t.Errorf("finished = %v, want %v", repo.finished, tt.wantFinished)
+			}
+			if want := now.Add(m.horizon); !repo.until.Equal(want) {
+				t.Errorf("until = %v, want %v", repo.until, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"math"
+	"time"
+)
+
+// Messages announcing changes to events are published to EventsExchange.
+// Their shape is shared with the consumers in booking-service.
+const (
+	EventsExchange = "events.exchange"
+
+	RoutingKeyEventCreated = "event.created"
+	RoutingKeyEventUpdated = "event.updated"
+	RoutingKeyEventDeleted = "event.deleted"
+	RoutingKeyEventSeatsUpdated = "event.seats.updated"
+)
+
+// booking-service publishes the seats its bookings take and give back to
+// BookingsExchange.
+const (
+	BookingsExchange = "booking.exchange"
+
+	RoutingKeyBookingSeatsUpdated = "bookings.seats.updated"
+)
+
+// Ticket type prices are kept with two decimals in ticketCurrency. Messages
+// carry them as integer minor units, so consumers never round a float.
+const ticketCurrency = "IDR"
+
+// EventCreatedMessage carries the whole event with its ticket types.
+// Version grows with every change so consumers can tell stale copies.
+type EventCreatedMessage struct {
+	ID             uint                `json:"id"`
+	PublicID       string              `json:"public_id"`
+	Title          string              `json:"title"`
+	StartDate      time.Time           `json:"start_date"`
+	EndDate        time.Time           `json:"end_date"`
+	AvailableSeats uint64              `json:"available_seats"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Version        uint                `json:"version"`
+	Currency       string              `json:"currency"`
+	TicketTypes    []TicketTypeMessage `json:"ticket_types"`
+}
+
+// TicketTypeMessage carries the price of a ticket type as Amount minor
+// units of Currency; 1250 IDR is 12.50 IDR.
+type TicketTypeMessage struct {
+	ID          uint   `json:"id"`
+	PublicID    string `json:"public_id"`
+	Name        string `json:"name"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Available   uint64 `json:"available"`
+	MaxPerOrder uint   `json:"max_per_order"`
+}
+
+type EventUpdatedMessage struct {
+	EventCreatedMessage `json:",inline"`
+}
+
+type EventDeletedMessage struct {
+	EventID uint `json:"event_id"`
+}
+
+// EventSeatsUpdatedMessage carries the seats left after a booking change.
+type EventSeatsUpdatedMessage struct {
+	EventID        uint   `json:"event_id"`
+	AvailableSeats uint64 `json:"available_seats"`
+	Version        uint   `json:"version"`
+}
+
+// BookingSeatsUpdatedMessage moves the available seats of an event, and of
+// the ticket type when set, by Delta: negative when a booking takes seats,
+// positive when a cancelled or expired one gives them back. Version numbers
+// the changes booking-service made to the event, so each one applies on top
+// of the version before it.
+type BookingSeatsUpdatedMessage struct {
+	EventID      uint  `json:"event_id"`
+	TicketTypeID *uint `json:"ticket_type_id,omitempty"`
+	Delta        int64 `json:"delta"`
+	Version      uint  `json:"version"`
+}
+
+func newEventCreatedMessage(ev *Event) EventCreatedMessage {
+	ticketTypes := make([]TicketTypeMessage, 0, len(ev.TicketTypes))
+	for _, tt := range ev.TicketTypes {
+		ticketTypes = append(ticketTypes, TicketTypeMessage{
+			ID:          tt.ID,
+			PublicID:    tt.PublicID,
+			Name:        tt.Name,
+			Amount:      minorUnits(tt.Price),
+			Currency:    ticketCurrency,
+			Available:   tt.Available,
+			MaxPerOrder: tt.MaxPerOrder,
+		})
+	}
+
+	return EventCreatedMessage{
+		ID:             ev.ID,
+		PublicID:       ev.PublicID,
+		Title:          ev.Title,
+		StartDate:      ev.StartDate,
+		EndDate:        ev.EndDate,
+		AvailableSeats: ev.AvailableSeats,
+		CreatedAt:      ev.CreatedAt,
+		UpdatedAt:      ev.UpdatedAt,
+		Version:        ev.Version,
+		Currency:       ticketCurrency,
+		TicketTypes:    ticketTypes,
+	}
+}
+
+// minorUnits converts a price stored with two decimals to minor units.
+func minorUnits(price float32) int64 {
+	return int64(math.Round(float64(price) * 100))
+}
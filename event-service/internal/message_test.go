@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		price float32
+		want  int64
+	}{
+		{price: 0, want: 0},
+		{price: 150000, want: 15000000},
+		{price: 19.99, want: 1999},
+		{price: 0.1, want: 10},
+	}
+
+	for _, tt := range tests {
+		if got := minorUnits(tt.price); got != tt.want {
+			t.Errorf("minorUnits(%v) = %d, want %d", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestNewEventCreatedMessage(t *testing.T) {
+	ev := &Event{
+		Model:          gorm.Model{ID: 7},
+		PublicID:       "ev-7",
+		Title:          "Jazz night",
+		AvailableSeats: 90,
+		Version:        3,
+		TicketTypes: []TicketType{
+			{ID: 1, PublicID: "tt-1", Name: "VIP", Price: 250.5, Available: 10, MaxPerOrder: 2},
+		},
+	}
+
+	msg := newEventCreatedMessage(ev)
+
+	if msg.ID != 7 || msg.PublicID != "ev-7" || msg.AvailableSeats != 90 || msg.Version != 3 || msg.Currency != ticketCurrency {
+		t.Errorf("message = %+v", msg)
+	}
+	if len(msg.TicketTypes) != 1 {
+		t.Fatalf("ticket types = %+v, want one", msg.TicketTypes)
+	}
+	tt := msg.TicketTypes[0]
+	if tt.ID != 1 || tt.Amount != 25050 || tt.Currency != ticketCurrency || tt.Available != 10 || tt.MaxPerOrder != 2 {
+		t.Errorf("ticket type = %+v", tt)
+	}
+}
@@ -16,8 +16,122 @@ type Event struct {
 	MaxSeats 		uint64 		`gorm:"column:max_seats"`
 	AvailableSeats 	uint64 		`gorm:"column:available_seats"`
 	OrganizerID 	uint 		`gorm:"column:organizer_id;not null"`
+	VenueID 		*uint 		`gorm:"column:venue_id"`
+	Venue 			*Venue 		`gorm:"foreignKey:VenueID"`
+	// Version counts the changes announced for the event, starting at 1.
+	Version 		uint 		`gorm:"column:version;not null;default:1"`
+	// BookingSeatsVersion is the version of the last seat change applied
+	// from booking-service, which numbers its changes to each event from 1.
+	BookingSeatsVersion uint 	`gorm:"column:booking_seats_version;not null;default:0"`
+	TicketTypes 	[]TicketType `gorm:"foreignKey:EventID"`
+	// SeriesID is set on an occurrence of an event series. OccurrenceStart
+	// is the start the recurrence rule gave it, which stays put when the
+	// occurrence is moved so it is not generated again.
+	SeriesID 		*uint 		`gorm:"column:series_id;uniqueIndex:idx_events_series_occurrence"`
+	OccurrenceStart *time.Time 	`gorm:"column:occurrence_start;uniqueIndex:idx_events_series_occurrence"`
 }
 
 func (e *Event) TableName() string {
 	return "events"
+}
+
+// Location is the time zone the event's times are read in, that of its
+// venue. They are stored in UTC.
+func (e *Event) Location() *time.Location {
+	return e.Venue.Location()
+}
+
+// TicketType is a priced tier of an event. Its quota is carved out of the
+// event's MaxSeats, and Available counts down as bookings take seats.
+type TicketType struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	EventID 		uint 		`gorm:"column:event_id;not null;index"`
+	Name 			string 		`gorm:"column:name;size:100;not null"`
+	Price 			float32 	`gorm:"column:price;type:decimal(10,2);not null"`
+	Quota 			uint64 		`gorm:"column:quota;not null"`
+	Available 		uint64 		`gorm:"column:available;not null"`
+	MaxPerOrder 	uint 		`gorm:"column:max_per_order;not null"`
+	CreatedAt 		time.Time
+	UpdatedAt 		time.Time
+}
+
+func (t *TicketType) TableName() string {
+	return "ticket_types"
+}
+
+// EventSeries is the template of a recurring event. Its occurrences are
+// ordinary events that point back at it; they are created ahead of time up
+// to MaterializedUntil, which moves along a rolling horizon. StartDate and
+// EndDate are those of the first occurrence.
+type EventSeries struct {
+	gorm.Model
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	Title 			string 		`gorm:"column:title;size:256;not null"`
+	Description 	*string 	`gorm:"type:text"`
+	OrganizerID 	uint 		`gorm:"column:organizer_id;not null"`
+	RRule 			string 		`gorm:"column:rrule;size:256;not null"`
+	StartDate 		time.Time 	`gorm:"column:start_date;not null"`
+	EndDate 		time.Time 	`gorm:"column:end_date;not null"`
+	MaxSeats 		uint64 		`gorm:"column:max_seats;not null"`
+	VenueID 		*uint 		`gorm:"column:venue_id"`
+	Venue 			*Venue 		`gorm:"foreignKey:VenueID"`
+	MaterializedUntil time.Time `gorm:"column:materialized_until;not null"`
+	// Finished is set once every occurrence of a bounded rule exists.
+	Finished 		bool 		`gorm:"column:finished;not null"`
+}
+
+func (s *EventSeries) TableName() string {
+	return "event_series"
+}
+
+// Location is the time zone the series' rule is expanded in, that of its
+// venue, so occurrences keep their wall clock time across DST changes.
+func (s *EventSeries) Location() *time.Location {
+	return s.Venue.Location()
+}
+
+// Venue is a place events are held at. TimeZone is an IANA zone name; the
+// times of its events are stored in UTC and read in that zone. Capacity is
+// what an event at the venue seats unless it says otherwise.
+type Venue struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	Name 			string 		`gorm:"column:name;size:256;not null"`
+	Address 		string 		`gorm:"column:address;size:512;not null"`
+	TimeZone 		string 		`gorm:"column:time_zone;size:64;not null"`
+	Capacity 		uint64 		`gorm:"column:capacity;not null"`
+	Latitude 		*float64 	`gorm:"column:latitude"`
+	Longitude 		*float64 	`gorm:"column:longitude"`
+	OwnerID 		uint 		`gorm:"column:owner_id;not null;index"`
+	CreatedAt 		time.Time
+	UpdatedAt 		time.Time
+}
+
+func (v *Venue) TableName() string {
+	return "venues"
+}
+
+// Location is the time zone of the venue. Without a venue, or with a zone
+// this system does not know, it is UTC.
+func (v *Venue) Location() *time.Location {
+	if v == nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(v.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ProcessedMessage records a consumed message that changed state, so the
+// change is not applied again when the message is redelivered.
+type ProcessedMessage struct {
+	MessageID 		string 		`gorm:"column:message_id;type:char(36);primaryKey"`
+	ProcessedAt 	time.Time 	`gorm:"column:processed_at;autoCreateTime"`
+}
+
+func (p *ProcessedMessage) TableName() string {
+	return "processed_messages"
 }
\ No newline at end of file
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/anrisys/quicket/event-service/pkg/outbox"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepositoryInterface interface {
@@ -16,6 +19,64 @@ type EventRepositoryInterface interface {
 	FindByTitle(ctx context.Context, title string) (*Event, error)
 	FindByID(ctx context.Context, id uint) (*Event, error)
 	FindByPublicID(ctx context.Context, publicID string) (*Event, error)
+	AddTicketType(ctx context.Context, eventID uint, tt *TicketType) error
+	UpdateTicketType(ctx context.Context, eventID uint, publicID string, update TicketTypeUpdate) (*TicketType, error)
+	Update(ctx context.Context, eventID uint, update EventUpdate) (*Event, error)
+	Delete(ctx context.Context, eventID uint) (*Event, error)
+	List(ctx context.Context, filter EventFilter) ([]Event, error)
+	ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) (*Event, error)
+	FindVenueByPublicID(ctx context.Context, publicID string) (*Venue, error)
+}
+
+// Orders of List. The start date orders use idx_events_start_date; the
+// creation orders follow the id.
+const (
+	SortStartDate = "start_date"
+	SortStartDateDesc = "-start_date"
+	SortCreatedAt = "created_at"
+	SortCreatedAtDesc = "-created_at"
+)
+
+// EventFilter narrows the events returned by List. Zero fields are not
+// filtered on. AfterStartDate and AfterID are the position of the last event
+// of the previous page in the order of Sort; a zero AfterID starts from the
+// first event. SeriesPublicID keeps the occurrences of that series.
+type EventFilter struct {
+	Keyword string
+	From time.Time
+	To time.Time
+	Available bool
+	OrganizerID uint
+	SeriesPublicID string
+	Sort string
+	AfterStartDate time.Time
+	AfterID uint
+	Limit int
+}
+
+// EventUpdate holds the fields of an event to change; nil fields are left
+// as they are.
+type EventUpdate struct {
+	Title *string
+	Description *string
+	StartDate *time.Time
+	EndDate *time.Time
+	MaxSeats *uint64
+	Venue *Venue
+}
+
+// TicketTypeUpdate holds the fields of a ticket type to change; nil fields
+// are left as they are.
+type TicketTypeUpdate struct {
+	Name *string
+	Price *float32
+	Quota *uint64
+	MaxPerOrder *uint
+}
+
+type eventSeatsRow struct {
+	ID uint
+	MaxSeats uint64
 }
 
 type EventRepository struct {
@@ -30,8 +91,16 @@ func NewEventRepository(db *gorm.DB, logger zerolog.Logger) *EventRepository {
 	}
 }
 
+// Create stores the event with its ticket types and queues an
+// event.created message in the same transaction.
 func (r *EventRepository) Create(ctx context.Context, event *Event) (*Event, error) {
-	err := r.db.WithContext(ctx).Create(event).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event.Version = 1
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		return r.enqueue(tx, RoutingKeyEventCreated, newEventCreatedMessage(event))
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return nil, errs.NewConflictError("event already exists")
@@ -44,9 +113,11 @@ func (r *EventRepository) Create(ctx context.Context, event *Event) (*Event, err
 	return event, nil
 }
 
+// FindByTitle finds the standalone event with the title. Occurrences of a
+// series share the title of their series and are left out.
 func (r *EventRepository) FindByTitle(ctx context.Context, title string) (*Event, error) {
 	event := &Event{}
-	err := r.db.WithContext(ctx).Take(event, "title = ?", title).Error
+	err := r.db.WithContext(ctx).Take(event, "title = ? AND series_id IS NULL", title).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.NewErrNotFound("event")
@@ -61,7 +132,7 @@ func (r *EventRepository) FindByTitle(ctx context.Context, title string) (*Event
 
 func (r *EventRepository) FindByID(ctx context.Context, id uint) (*Event, error) {
 	event := &Event{}
-	err := r.db.WithContext(ctx).First(event, id).Error
+	err := r.db.WithContext(ctx).Preload("TicketTypes").Preload("Venue").First(event, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.NewErrNotFound("event")
@@ -76,7 +147,7 @@ func (r *EventRepository) FindByID(ctx context.Context, id uint) (*Event, error)
 
 func (r *EventRepository) FindByPublicID(ctx context.Context, publicID string) (*Event, error) {
 	event := &Event{}
-	err := r.db.WithContext(ctx).Take(event, "public_id = ?", publicID).Error
+	err := r.db.WithContext(ctx).Preload("TicketTypes").Preload("Venue").Take(event, "public_id = ?", publicID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.NewErrNotFound("event")
@@ -89,8 +160,496 @@ func (r *EventRepository) FindByPublicID(ctx context.Context, publicID string) (
 	return event, nil
 }
 
+// List returns the events matching filter, with their ticket types, in the
+// order of filter.Sort, start date first by default. Keyword is matched
+// against the title and the description; From and To bound the start and
+// end dates.
+func (r *EventRepository) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	q := r.db.WithContext(ctx).Model(&Event{}).Preload("TicketTypes").Preload("Venue")
+
+	if filter.Keyword != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Keyword) + "%"
+		q = q.Where("(title LIKE ? OR description LIKE ?)", pattern, pattern)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("start_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("end_date <= ?", filter.To)
+	}
+	if filter.Available {
+		q = q.Where("available_seats > 0")
+	}
+	if filter.OrganizerID > 0 {
+		q = q.Where("organizer_id = ?", filter.OrganizerID)
+	}
+	if filter.SeriesPublicID != "" {
+		q = q.Where("series_id IN (?)", r.db.Model(&EventSeries{}).
+			Select("id").
+			Where("public_id = ?", filter.SeriesPublicID))
+	}
+
+	switch filter.Sort {
+	case SortStartDateDesc:
+		if filter.AfterID > 0 {
+			q = q.Where("(start_date < ? OR (start_date = ? AND id < ?))",
+				filter.AfterStartDate, filter.AfterStartDate, filter.AfterID)
+		}
+		q = q.Order("start_date DESC, id DESC")
+	case SortCreatedAt:
+		if filter.AfterID > 0 {
+			q = q.Where("id > ?", filter.AfterID)
+		}
+		q = q.Order("id")
+	case SortCreatedAtDesc:
+		if filter.AfterID > 0 {
+			q = q.Where("id < ?", filter.AfterID)
+		}
+		q = q.Order("id DESC")
+	default:
+		if filter.AfterID > 0 {
+			q = q.Where("(start_date > ? OR (start_date = ? AND id > ?))",
+				filter.AfterStartDate, filter.AfterStartDate, filter.AfterID)
+		}
+		q = q.Order("start_date, id")
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var events []Event
+	if err := q.Find(&events).Error; err != nil {
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("keyword", filter.Keyword).
+			Uint("organizer_id", filter.OrganizerID).
+			Msg("list events failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return events, nil
+}
+
+// AddTicketType adds a tier to the event. The event row is locked so
+// concurrent changes can not push the sum of quotas above MaxSeats.
+func (r *EventRepository) AddTicketType(ctx context.Context, eventID uint, tt *TicketType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEventSeats(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		allocated, err := r.allocatedQuota(tx, eventID, 0)
+		if err != nil {
+			return err
+		}
+		if allocated+tt.Quota > ev.MaxSeats {
+			return ErrQuotaExceedsSeats
+		}
+
+		tt.EventID = eventID
+		tt.Available = tt.Quota
+		if err := tx.Create(tt).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("insert ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return r.announceUpdate(tx, eventID)
+	})
+}
+
+// UpdateTicketType applies update to a tier of the event. Changing the quota
+// moves Available by the same amount and is refused when fewer seats than
+// already sold would remain.
+func (r *EventRepository) UpdateTicketType(ctx context.Context, eventID uint, publicID string, update TicketTypeUpdate) (*TicketType, error) {
+	var tt TicketType
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEventSeats(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ? AND event_id = ?", publicID, eventID).
+			Take(&tt).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTicketTypeNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("ticket_type_public_id", publicID).
+				Msg("lock/select ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if update.Name != nil {
+			tt.Name = *update.Name
+		}
+		if update.Price != nil {
+			tt.Price = *update.Price
+		}
+		if update.MaxPerOrder != nil {
+			tt.MaxPerOrder = *update.MaxPerOrder
+		}
+		if update.Quota != nil {
+			sold := tt.Quota - tt.Available
+			if *update.Quota < sold {
+				return ErrQuotaBelowSold
+			}
+
+			allocated, err := r.allocatedQuota(tx, eventID, tt.ID)
+			if err != nil {
+				return err
+			}
+			if allocated+*update.Quota > ev.MaxSeats {
+				return ErrQuotaExceedsSeats
+			}
+
+			tt.Quota = *update.Quota
+			tt.Available = *update.Quota - sold
+		}
+
+		if uint64(tt.MaxPerOrder) > tt.Quota {
+			return ErrMaxPerOrderAboveQuota
+		}
+
+		if err := tx.Save(&tt).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("ticket_type_id", tt.ID).
+				Msg("update ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return r.announceUpdate(tx, eventID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tt, nil
+}
+
+// Update applies update to the event. The event row is locked so seats can
+// not be taken while MaxSeats changes: it can not drop below the seats
+// already sold nor below the quotas of the ticket types.
+func (r *EventRepository) Update(ctx context.Context, eventID uint, update EventUpdate) (*Event, error) {
+	var ev Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockEvent(tx, eventID, &ev); err != nil {
+			return err
+		}
+		return r.applyUpdate(tx, &ev, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// applyUpdate changes the event, locked by the caller with its ticket
+// types, saves it and queues event.updated.
+func (r *EventRepository) applyUpdate(tx *gorm.DB, ev *Event, update EventUpdate) error {
+	if update.Title != nil {
+		ev.Title = *update.Title
+	}
+	if update.Description != nil {
+		ev.Description = update.Description
+	}
+	if update.StartDate != nil {
+		ev.StartDate = *update.StartDate
+	}
+	if update.EndDate != nil {
+		ev.EndDate = *update.EndDate
+	}
+	if ev.EndDate.Before(ev.StartDate) {
+		return ErrInvalidDates
+	}
+	if update.Venue != nil {
+		ev.VenueID = &update.Venue.ID
+		ev.Venue = update.Venue
+	}
+
+	if update.MaxSeats != nil {
+		sold := ev.MaxSeats - ev.AvailableSeats
+		if *update.MaxSeats < sold {
+			return ErrMaxSeatsBelowSold
+		}
+
+		allocated, err := r.allocatedQuota(tx, ev.ID, 0)
+		if err != nil {
+			return err
+		}
+		if allocated > *update.MaxSeats {
+			return ErrQuotaExceedsSeats
+		}
+
+		ev.MaxSeats = *update.MaxSeats
+		ev.AvailableSeats = *update.MaxSeats - sold
+	}
+
+	ev.Version++
+	if err := tx.Omit(clause.Associations).Save(ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errs.NewConflictError("event with this title already exists")
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", ev.ID).
+			Msg("update event failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return r.enqueue(tx, RoutingKeyEventUpdated, EventUpdatedMessage{newEventCreatedMessage(ev)})
+}
+
+// Delete removes the event. An event that has sold seats is soft-deleted so
+// the bookings made for it can still be resolved; one without is removed
+// together with its ticket types.
+func (r *EventRepository) Delete(ctx context.Context, eventID uint) (*Event, error) {
+	var ev Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockEvent(tx, eventID, &ev); err != nil {
+			return err
+		}
+
+		del := tx
+		if ev.AvailableSeats == ev.MaxSeats {
+			del = tx.Unscoped()
+		}
+		if err := del.Delete(&ev).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("delete event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return r.enqueue(tx, RoutingKeyEventDeleted, EventDeletedMessage{EventID: eventID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// ApplySeatsDelta moves the available seats of the event, and of its
+// ticket type when set, by the delta of msg. messageID is recorded in the
+// same transaction, so a delta that was applied already is refused with
+// ErrDuplicateMessage, as is one whose version the event is past. A change
+// that skips a version is refused with ErrSeatsVersionGap rather than
+// applied out of order. Deleted events keep counting seats, since their
+// bookings can still be cancelled. The change bumps the version and queues
+// event.seats.updated.
+func (r *EventRepository) ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) (*Event, error) {
+	eventID, ticketTypeID, delta := msg.EventID, msg.TicketTypeID, msg.Delta
+	var ev Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedMessage{MessageID: messageID})
+		if res.Error != nil {
+			r.logger.Error().Err(res.Error).
+				Str("message_id", messageID).
+				Msg("record processed message failed")
+			return fmt.Errorf("%w: %v", ErrDB, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrDuplicateMessage
+		}
+
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&ev, eventID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("lock/select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if err := checkSeatsVersion(ev.BookingSeatsVersion, msg.Version); err != nil {
+			return err
+		}
+
+		available, ok := shiftSeats(ev.AvailableSeats, delta, ev.MaxSeats)
+		if !ok {
+			return ErrSeatsOutOfRange
+		}
+
+		if ticketTypeID != nil {
+			var tt TicketType
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND event_id = ?", *ticketTypeID, eventID).
+				Take(&tt).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrTicketTypeNotFound
+				}
+				r.logger.Error().Err(err).
+					Uint("ticket_type_id", *ticketTypeID).
+					Msg("lock/select ticket type failed")
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+
+			ttAvailable, ok := shiftSeats(tt.Available, delta, tt.Quota)
+			if !ok {
+				return ErrSeatsOutOfRange
+			}
+			if err := tx.Model(&tt).UpdateColumn("available", ttAvailable).Error; err != nil {
+				r.logger.Error().Err(err).
+					Uint("ticket_type_id", tt.ID).
+					Msg("update ticket type seats failed")
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+		}
+
+		// The version guard repeats the check above in the statement itself.
+		res = tx.Table("events").
+			Where("id = ? AND booking_seats_version = ?", eventID, msg.Version-1).
+			UpdateColumns(map[string]any{
+				"available_seats":       available,
+				"version":               ev.Version + 1,
+				"booking_seats_version": msg.Version,
+			})
+		if res.Error != nil {
+			r.logger.Error().Err(res.Error).
+				Uint("event_id", eventID).
+				Msg("update event seats failed")
+			return fmt.Errorf("%w: %v", ErrDB, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrSeatsVersionGap
+		}
+		ev.AvailableSeats = available
+		ev.Version++
+		ev.BookingSeatsVersion = msg.Version
+
+		return r.enqueue(tx, RoutingKeyEventSeatsUpdated, EventSeatsUpdatedMessage{
+			EventID:        ev.ID,
+			AvailableSeats: ev.AvailableSeats,
+			Version:        ev.Version,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// announceUpdate bumps the version of the event and queues an
+// event.updated message carrying the event as it stands in tx.
+func (r *EventRepository) announceUpdate(tx *gorm.DB, eventID uint) error {
+	if err := tx.Model(&Event{}).
+		Where("id = ?", eventID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("bump event version failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	var ev Event
+	if err := tx.Preload("TicketTypes").Take(&ev, eventID).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("select event failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return r.enqueue(tx, RoutingKeyEventUpdated, EventUpdatedMessage{newEventCreatedMessage(&ev)})
+}
+
+// enqueue adds a message about an event to the outbox within tx.
+func (r *EventRepository) enqueue(tx *gorm.DB, routingKey string, payload any) error {
+	if err := outbox.Add(tx, EventsExchange, routingKey, payload); err != nil {
+		r.logger.Error().Err(err).
+			Str("routing_key", routingKey).
+			Msg("queue event message failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// lockEvent loads the event with its ticket types into ev and locks the
+// event row.
+func (r *EventRepository) lockEvent(tx *gorm.DB, eventID uint, ev *Event) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("TicketTypes").
+		Preload("Venue").
+		Take(ev, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("lock/select event failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func (r *EventRepository) lockEventSeats(tx *gorm.DB, eventID uint) (*eventSeatsRow, error) {
+	var ev eventSeatsRow
+	if err := tx.Table("events").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, max_seats").
+		Where("id = ? AND deleted_at IS NULL", eventID).
+		Take(&ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("lock/select event failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &ev, nil
+}
+
+// allocatedQuota sums the quotas of the event's tiers, leaving out the tier
+// with id exclude.
+func (r *EventRepository) allocatedQuota(tx *gorm.DB, eventID, exclude uint) (uint64, error) {
+	var total uint64
+	if err := tx.Model(&TicketType{}).
+		Select("COALESCE(SUM(quota), 0)").
+		Where("event_id = ? AND id <> ?", eventID, exclude).
+		Scan(&total).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("sum ticket type quotas failed")
+		return 0, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return total, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func isConnectionError(err error) bool {
 	// Implement proper connection error detection
 	return strings.Contains(err.Error(), "connection refused") ||
 		errors.Is(err, context.DeadlineExceeded)
+}
+
+// checkSeatsVersion tells whether a seat change numbered version comes right
+// after the last one applied, current. An older change was applied already;
+// a newer one means one in between is missing.
+func checkSeatsVersion(current, version uint) error {
+	switch {
+	case version <= current:
+		return ErrDuplicateMessage
+	case version != current+1:
+		return ErrSeatsVersionGap
+	}
+	return nil
+}
+
+// shiftSeats moves seats by delta and reports whether the result stays
+// between zero and limit.
+func shiftSeats(seats uint64, delta int64, limit uint64) (uint64, bool) {
+	if delta < 0 {
+		taken := uint64(-delta)
+		if taken > seats {
+			return 0, false
+		}
+		return seats - taken, true
+	}
+	shifted := seats + uint64(delta)
+	if shifted > limit {
+		return 0, false
+	}
+	return shifted, true
 }
\ No newline at end of file
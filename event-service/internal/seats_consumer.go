@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anrisys/quicket/event-service/pkg/mq/rabbitmq"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+const (
+	seatsQueueName             = "event-service.bookings.seats"
+	bookingsDeadLetterExchange = "bookings.dlx"
+)
+
+// SeatsConsumer applies the seat changes of booking-service to the events.
+type SeatsConsumer struct {
+	consumer *rabbitmq.Consumer
+	srv      EventServiceInterface
+	logger   zerolog.Logger
+}
+
+func NewSeatsConsumer(consumer *rabbitmq.Consumer, srv EventServiceInterface, logger zerolog.Logger) *SeatsConsumer {
+	return &SeatsConsumer{
+		consumer: consumer,
+		srv:      srv,
+		logger:   logger,
+	}
+}
+
+// Start consumes the seat changes until ctx is cancelled.
+func (c *SeatsConsumer) Start(ctx context.Context) error {
+	return c.consumer.Consume(ctx, rabbitmq.Subscription{
+		Exchange:           BookingsExchange,
+		Queue:              seatsQueueName,
+		RoutingKeys:        []string{RoutingKeyBookingSeatsUpdated},
+		DeadLetterExchange: bookingsDeadLetterExchange,
+	}, c.handleMessage)
+}
+
+// handleMessage acks a change once applied and requeues it when applying
+// failed for a reason that may pass. A change that can never be applied is
+// dead-lettered, and so is one that skips a version: the changes after it
+// follow it to the dead letter queue, where they can be replayed in order
+// once the missing one is found.
+func (c *SeatsConsumer) handleMessage(msg amqp.Delivery) {
+	log := c.logger.With().
+		Str("routing_key", msg.RoutingKey).
+		Str("message_id", msg.MessageId).
+		Logger()
+
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error().Interface("error", err).Msg("panic during message processing")
+			msg.Nack(false, false)
+		}
+	}()
+
+	if msg.RoutingKey != RoutingKeyBookingSeatsUpdated {
+		log.Warn().Msg("unknown routing key, acknowledging and ignoring")
+		msg.Ack(false)
+		return
+	}
+
+	// The message id is what makes applying a delta idempotent.
+	if msg.MessageId == "" {
+		log.Error().Msg("seats update without message id")
+		msg.Nack(false, false)
+		return
+	}
+
+	var seatsMsg BookingSeatsUpdatedMessage
+	if err := json.Unmarshal(msg.Body, &seatsMsg); err != nil {
+		log.Error().Err(err).Msg("failed to unmarshal seats update")
+		msg.Nack(false, false)
+		return
+	}
+
+	err := c.srv.ApplySeatsDelta(context.Background(), msg.MessageId, &seatsMsg)
+	switch {
+	case err == nil:
+		msg.Ack(false)
+	case errors.Is(err, ErrEventNotFound),
+		errors.Is(err, ErrTicketTypeNotFound),
+		errors.Is(err, ErrSeatsOutOfRange),
+		errors.Is(err, ErrSeatsVersionGap):
+		log.Error().Err(err).
+			Uint("event_id", seatsMsg.EventID).
+			Int64("delta", seatsMsg.Delta).
+			Uint("version", seatsMsg.Version).
+			Msg("seats update can not be applied")
+		msg.Nack(false, false)
+	default:
+		log.Error().Err(err).Msg("failed to apply seats update")
+		msg.Nack(false, true)
+	}
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+// acknowledger records how a delivery was settled.
+type acknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// seatsService stands in for the event service; err is what applying a
+// delta returns.
+type seatsService struct {
+	EventServiceInterface
+	err       error
+	messageID string
+	msg       *BookingSeatsUpdatedMessage
+}
+
+func (s *seatsService) ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) error {
+	s.messageID = messageID
+	s.msg = msg
+	return s.err
+}
+
+func TestSeatsConsumer_HandleMessage(t *testing.T) {
+	body := `{"event_id":1,"delta":-2,"version":3}`
+
+	tests := []struct {
+		name       string
+		routingKey string
+		messageID  string
+		body       string
+		srvErr     error
+		wantAck    bool
+		wantNack   bool
+		requeue    bool
+	}{
+		{name: "applied", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, wantAck: true},
+		{name: "unknown routing key", routingKey: "booking.created", messageID: "m-1", body: body, wantAck: true},
+		{name: "without message id", routingKey: RoutingKeyBookingSeatsUpdated, body: body, wantNack: true},
+		{name: "undecodable body", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: `{"event_id":`, wantNack: true},
+		{name: "unknown event", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, srvErr: ErrEventNotFound, wantNack: true},
+		{name: "unknown ticket type", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, srvErr: ErrTicketTypeNotFound, wantNack: true},
+		{name: "seats out of range", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, srvErr: ErrSeatsOutOfRange, wantNack: true},
+		{name: "version gap", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, srvErr: ErrSeatsVersionGap, wantNack: true},
+		{name: "database error", routingKey: RoutingKeyBookingSeatsUpdated, messageID: "m-1", body: body, srvErr: ErrDB, wantNack: true, requeue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ack := &acknowledger{}
+			srv := &seatsService{err: tt.srvErr}
+			c := NewSeatsConsumer(nil, srv, zerolog.Nop())
+
+			c.handleMessage(amqp.Delivery{
+				Acknowledger: ack,
+				RoutingKey:   tt.routingKey,
+				MessageId:    tt.messageID,
+				Body:         []byte(tt.body),
+			})
+
+			if ack.acked != tt.wantAck || ack.nacked != tt.wantNack {
+				t.Fatalf("acked = %v, nacked = %v, want %v, %v", ack.acked, ack.nacked, tt.wantAck, tt.wantNack)
+			}
+			if ack.nacked && ack.requeue != tt.requeue {
+				t.Errorf("requeue = %v, want %v", ack.requeue, tt.requeue)
+			}
+			if srv.msg != nil && (srv.messageID != tt.messageID || srv.msg.EventID != 1 || srv.msg.Delta != -2 || srv.msg.Version != 3) {
+				t.Errorf("applied %q %+v", srv.messageID, srv.msg)
+			}
+		})
+	}
+}
+
+func TestEventService_ApplySeatsDelta(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "applied"},
+		{name: "already processed message is not an error", repoErr: ErrDuplicateMessage},
+		{name: "version gap", repoErr: ErrSeatsVersionGap, wantErr: ErrSeatsVersionGap},
+		{name: "database error", repoErr: ErrDB, wantErr: ErrDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &eventRepo{seatsErr: tt.repoErr}
+			srv := NewEventService(repo, testUsers, zerolog.Nop(), closedRedis(t))
+
+			err := srv.ApplySeatsDelta(context.Background(), "m-1", &BookingSeatsUpdatedMessage{EventID: 1, Delta: -2, Version: 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplySeatsDelta() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckSeatsVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		current uint
+		version uint
+		want    error
+	}{
+		{name: "first change", current: 0, version: 1},
+		{name: "next change", current: 4, version: 5},
+		{name: "same change again", current: 5, version: 5, want: ErrDuplicateMessage},
+		{name: "older change", current: 5, version: 2, want: ErrDuplicateMessage},
+		{name: "change missing in between", current: 5, version: 7, want: ErrSeatsVersionGap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSeatsVersion(tt.current, tt.version); !errors.Is(err, tt.want) {
+				t.Errorf("checkSeatsVersion(%d, %d) = %v, want %v", tt.current, tt.version, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftSeats(t *testing.T) {
+	tests := []struct {
+		name   string
+		seats  uint64
+		delta  int64
+		limit  uint64
+		want   uint64
+		wantOK bool
+	}{
+		{name: "take", seats: 10, delta: -3, limit: 10, want: 7, wantOK: true},
+		{name: "take the last", seats: 3, delta: -3, limit: 10, want: 0, wantOK: true},
+		{name: "take more than available", seats: 2, delta: -3, limit: 10},
+		{name: "give back", seats: 7, delta: 3, limit: 10, want: 10, wantOK: true},
+		{name: "give back beyond the limit", seats: 8, delta: 3, limit: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := shiftSeats(tt.seats, tt.delta, tt.limit)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("shiftSeats(%d, %d, %d) = %d, %v, want %d, %v", tt.seats, tt.delta, tt.limit, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
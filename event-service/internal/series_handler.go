@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type SeriesHandler struct {
+	SeriesService SeriesServiceInterface
+	logger        zerolog.Logger
+}
+
+func NewSeriesHandler(seriesService SeriesServiceInterface, logger zerolog.Logger) *SeriesHandler {
+	return &SeriesHandler{
+		SeriesService: seriesService,
+		logger:        logger,
+	}
+}
+
+// Create godoc
+// @Summary Create event series
+// @Description Create a recurring event (Admin/Organizer only). Its occurrences are created as events ahead of time and listed with GET /api/v1/events?series=.
+// @Tags Events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateEventSeriesRequest true "Event series data"
+// @Success 201 {object} EventSeriesSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden (role restriction)"
+// @Router /api/v1/events/series [post]
+func (h *SeriesHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var req CreateEventSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid event series data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	series, err := h.SeriesService.Create(ctx, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := EventSeriesSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Event series created successfully",
+		},
+		Series: *series,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Get godoc
+// @Summary Get event series
+// @Description Get a recurring event
+// @Tags Events - Public
+// @Produce json
+// @Param publicID path string true "Event series public ID"
+// @Success 200 {object} EventSeriesSuccessResponse
+// @Failure 404 {object} errs.ErrorResponse "Event series not found"
+// @Router /api/v1/events/series/{publicID} [get]
+func (h *SeriesHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	series, err := h.SeriesService.FindByPublicID(ctx, c.Param("publicID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := EventSeriesSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Event series retrieved successfully",
+		},
+		Series: *series,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List event series
+// @Description Page through recurring events in creation order; pass next_cursor back as cursor for the next page.
+// @Tags Events - Public
+// @Produce json
+// @Param organizer query string false "Organizer public ID"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} ListEventSeriesSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Router /api/v1/events/series [get]
+func (h *SeriesHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query ListEventSeriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.SeriesService.List(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListEventSeriesSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Event series retrieved successfully",
+		},
+		EventSeriesListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
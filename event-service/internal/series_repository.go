@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeriesRepositoryInterface interface {
+	CreateSeries(ctx context.Context, series *EventSeries) error
+	FindSeriesByID(ctx context.Context, id uint) (*EventSeries, error)
+	FindSeriesByPublicID(ctx context.Context, publicID string) (*EventSeries, error)
+	ListSeries(ctx context.Context, filter SeriesFilter) ([]EventSeries, error)
+	ListDueSeries(ctx context.Context, until time.Time, afterID uint, limit int) ([]EventSeries, error)
+	ListOccurrences(ctx context.Context, seriesID uint, from time.Time) ([]Event, error)
+	AddOccurrences(ctx context.Context, series *EventSeries, occurrences []Event, until time.Time, finished bool) (int, error)
+	SplitSeries(ctx context.Context, split SeriesSplit) error
+}
+
+// SeriesFilter narrows the series returned by ListSeries, which orders them
+// by id. A zero AfterID starts from the first series.
+type SeriesFilter struct {
+	OrganizerID uint
+	AfterID uint
+	Limit int
+}
+
+// OccurrenceMove hands an occurrence over to the series that continues a
+// split, where the rule gives it OccurrenceStart, and applies Update to it.
+// Update is nil for an occurrence that was soft-deleted.
+type OccurrenceMove struct {
+	EventID uint
+	OccurrenceStart time.Time
+	Update *EventUpdate
+}
+
+// SeriesSplit is a "this and following" edit of a series. The occurrences
+// before the edited one stay with Series under KeepRRule and Next takes over
+// the rest. When KeepRRule is empty the edited occurrence is the first one,
+// and Series takes the fields of Next instead. Moves are ordered so that no
+// two occurrences of a series ever share an occurrence start.
+type SeriesSplit struct {
+	Series *EventSeries
+	KeepRRule string
+	Next *EventSeries
+	Moves []OccurrenceMove
+}
+
+func (r *EventRepository) CreateSeries(ctx context.Context, series *EventSeries) error {
+	if err := r.db.WithContext(ctx).Create(series).Error; err != nil {
+		if isConnectionError(err) {
+			return errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("series_public_id", series.PublicID).
+			Msg("insert event series failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func (r *EventRepository) FindSeriesByID(ctx context.Context, id uint) (*EventSeries, error) {
+	return r.findSeries(ctx, "id = ?", id)
+}
+
+func (r *EventRepository) FindSeriesByPublicID(ctx context.Context, publicID string) (*EventSeries, error) {
+	return r.findSeries(ctx, "public_id = ?", publicID)
+}
+
+func (r *EventRepository) findSeries(ctx context.Context, query string, arg any) (*EventSeries, error) {
+	series := &EventSeries{}
+	if err := r.db.WithContext(ctx).Preload("Venue").Take(series, query, arg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Interface("key", arg).
+			Msg("select event series failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return series, nil
+}
+
+func (r *EventRepository) ListSeries(ctx context.Context, filter SeriesFilter) ([]EventSeries, error) {
+	q := r.db.WithContext(ctx).Model(&EventSeries{}).Preload("Venue")
+	if filter.OrganizerID > 0 {
+		q = q.Where("organizer_id = ?", filter.OrganizerID)
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("id > ?", filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var series []EventSeries
+	if err := q.Order("id").Find(&series).Error; err != nil {
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Uint("organizer_id", filter.OrganizerID).
+			Msg("list event series failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return series, nil
+}
+
+// ListDueSeries returns, in id order, up to limit series after afterID that
+// still have occurrences to create before until.
+func (r *EventRepository) ListDueSeries(ctx context.Context, until time.Time, afterID uint, limit int) ([]EventSeries, error) {
+	var series []EventSeries
+	if err := r.db.WithContext(ctx).
+		Preload("Venue").
+		Where("finished = ? AND materialized_until < ? AND id > ?", false, until, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&series).Error; err != nil {
+		r.logger.Error().Err(err).
+			Time("until", until).
+			Msg("list due event series failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return series, nil
+}
+
+// ListOccurrences returns the occurrences of the series whose rule start is
+// from or later, soft-deleted ones included, in the order of the rule.
+func (r *EventRepository) ListOccurrences(ctx context.Context, seriesID uint, from time.Time) ([]Event, error) {
+	var events []Event
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("series_id = ? AND occurrence_start >= ?", seriesID, from).
+		Order("occurrence_start").
+		Find(&events).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("series_id", seriesID).
+			Msg("list occurrences failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return events, nil
+}
+
+// AddOccurrences creates the occurrences of the series up to until, queues
+// event.created for each and moves the watermark of the series there.
+// Occurrences that already exist are skipped, so two replicas materializing
+// the same series do not clash. ErrSeriesChanged is
+// returned when the series was edited since it was read; the occurrences
+// are then left for the next run.
+func (r *EventRepository) AddOccurrences(ctx context.Context, series *EventSeries, occurrences []Event, until time.Time, finished bool) (int, error) {
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Taking the series row first orders this with SplitSeries.
+		res := tx.Model(&EventSeries{}).
+			Where("id = ? AND materialized_until = ? AND rrule = ?", series.ID, series.MaterializedUntil, series.RRule).
+			Updates(map[string]any{
+				"materialized_until": until,
+				"finished": finished,
+			})
+		if res.Error != nil {
+			r.logger.Error().Err(res.Error).
+				Uint("series_id", series.ID).
+				Msg("move series watermark failed")
+			return fmt.Errorf("%w: %v", ErrDB, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrSeriesChanged
+		}
+
+		for i := range occurrences {
+			occurrences[i].Version = 1
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&occurrences[i])
+			if res.Error != nil {
+				r.logger.Error().Err(res.Error).
+					Uint("series_id", series.ID).
+					Time("occurrence_start", occurrences[i].StartDate).
+					Msg("insert occurrence failed")
+				return fmt.Errorf("%w: %v", ErrDB, res.Error)
+			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+			created++
+			if err := r.enqueue(tx, RoutingKeyEventCreated, newEventCreatedMessage(&occurrences[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	series.MaterializedUntil = until
+	series.Finished = finished
+	return created, nil
+}
+
+// SplitSeries applies split in one transaction: either every following
+// occurrence takes the edit, and is announced with event.updated, or none
+// does.
+func (r *EventRepository) SplitSeries(ctx context.Context, split SeriesSplit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var series EventSeries
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&series, split.Series.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSeriesNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("series_id", split.Series.ID).
+				Msg("lock/select event series failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if series.RRule != split.Series.RRule || !series.MaterializedUntil.Equal(split.Series.MaterializedUntil) {
+			return ErrSeriesChanged
+		}
+
+		target := split.Next
+		if split.KeepRRule == "" {
+			series.Title = split.Next.Title
+			series.Description = split.Next.Description
+			series.RRule = split.Next.RRule
+			series.StartDate = split.Next.StartDate
+			series.EndDate = split.Next.EndDate
+			series.MaxSeats = split.Next.MaxSeats
+			series.VenueID = split.Next.VenueID
+			series.MaterializedUntil = split.Next.MaterializedUntil
+			series.Finished = false
+			target = &series
+		} else {
+			if err := tx.Omit("Venue").Create(split.Next).Error; err != nil {
+				r.logger.Error().Err(err).
+					Uint("series_id", series.ID).
+					Msg("insert split event series failed")
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+			series.RRule = split.KeepRRule
+		}
+		if err := tx.Save(&series).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("series_id", series.ID).
+				Msg("update event series failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		for _, move := range split.Moves {
+			if err := tx.Unscoped().Model(&Event{}).
+				Where("id = ?", move.EventID).
+				Updates(map[string]any{
+					"series_id": target.ID,
+					"occurrence_start": move.OccurrenceStart,
+				}).Error; err != nil {
+				r.logger.Error().Err(err).
+					Uint("event_id", move.EventID).
+					Uint("series_id", target.ID).
+					Msg("move occurrence failed")
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+			if move.Update == nil {
+				continue
+			}
+
+			var ev Event
+			if err := r.lockEvent(tx, move.EventID, &ev); err != nil {
+				return err
+			}
+			if err := r.applyUpdate(tx, &ev, *move.Update); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
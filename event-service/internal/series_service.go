@@ -0,0 +1,389 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/anrisys/quicket/event-service/pkg/rrule"
+	"github.com/anrisys/quicket/event-service/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+type SeriesServiceInterface interface {
+	Create(ctx context.Context, req *CreateEventSeriesRequest, userPublicID string) (*EventSeriesDTO, error)
+	FindByPublicID(ctx context.Context, publicID string) (*EventSeriesDTO, error)
+	List(ctx context.Context, query *ListEventSeriesQuery) (*EventSeriesListDTO, error)
+	UpdateFollowing(ctx context.Context, eventPublicID string, req *UpdateEventRequest, userPublicID string) (*EventDTO, error)
+}
+
+type SeriesService struct {
+	repo         SeriesRepositoryInterface
+	events       *EventService
+	users        UserReader
+	materializer *Materializer
+	logger       zerolog.Logger
+}
+
+func NewSeriesService(repo SeriesRepositoryInterface, events *EventService, users UserReader, materializer *Materializer, logger zerolog.Logger) *SeriesService {
+	return &SeriesService{
+		repo:         repo,
+		events:       events,
+		users:        users,
+		materializer: materializer,
+		logger:       logger,
+	}
+}
+
+// Create stores the series and creates its occurrences within the horizon
+// right away. Should that fail, the materializer catches up later.
+func (s *SeriesService) Create(ctx context.Context, req *CreateEventSeriesRequest, userPublicID string) (*EventSeriesDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("event series service#create: %w", err)
+	}
+
+	rule, err := rrule.Parse(req.RRule)
+	if err != nil {
+		return nil, errs.NewValidationError(err.Error())
+	}
+
+	venue, err := s.events.findVenue(ctx, req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	maxSeats, err := maxSeatsAt(req.MaxSeats, venue)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStartDate(req.StartDate, venue.Location(), time.Now()); err != nil {
+		return nil, err
+	}
+
+	start := req.StartDate.In(venue.Location()).Truncate(time.Second)
+	end := req.EndDate.Truncate(time.Second)
+	if !isFirstOccurrence(rule, start) {
+		return nil, errs.NewValidationError("start date must be the first occurrence of the recurrence rule")
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public ID: %w", err)
+	}
+
+	series := &EventSeries{
+		PublicID: publicID,
+		Title: req.Title,
+		Description: &req.Description,
+		OrganizerID: uint(usr.ID),
+		RRule: rule.String(),
+		StartDate: start.UTC(),
+		EndDate: end.UTC(),
+		MaxSeats: maxSeats,
+		MaterializedUntil: start.UTC(),
+	}
+	if venue != nil {
+		series.VenueID = &venue.ID
+	}
+	if err := s.repo.CreateSeries(ctx, series); err != nil {
+		return nil, fmt.Errorf("event series service#create: %w", err)
+	}
+	series.Venue = venue
+
+	created, err := s.materializer.Materialize(ctx, series, time.Now())
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("series_public_id", series.PublicID).
+			Msg("failed to materialize new event series")
+	}
+
+	s.logger.Info().
+		Str("series_public_id", series.PublicID).
+		Str("user_id", userPublicID).
+		Str("rrule", series.RRule).
+		Int("occurrences", created).
+		Msg("Event series created")
+	return prepareEventSeriesDTO(series), nil
+}
+
+func (s *SeriesService) FindByPublicID(ctx context.Context, publicID string) (*EventSeriesDTO, error) {
+	series, err := s.repo.FindSeriesByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapSeriesError(err)
+	}
+	return prepareEventSeriesDTO(series), nil
+}
+
+func (s *SeriesService) List(ctx context.Context, query *ListEventSeriesQuery) (*EventSeriesListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	list := &EventSeriesListDTO{
+		Series: make([]EventSeriesDTO, 0, limit),
+	}
+
+	filter := SeriesFilter{AfterID: afterID}
+	if query.Organizer != "" {
+		organizerID, err := s.users.GetUserID(ctx, query.Organizer)
+		if errors.Is(err, errs.ErrNotFound) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event series service#list: %w", err)
+		}
+		filter.OrganizerID = *organizerID
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+	series, err := s.repo.ListSeries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("event series service#list: %w", err)
+	}
+
+	if len(series) > limit {
+		series = series[:limit]
+		list.NextCursor = util.EncodeCursor(series[limit-1].ID)
+	}
+	for i := range series {
+		list.Series = append(list.Series, *prepareEventSeriesDTO(&series[i]))
+	}
+	return list, nil
+}
+
+// UpdateFollowing applies req to an occurrence and to every occurrence of
+// its series after it. The series is split there: the occurrences before it
+// keep the current rule, cut short, and a new series carries the edit and
+// what is left of the rule. A new start date moves each following
+// occurrence by the same amount, and weekly weekdays along with them.
+func (s *SeriesService) UpdateFollowing(ctx context.Context, eventPublicID string, req *UpdateEventRequest, userPublicID string) (*EventDTO, error) {
+	ev, err := s.events.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.SeriesID == nil {
+		return nil, errs.NewValidationError("scope following only applies to occurrences of a series")
+	}
+
+	series, err := s.repo.FindSeriesByID(ctx, *ev.SeriesID)
+	if err != nil {
+		return nil, s.mapSeriesError(err)
+	}
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("event series service#update: %w", err)
+	}
+
+	venue := series.Venue
+	var newVenue *Venue
+	if req.VenueID != nil {
+		newVenue, err = s.events.findVenue(ctx, *req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if newVenue != nil {
+			venue = newVenue
+		}
+	}
+	if req.StartDate != nil {
+		if err := validateStartDate(*req.StartDate, venue.Location(), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	from := *ev.OccurrenceStart
+	newStart := ev.StartDate
+	if req.StartDate != nil {
+		newStart = req.StartDate.Truncate(time.Second)
+	}
+	shift := newStart.Sub(ev.StartDate)
+	if req.EndDate != nil && req.EndDate.Before(newStart) {
+		return nil, errs.NewValidationError("end date can not be before the start date")
+	}
+
+	occurrences, err := s.repo.ListOccurrences(ctx, series.ID, from)
+	if err != nil {
+		return nil, fmt.Errorf("event series service#update: %w", err)
+	}
+	// Moving the latest occurrence first when they move later, and the
+	// earliest first when they move earlier, keeps their starts apart.
+	if shift > 0 {
+		slices.Reverse(occurrences)
+	}
+
+	moves := make([]OccurrenceMove, 0, len(occurrences))
+	for _, o := range occurrences {
+		move := OccurrenceMove{
+			EventID: o.ID,
+			OccurrenceStart: o.OccurrenceStart.Add(shift),
+		}
+		if !o.DeletedAt.Valid {
+			update := EventUpdate{
+				Title: req.Title,
+				Description: req.Description,
+				MaxSeats: req.MaxSeats,
+				Venue: newVenue,
+			}
+			if shift != 0 || req.EndDate != nil {
+				start := o.StartDate.Add(shift)
+				end := o.EndDate.Add(shift)
+				if req.EndDate != nil {
+					end = start.Add(req.EndDate.Sub(newStart))
+				}
+				update.StartDate = &start
+				update.EndDate = &end
+			}
+			move.Update = &update
+		}
+		moves = append(moves, move)
+	}
+
+	next := &EventSeries{
+		PublicID: series.PublicID,
+		Title: series.Title,
+		Description: series.Description,
+		OrganizerID: series.OrganizerID,
+		StartDate: from.Add(shift),
+		EndDate: from.Add(shift).Add(series.EndDate.Sub(series.StartDate)),
+		MaxSeats: series.MaxSeats,
+		VenueID: series.VenueID,
+		MaterializedUntil: series.MaterializedUntil.Add(shift),
+	}
+	if newVenue != nil {
+		next.VenueID = &newVenue.ID
+	}
+	if req.Title != nil {
+		next.Title = *req.Title
+	}
+	if req.Description != nil {
+		next.Description = req.Description
+	}
+	if req.MaxSeats != nil {
+		next.MaxSeats = *req.MaxSeats
+	}
+	if req.EndDate != nil {
+		next.EndDate = next.StartDate.Add(req.EndDate.Sub(newStart))
+	}
+
+	// Weekdays are those at the venue, where the rule is expanded.
+	nextRule := rule.Shift(daysBetween(from.In(series.Location()), next.StartDate.In(venue.Location())))
+	if !rule.Until.IsZero() {
+		nextRule.Until = rule.Until.Add(shift)
+	}
+
+	split := SeriesSplit{
+		Series: series,
+		Next: next,
+		Moves: moves,
+	}
+	if before := countBefore(rule, series.StartDate.In(series.Location()), from); before > 0 {
+		keep := *rule
+		if rule.Count > 0 {
+			keep.Count = before
+			nextRule.Count = rule.Count - before
+		} else {
+			keep.Until = from.Add(-time.Second)
+		}
+		split.KeepRRule = keep.String()
+
+		publicID, err := util.GeneratePublicID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate public ID: %w", err)
+		}
+		next.PublicID = publicID
+	}
+	next.RRule = nextRule.String()
+
+	if err := s.repo.SplitSeries(ctx, split); err != nil {
+		return nil, s.mapSeriesError(err)
+	}
+
+	for _, o := range occurrences {
+		s.events.evictEvent(ctx, o.PublicID)
+	}
+
+	updated, err := s.events.repo.FindByID(ctx, ev.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("series_public_id", series.PublicID).
+		Str("next_series_public_id", next.PublicID).
+		Int("occurrences", len(moves)).
+		Str("user_id", userPublicID).
+		Msg("Event series updated from occurrence")
+	return s.events.prepareEventDTO(ctx, updated), nil
+}
+
+func (s *SeriesService) mapSeriesError(err error) error {
+	switch {
+	case errors.Is(err, ErrSeriesNotFound):
+		return errs.NewErrNotFound("event series")
+	case errors.Is(err, ErrSeriesChanged):
+		return errs.NewConflictError("event series changed while it was being edited, try again")
+	default:
+		return s.events.mapEventError(err)
+	}
+}
+
+// isFirstOccurrence reports whether start is the first occurrence rule gives
+// from start on, that is whether the rule does not skip it.
+func isFirstOccurrence(rule *rrule.Rule, start time.Time) bool {
+	for first := range rule.All(start) {
+		return first.Equal(start)
+	}
+	return false
+}
+
+// countBefore counts the occurrences of rule from start that come before
+// from.
+func countBefore(rule *rrule.Rule, start, from time.Time) int {
+	n := 0
+	for t := range rule.All(start) {
+		if !t.Before(from) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// daysBetween is the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	da := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	db := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// prepareEventSeriesDTO renders the series with its times in the time zone
+// of its venue.
+func prepareEventSeriesDTO(series *EventSeries) *EventSeriesDTO {
+	loc := series.Location()
+	return &EventSeriesDTO{
+		PublicID:          series.PublicID,
+		Title:             series.Title,
+		Description:       series.Description,
+		RRule:             series.RRule,
+		StartDate:         series.StartDate.In(loc),
+		EndDate:           series.EndDate.In(loc),
+		MaxSeats:          series.MaxSeats,
+		Venue:             prepareEventVenueDTO(series.Venue),
+		MaterializedUntil: series.MaterializedUntil.In(loc),
+		CreatedAt:         series.CreatedAt,
+		UpdatedAt:         series.UpdatedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/event-service/pkg/rrule"
+)
+
+func mustParseRule(t *testing.T, s string) *rrule.Rule {
+	t.Helper()
+	rule, err := rrule.Parse(s)
+	if err != nil {
+		t.Fatalf("rrule.Parse(%q) error = %v", s, err)
+	}
+	return rule
+}
+
+func TestIsFirstOccurrence(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  bool
+	}{
+		{name: "daily", rule: "FREQ=DAILY", start: time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC), want: true},
+		{name: "weekday in the rule", rule: "FREQ=WEEKLY;BYDAY=WE", start: time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC), want: true},
+		{name: "weekday not in the rule", rule: "FREQ=WEEKLY;BYDAY=MO,FR", start: time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFirstOccurrence(mustParseRule(t, tt.rule), tt.start); got != tt.want {
+				t.Errorf("isFirstOccurrence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountBefore(t *testing.T) {
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want int
+	}{
+		{name: "from the start", rule: "FREQ=DAILY", from: start, want: 0},
+		{name: "daily", rule: "FREQ=DAILY", from: start.AddDate(0, 0, 3), want: 3},
+		{name: "occurrence at from is not counted", rule: "FREQ=WEEKLY;BYDAY=MO,TH", from: time.Date(2026, 5, 11, 9, 0, 0, 0, time.UTC), want: 2},
+		{name: "rule ends before from", rule: "FREQ=DAILY;COUNT=2", from: start.AddDate(0, 1, 0), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countBefore(mustParseRule(t, tt.rule), start, tt.from); got != tt.want {
+				t.Errorf("countBefore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		a, b time.Time
+		want int
+	}{
+		{name: "same day", a: time.Date(2026, 5, 4, 1, 0, 0, 0, time.UTC), b: time.Date(2026, 5, 4, 23, 0, 0, 0, time.UTC), want: 0},
+		{name: "next day by an hour", a: time.Date(2026, 5, 4, 23, 0, 0, 0, time.UTC), b: time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC), want: 1},
+		{name: "backwards", a: time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC), b: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC), want: -3},
+		{name: "calendar days of each time zone", a: time.Date(2026, 5, 4, 23, 0, 0, 0, jakarta), b: time.Date(2026, 5, 4, 20, 0, 0, 0, time.UTC), want: 0},
+		{name: "across a month", a: time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC), b: time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysBetween(tt.a, tt.b); got != tt.want {
+				t.Errorf("daysBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anrisys/quicket/event-service/pkg/database"
@@ -18,9 +22,23 @@ type EventServiceInterface interface {
 	FindByID(ctx context.Context, id uint) (*EventDTOWithID, error) 
 	FindByPublicID(ctx context.Context, publicID string) (*EventDTO, error)
 	eventExistsByTitle(ctx context.Context, title string) (bool, error)
-	prepareEvent(ctx context.Context, req *CreateEventRequest, userID int) (*Event, error)
+	prepareEvent(ctx context.Context, req *CreateEventRequest, userID int, venue *Venue) (*Event, error)
+	AddTicketType(ctx context.Context, eventPublicID string, req *CreateTicketTypeRequest, userPublicID string) (*TicketTypeDTO, error)
+	UpdateTicketType(ctx context.Context, eventPublicID, ticketTypePublicID string, req *UpdateTicketTypeRequest, userPublicID string) (*TicketTypeDTO, error)
+	Update(ctx context.Context, eventPublicID string, req *UpdateEventRequest, userPublicID string) (*EventDTO, error)
+	Delete(ctx context.Context, eventPublicID string, userPublicID string) (*EventDTO, error)
+	List(ctx context.Context, query *ListEventsQuery) (*EventListDTO, error)
+	ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) error
 }
 
+const (
+	defaultListLimit = 20
+	// listCacheTTL bounds how long a cached listing lives. Listings are
+	// dropped early when an event changes, so this only keeps rarely asked
+	// queries from piling up.
+	listCacheTTL = 5 * time.Minute
+)
+
 type EventService struct {
 	repo   EventRepositoryInterface
 	users  UserReader
@@ -48,6 +66,14 @@ func (s *EventService) Create(ctx context.Context, req *CreateEventRequest, user
 		return nil, fmt.Errorf("event/service#create: %w", err)
 	}
 
+	venue, err := s.findVenue(ctx, req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStartDate(req.StartDate, venue.Location(), time.Now()); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.eventExistsByTitle(ctx, req.Title)
 	if err != nil {
 		return nil, fmt.Errorf("event/service#create: %w", err)
@@ -57,16 +83,27 @@ func (s *EventService) Create(ctx context.Context, req *CreateEventRequest, user
 		return nil, errs.NewConflictError("event with this title already exists")
 	}
 
-	newEv, err := s.prepareEvent(ctx, req, usr.ID)
+	newEv, err := s.prepareEvent(ctx, req, usr.ID, venue)
 	if err != nil {
 		return nil, err
 	}
 
+	var quotas uint64
+	for _, tt := range newEv.TicketTypes {
+		quotas += tt.Quota
+	}
+	if quotas > newEv.MaxSeats {
+		return nil, errs.NewValidationError("sum of ticket type quotas exceeds max seats")
+	}
+
 	registeredEvent, err := s.repo.Create(ctx, newEv)
 
 	if err != nil {
 		return nil, fmt.Errorf("event service#create: %w", err)
 	}
+	registeredEvent.Venue = venue
+
+	s.evictEvent(ctx, registeredEvent.PublicID)
 
 	evDTO := s.prepareEventDTO(ctx, registeredEvent)
 	return evDTO, nil
@@ -83,12 +120,12 @@ func (s *EventService) FindByID(ctx context.Context, id uint) (*EventDTOWithID,
 }
 
 func (s *EventService) FindByPublicID(ctx context.Context, publicID string) (*EventDTO, error) {
-	var event *EventDTO
+	var event EventDTO
 	cacheKey := fmt.Sprintf("%s:publicID:%s", database.EventKey, publicID)
-	err := s.redis.Get(ctx, cacheKey, event)
+	err := s.redis.Get(ctx, cacheKey, &event)
 	if err == nil {
 		s.logger.Debug().Msgf("Cache hit for event with public ID: %s", publicID)
-		return event, nil
+		return &event, nil
 	}
 
 	if err == redis.Nil {
@@ -102,16 +139,145 @@ func (s *EventService) FindByPublicID(ctx context.Context, publicID string) (*Ev
 		return nil, err
 	}
 
+	evDTO := s.prepareEventDTO(ctx, dbEvent)
+
 	cacheTTL := 1 * time.Hour
-	err = s.redis.Set(ctx, cacheKey, dbEvent, cacheTTL)
+	err = s.redis.Set(ctx, cacheKey, evDTO, cacheTTL)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to set event in Redis cache")
 	}
-	
-	evDTO := s.prepareEventDTO(ctx, dbEvent)
+
 	return evDTO, nil
 }
 
+// List returns a page of events matching query. First pages are what most
+// clients ask for, so they are cached until an event changes; later pages
+// are read from the database.
+func (s *EventService) List(ctx context.Context, query *ListEventsQuery) (*EventListDTO, error) {
+	filter := EventFilter{
+		Keyword: strings.TrimSpace(query.Q),
+		From: query.From,
+		To: query.To,
+		Available: query.Available,
+		SeriesPublicID: query.Series,
+		Sort: query.Sort,
+	}
+
+	var err error
+	switch query.Sort {
+	case SortCreatedAt, SortCreatedAtDesc:
+		filter.AfterID, err = util.DecodeCursor(query.Cursor)
+	default:
+		filter.AfterStartDate, filter.AfterID, err = util.DecodeTimeCursor(query.Cursor)
+	}
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, errs.NewValidationError("from must not be after to")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	var cacheKey string
+	if query.Cursor == "" {
+		cacheKey = s.listCacheKey(ctx, query, limit)
+	}
+	if cacheKey != "" {
+		var cached EventListDTO
+		err := s.redis.Get(ctx, cacheKey, &cached)
+		if err == nil {
+			s.logger.Debug().Str("key", cacheKey).Msg("Cache hit for event listing")
+			return &cached, nil
+		}
+		if err != redis.Nil {
+			s.logger.Error().Err(err).Msg("Redis Get operation failed")
+		}
+	}
+
+	list := &EventListDTO{
+		Events: make([]EventDTO, 0, limit),
+	}
+
+	if query.Organizer != "" {
+		organizerID, err := s.users.GetUserID(ctx, query.Organizer)
+		if errors.Is(err, errs.ErrNotFound) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event service#list: %w", err)
+		}
+		filter.OrganizerID = *organizerID
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+	events, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("event service#list: %w", err)
+	}
+
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		switch query.Sort {
+		case SortCreatedAt, SortCreatedAtDesc:
+			list.NextCursor = util.EncodeCursor(last.ID)
+		default:
+			list.NextCursor = util.EncodeTimeCursor(last.StartDate, last.ID)
+		}
+	}
+	for i := range events {
+		list.Events = append(list.Events, *s.prepareEventDTO(ctx, &events[i]))
+	}
+
+	if cacheKey != "" {
+		if err := s.redis.Set(ctx, cacheKey, list, listCacheTTL); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to set event listing in Redis cache")
+		}
+	}
+	return list, nil
+}
+
+// listCacheKey names the cached listing for query under the current listing
+// version. It returns "" when the version can not be read, and the listing
+// is then not cached.
+func (s *EventService) listCacheKey(ctx context.Context, query *ListEventsQuery, limit int) string {
+	var version int64
+	if err := s.redis.Get(ctx, database.EventListVersionKey, &version); err != nil && err != redis.Nil {
+		s.logger.Error().Err(err).Msg("Failed to read event listing version")
+		return ""
+	}
+	return eventListCacheKey(version, query, limit)
+}
+
+// eventListCacheKey names the listing for query at listing version version.
+// Queries that list the same events share a key.
+func eventListCacheKey(version int64, query *ListEventsQuery, limit int) string {
+	var from, to string
+	if !query.From.IsZero() {
+		from = query.From.UTC().Format(time.RFC3339Nano)
+	}
+	if !query.To.IsZero() {
+		to = query.To.UTC().Format(time.RFC3339Nano)
+	}
+	params := strings.Join([]string{
+		strings.ToLower(strings.TrimSpace(query.Q)),
+		from,
+		to,
+		strconv.FormatBool(query.Available),
+		query.Organizer,
+		query.Series,
+		query.Sort,
+		strconv.Itoa(limit),
+	}, "\x00")
+	sum := sha256.Sum256([]byte(params))
+	return fmt.Sprintf("%s:v%d:%s", database.EventListKey, version, hex.EncodeToString(sum[:]))
+}
+
 func (s *EventService) GetEventDateTimeAndSeats(ctx context.Context, publicID string) (*EventDateTimeAndSeats, error) {
 	event, err := s.repo.FindByPublicID(ctx, publicID)
 	if err != nil {
@@ -124,6 +290,221 @@ func (s *EventService) GetEventDateTimeAndSeats(ctx context.Context, publicID st
 	}, nil
 }
 
+func (s *EventService) AddTicketType(ctx context.Context, eventPublicID string, req *CreateTicketTypeRequest, userPublicID string) (*TicketTypeDTO, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	tt, err := s.prepareTicketType(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddTicketType(ctx, ev.ID, tt); err != nil {
+		return nil, s.mapTicketTypeError(err)
+	}
+	s.evictEvent(ctx, eventPublicID)
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("ticket_type_public_id", tt.PublicID).
+		Msg("Ticket type created")
+	ttDTO := prepareTicketTypeDTO(tt)
+	return &ttDTO, nil
+}
+
+func (s *EventService) UpdateTicketType(ctx context.Context, eventPublicID, ticketTypePublicID string, req *UpdateTicketTypeRequest, userPublicID string) (*TicketTypeDTO, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	tt, err := s.repo.UpdateTicketType(ctx, ev.ID, ticketTypePublicID, TicketTypeUpdate{
+		Name: req.Name,
+		Price: req.Price,
+		Quota: req.Quota,
+		MaxPerOrder: req.MaxPerOrder,
+	})
+	if err != nil {
+		return nil, s.mapTicketTypeError(err)
+	}
+	s.evictEvent(ctx, eventPublicID)
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("ticket_type_public_id", tt.PublicID).
+		Msg("Ticket type updated")
+	ttDTO := prepareTicketTypeDTO(tt)
+	return &ttDTO, nil
+}
+
+func (s *EventService) Update(ctx context.Context, eventPublicID string, req *UpdateEventRequest, userPublicID string) (*EventDTO, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences share the title of their series.
+	if req.Title != nil && *req.Title != ev.Title && ev.SeriesID == nil {
+		other, err := s.repo.FindByTitle(ctx, *req.Title)
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return nil, fmt.Errorf("event service#update: %w", err)
+		}
+		if err == nil && other.ID != ev.ID {
+			return nil, errs.NewConflictError("event with this title already exists")
+		}
+	}
+
+	venue := ev.Venue
+	var newVenue *Venue
+	if req.VenueID != nil {
+		newVenue, err = s.findVenue(ctx, *req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if newVenue != nil {
+			venue = newVenue
+		}
+	}
+	if req.StartDate != nil {
+		if err := validateStartDate(*req.StartDate, venue.Location(), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, ev.ID, EventUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		StartDate:   inUTC(req.StartDate),
+		EndDate:     inUTC(req.EndDate),
+		MaxSeats:    req.MaxSeats,
+		Venue:       newVenue,
+	})
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+	s.evictEvent(ctx, eventPublicID)
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("user_id", userPublicID).
+		Msg("Event updated")
+	return s.prepareEventDTO(ctx, updated), nil
+}
+
+// Delete removes the event. Events with sold seats are soft-deleted, which
+// the returned event tells by its DeletedAt.
+func (s *EventService) Delete(ctx context.Context, eventPublicID string, userPublicID string) (*EventDTO, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.repo.Delete(ctx, ev.ID)
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+	s.evictEvent(ctx, eventPublicID)
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("user_id", userPublicID).
+		Bool("soft", deleted.DeletedAt.Valid).
+		Msg("Event deleted")
+	return s.prepareEventDTO(ctx, deleted), nil
+}
+
+// findManagedEvent loads the event and makes sure the user is its organizer
+// or an admin.
+func (s *EventService) findManagedEvent(ctx context.Context, eventPublicID, userPublicID string) (*Event, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("event/service#managed: %w", err)
+	}
+
+	ev, err := s.repo.FindByPublicID(ctx, eventPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if usr.Role != "admin" && ev.OrganizerID != uint(usr.ID) {
+		return nil, errs.NewForbiddenError("only the organizer of this event can manage it")
+	}
+	return ev, nil
+}
+
+// ApplySeatsDelta records the seats a booking in booking-service took or
+// gave back. A delta that was applied already is ignored.
+func (s *EventService) ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) error {
+	ev, err := s.repo.ApplySeatsDelta(ctx, messageID, msg)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateMessage) {
+			s.logger.Debug().
+				Str("message_id", messageID).
+				Msg("Seats update already applied")
+			return nil
+		}
+		return err
+	}
+
+	s.evictEvent(ctx, ev.PublicID)
+
+	s.logger.Info().
+		Uint("event_id", ev.ID).
+		Int64("delta", msg.Delta).
+		Uint64("available_seats", ev.AvailableSeats).
+		Uint("version", ev.Version).
+		Msg("Event seats updated")
+	return nil
+}
+
+// evictEvent drops the cached copy of the event and the cached listings so
+// readers see the change.
+func (s *EventService) evictEvent(ctx context.Context, publicID string) {
+	cacheKey := fmt.Sprintf("%s:publicID:%s", database.EventKey, publicID)
+	if err := s.redis.Del(ctx, cacheKey); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to evict event from Redis cache")
+	}
+	if _, err := s.redis.Incr(ctx, database.EventListVersionKey); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to invalidate cached event listings")
+	}
+}
+
+func (s *EventService) mapTicketTypeError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrTicketTypeNotFound):
+		return errs.NewErrNotFound("ticket type")
+	case errors.Is(err, ErrQuotaExceedsSeats):
+		return errs.NewConflictError("sum of ticket type quotas exceeds max seats")
+	case errors.Is(err, ErrQuotaBelowSold):
+		return errs.NewConflictError("quota can not be lower than the seats already sold")
+	case errors.Is(err, ErrMaxPerOrderAboveQuota):
+		return errs.NewValidationError("max per order can not be greater than the quota")
+	default:
+		return fmt.Errorf("event service#ticket type: %w", err)
+	}
+}
+
+func (s *EventService) mapEventError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrInvalidDates):
+		return errs.NewValidationError("end date can not be before the start date")
+	case errors.Is(err, ErrMaxSeatsBelowSold):
+		return errs.NewConflictError("max seats can not be lower than the seats already sold")
+	case errors.Is(err, ErrQuotaExceedsSeats):
+		return errs.NewConflictError("max seats can not be lower than the sum of ticket type quotas")
+	case errors.Is(err, ErrVenueNotFound):
+		return errs.NewErrNotFound("venue")
+	default:
+		return fmt.Errorf("event service#event: %w", err)
+	}
+}
+
 func (s *EventService) eventExistsByTitle(ctx context.Context, title string) (bool, error) {
 	_, err := s.repo.FindByTitle(ctx, title)
 	if err == nil {
@@ -135,36 +516,128 @@ func (s *EventService) eventExistsByTitle(ctx context.Context, title string) (bo
 	return false, err
 }
 
-func (s *EventService) prepareEvent(ctx context.Context, req *CreateEventRequest, userID int) (*Event, error) {
+func (s *EventService) prepareEvent(ctx context.Context, req *CreateEventRequest, userID int, venue *Venue) (*Event, error) {
+	maxSeats, err := maxSeatsAt(req.MaxSeats, venue)
+	if err != nil {
+		return nil, err
+	}
+
 	publicID, err := util.GeneratePublicID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate public ID: %w", err)
 	}
 
-	return &Event{
+	ticketTypes := make([]TicketType, 0, len(req.TicketTypes))
+	for i := range req.TicketTypes {
+		tt, err := s.prepareTicketType(ctx, &req.TicketTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		ticketTypes = append(ticketTypes, *tt)
+	}
+
+	ev := &Event{
 		PublicID:       publicID,
 		Title:          req.Title,
 		Description:    &req.Description,
 		OrganizerID:    uint(userID),
-		StartDate:      req.StartDate,
-		EndDate:        req.EndDate,
-		MaxSeats:       req.MaxSeats,
-		AvailableSeats: req.MaxSeats,
+		StartDate:      req.StartDate.UTC(),
+		EndDate:        req.EndDate.UTC(),
+		MaxSeats:       maxSeats,
+		AvailableSeats: maxSeats,
+		TicketTypes:    ticketTypes,
+	}
+	if venue != nil {
+		ev.VenueID = &venue.ID
+	}
+	return ev, nil
+}
+
+// findVenue loads the venue an event is to be held at. An empty publicID is
+// no venue.
+func (s *EventService) findVenue(ctx context.Context, publicID string) (*Venue, error) {
+	if publicID == "" {
+		return nil, nil
+	}
+	venue, err := s.repo.FindVenueByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+	return venue, nil
+}
+
+// maxSeatsAt is maxSeats, or the capacity of the venue when it is not given.
+func maxSeatsAt(maxSeats uint64, venue *Venue) (uint64, error) {
+	if maxSeats > 0 {
+		return maxSeats, nil
+	}
+	if venue == nil {
+		return 0, errs.NewValidationError("max seats is required for an event without a venue")
+	}
+	return venue.Capacity, nil
+}
+
+// validateStartDate makes sure start falls after today in loc, the time
+// zone of the venue, rather than that of the server.
+func validateStartDate(start time.Time, loc *time.Location, now time.Time) error {
+	y, m, d := now.In(loc).Date()
+	if !start.After(time.Date(y, m, d+1, 0, 0, 0, 0, loc)) {
+		return errs.NewValidationError("start date must be after today")
+	}
+	return nil
+}
+
+// inUTC is t in UTC, the zone event times are stored in.
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func (s *EventService) prepareTicketType(ctx context.Context, req *CreateTicketTypeRequest) (*TicketType, error) {
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public ID: %w", err)
+	}
+
+	return &TicketType{
+		PublicID:    publicID,
+		Name:        req.Name,
+		Price:       req.Price,
+		Quota:       req.Quota,
+		Available:   req.Quota,
+		MaxPerOrder: req.MaxPerOrder,
 	}, nil
 }
 
+// prepareEventDTO renders the event with its times in the time zone of its
+// venue.
 func (s *EventService) prepareEventDTO(_ctx context.Context, ev *Event) *EventDTO {
-	return &EventDTO{
+	loc := ev.Location()
+	ticketTypes := make([]TicketTypeDTO, 0, len(ev.TicketTypes))
+	for i := range ev.TicketTypes {
+		ticketTypes = append(ticketTypes, prepareTicketTypeDTO(&ev.TicketTypes[i]))
+	}
+
+	evDTO := &EventDTO{
 		PublicID: ev.PublicID,
 		Title: ev.Title,
 		Description: ev.Description,
-		StartDate: ev.StartDate,
-		EndDate: ev.EndDate,
+		StartDate: ev.StartDate.In(loc),
+		EndDate: ev.EndDate.In(loc),
 		MaxSeats: ev.MaxSeats,
 		AvailableSeats: ev.AvailableSeats,
+		Venue: prepareEventVenueDTO(ev.Venue),
+		TicketTypes: ticketTypes,
 		CreatedAt: ev.CreatedAt,
 		UpdatedAt: ev.UpdatedAt,
 	}
+	if ev.DeletedAt.Valid {
+		evDTO.DeletedAt = &ev.DeletedAt.Time
+	}
+	return evDTO
 }
 
 func (s *EventService) prepareEventDTOWithID(_ctx context.Context, ev *Event) *EventDTOWithID {
@@ -173,4 +646,26 @@ func (s *EventService) prepareEventDTOWithID(_ctx context.Context, ev *Event) *E
 		ID: ev.ID,
 		EventDTO: *baseEv,
 	}
+}
+
+func prepareEventVenueDTO(venue *Venue) *EventVenueDTO {
+	if venue == nil {
+		return nil
+	}
+	return &EventVenueDTO{
+		PublicID: venue.PublicID,
+		Name:     venue.Name,
+		TimeZone: venue.TimeZone,
+	}
+}
+
+func prepareTicketTypeDTO(tt *TicketType) TicketTypeDTO {
+	return TicketTypeDTO{
+		PublicID:    tt.PublicID,
+		Name:        tt.Name,
+		Price:       tt.Price,
+		Quota:       tt.Quota,
+		Available:   tt.Available,
+		MaxPerOrder: tt.MaxPerOrder,
+	}
 }
\ No newline at end of file
@@ -0,0 +1,424 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/event-service/pkg/config"
+	"github.com/anrisys/quicket/event-service/pkg/database"
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/anrisys/quicket/event-service/pkg/util"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// statusOf is the HTTP status err maps to, or 0 when it is not an AppError.
+func statusOf(err error) int {
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Status
+	}
+	return 0
+}
+
+func TestValidateStartDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20:00 on 1 May in UTC is already 2 May in Jakarta and still 1 May in
+	// Los Angeles.
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		loc     *time.Location
+		wantErr bool
+	}{
+		{name: "tomorrow in the venue's zone", start: time.Date(2026, 5, 3, 9, 0, 0, 0, jakarta), loc: jakarta},
+		{name: "today in the venue's zone", start: time.Date(2026, 5, 2, 23, 0, 0, 0, jakarta), loc: jakarta, wantErr: true},
+		{name: "tomorrow in UTC is today in the venue's zone", start: time.Date(2026, 5, 2, 12, 0, 0, 0, jakarta), loc: jakarta, wantErr: true},
+		{name: "today in UTC is tomorrow in the venue's zone", start: time.Date(2026, 5, 2, 9, 0, 0, 0, losAngeles), loc: losAngeles},
+		{name: "midnight starting tomorrow", start: time.Date(2026, 5, 3, 0, 0, 0, 0, jakarta), loc: jakarta, wantErr: true},
+		{name: "in the past", start: time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC), loc: time.UTC, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStartDate(tt.start, tt.loc, now)
+			if tt.wantErr {
+				if statusOf(err) != http.StatusBadRequest {
+					t.Errorf("validateStartDate() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("validateStartDate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestMaxSeatsAt(t *testing.T) {
+	venue := &Venue{Capacity: 500}
+
+	tests := []struct {
+		name     string
+		maxSeats uint64
+		venue    *Venue
+		want     uint64
+		wantErr  bool
+	}{
+		{name: "given seats win over the venue", maxSeats: 100, venue: venue, want: 100},
+		{name: "venue capacity by default", venue: venue, want: 500},
+		{name: "given seats without a venue", maxSeats: 100, want: 100},
+		{name: "no seats and no venue", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxSeatsAt(tt.maxSeats, tt.venue)
+			if tt.wantErr {
+				if statusOf(err) != http.StatusBadRequest {
+					t.Errorf("maxSeatsAt() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("maxSeatsAt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("maxSeatsAt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVenue_Location(t *testing.T) {
+	tests := []struct {
+		name  string
+		venue *Venue
+		want  string
+	}{
+		{name: "venue time zone", venue: &Venue{TimeZone: "Asia/Jakarta"}, want: "Asia/Jakarta"},
+		{name: "no venue", venue: nil, want: "UTC"},
+		{name: "unknown time zone", venue: &Venue{TimeZone: "Mars/Olympus"}, want: "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.venue.Location().String(); got != tt.want {
+				t.Errorf("Location() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	lat, lng := -6.2, 106.8
+
+	tests := []struct {
+		name     string
+		lat, lng *float64
+		wantErr  bool
+	}{
+		{name: "both", lat: &lat, lng: &lng},
+		{name: "neither"},
+		{name: "latitude only", lat: &lat, wantErr: true},
+		{name: "longitude only", lng: &lng, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCoordinates(tt.lat, tt.lng)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCoordinates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// eventRepo is an in-memory stand-in for the event repository. Only what the
+// tests call is implemented.
+type eventRepo struct {
+	EventRepositoryInterface
+	events  map[string]*Event
+	updated bool
+	deleted bool
+	// listed is what List returns, cut to the filter's limit; filter is the
+	// last filter it was called with.
+	listed []Event
+	filter EventFilter
+	// seatsErr is what ApplySeatsDelta returns.
+	seatsErr error
+}
+
+func (r *eventRepo) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	r.filter = filter
+	if len(r.listed) > filter.Limit {
+		return r.listed[:filter.Limit], nil
+	}
+	return r.listed, nil
+}
+
+func (r *eventRepo) FindByPublicID(ctx context.Context, publicID string) (*Event, error) {
+	ev, ok := r.events[publicID]
+	if !ok {
+		return nil, errs.NewErrNotFound("event")
+	}
+	return ev, nil
+}
+
+func (r *eventRepo) ApplySeatsDelta(ctx context.Context, messageID string, msg *BookingSeatsUpdatedMessage) (*Event, error) {
+	if r.seatsErr != nil {
+		return nil, r.seatsErr
+	}
+	return &Event{Model: gorm.Model{ID: msg.EventID}, PublicID: "ev-1"}, nil
+}
+
+func (r *eventRepo) Update(ctx context.Context, eventID uint, update EventUpdate) (*Event, error) {
+	r.updated = true
+	for _, ev := range r.events {
+		if ev.ID == eventID {
+			if update.Description != nil {
+				ev.Description = update.Description
+			}
+			return ev, nil
+		}
+	}
+	return nil, ErrEventNotFound
+}
+
+func (r *eventRepo) Delete(ctx context.Context, eventID uint) (*Event, error) {
+	r.deleted = true
+	for _, ev := range r.events {
+		if ev.ID == eventID {
+			return ev, nil
+		}
+	}
+	return nil, ErrEventNotFound
+}
+
+// userReader finds the users of the test by public id.
+type userReader map[string]*UserDTO
+
+func (u userReader) GetUserID(ctx context.Context, publicID string) (*uint, error) {
+	usr, ok := u[publicID]
+	if !ok {
+		return nil, errs.NewErrNotFound("user")
+	}
+	id := uint(usr.ID)
+	return &id, nil
+}
+
+func (u userReader) FindUserByPublicID(ctx context.Context, publicID string) (*UserDTO, error) {
+	usr, ok := u[publicID]
+	if !ok {
+		return nil, errs.NewErrNotFound("user")
+	}
+	return usr, nil
+}
+
+// closedRedis is a Redis client that fails every command at once, so the
+// service runs as it does when the cache is down.
+func closedRedis(t *testing.T) *database.RedisClient {
+	t.Helper()
+	rdb := database.NewRedisClient(&config.Config{Redis: &config.RedisConfig{Host: "localhost", Port: "6379"}})
+	if err := rdb.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return rdb
+}
+
+var testUsers = userReader{
+	"organizer": {ID: 1, PublicID: "organizer", Role: "organizer"},
+	"other":     {ID: 2, PublicID: "other", Role: "organizer"},
+	"admin":     {ID: 3, PublicID: "admin", Role: "admin"},
+}
+
+func TestEventService_ManagedByOrganizer(t *testing.T) {
+	description := "moved to the main hall"
+
+	tests := []struct {
+		name       string
+		user       string
+		event      string
+		wantStatus int
+	}{
+		{name: "organizer", user: "organizer", event: "ev-1"},
+		{name: "admin", user: "admin", event: "ev-1"},
+		{name: "another organizer", user: "other", event: "ev-1", wantStatus: http.StatusForbidden},
+		{name: "unknown event", user: "organizer", event: "ev-2", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		for _, op := range []string{"update", "delete"} {
+			t.Run(op+" by "+tt.name, func(t *testing.T) {
+				repo := &eventRepo{events: map[string]*Event{
+					"ev-1": {Model: gorm.Model{ID: 10}, PublicID: "ev-1", OrganizerID: 1},
+				}}
+				srv := NewEventService(repo, testUsers, zerolog.Nop(), closedRedis(t))
+
+				var err error
+				switch op {
+				case "update":
+					_, err = srv.Update(context.Background(), tt.event, &UpdateEventRequest{Description: &description}, tt.user)
+				case "delete":
+					_, err = srv.Delete(context.Background(), tt.event, tt.user)
+				}
+
+				if tt.wantStatus == 0 {
+					if err != nil {
+						t.Fatalf("%s error = %v", op, err)
+					}
+				} else if got := statusOf(err); got != tt.wantStatus {
+					t.Fatalf("%s error = %v, want status %d", op, err, tt.wantStatus)
+				}
+				changed := repo.updated || repo.deleted
+				if changed != (tt.wantStatus == 0) {
+					t.Errorf("event changed = %v, want %v", changed, tt.wantStatus == 0)
+				}
+			})
+		}
+	}
+}
+
+func TestEventService_List(t *testing.T) {
+	start := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Model: gorm.Model{ID: 1}, PublicID: "ev-1", StartDate: start},
+		{Model: gorm.Model{ID: 2}, PublicID: "ev-2", StartDate: start.Add(time.Hour)},
+		{Model: gorm.Model{ID: 3}, PublicID: "ev-3", StartDate: start.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name          string
+		query         ListEventsQuery
+		wantIDs       []string
+		wantCursor    string
+		wantAfterID   uint
+		wantAfterDate time.Time
+		wantStatus    int
+	}{
+		{
+			name:       "first page by start date",
+			query:      ListEventsQuery{Limit: 2},
+			wantIDs:    []string{"ev-1", "ev-2"},
+			wantCursor: util.EncodeTimeCursor(start.Add(time.Hour), 2),
+		},
+		{
+			name:       "first page by creation",
+			query:      ListEventsQuery{Limit: 2, Sort: SortCreatedAt},
+			wantIDs:    []string{"ev-1", "ev-2"},
+			wantCursor: util.EncodeCursor(2),
+		},
+		{
+			name:    "last page has no cursor",
+			query:   ListEventsQuery{Limit: 3},
+			wantIDs: []string{"ev-1", "ev-2", "ev-3"},
+		},
+		{
+			name:          "next page by start date",
+			query:         ListEventsQuery{Limit: 3, Cursor: util.EncodeTimeCursor(start, 1)},
+			wantIDs:       []string{"ev-1", "ev-2", "ev-3"},
+			wantAfterID:   1,
+			wantAfterDate: start,
+		},
+		{
+			name:        "next page by creation",
+			query:       ListEventsQuery{Limit: 3, Sort: SortCreatedAtDesc, Cursor: util.EncodeCursor(9)},
+			wantIDs:     []string{"ev-1", "ev-2", "ev-3"},
+			wantAfterID: 9,
+		},
+		{
+			name:       "cursor of the other order",
+			query:      ListEventsQuery{Sort: SortCreatedAt, Cursor: util.EncodeTimeCursor(start, 1)},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "to before from",
+			query:      ListEventsQuery{From: start, To: start.Add(-time.Hour)},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &eventRepo{listed: events}
+			srv := NewEventService(repo, testUsers, zerolog.Nop(), closedRedis(t))
+
+			list, err := srv.List(context.Background(), &tt.query)
+			if tt.wantStatus != 0 {
+				if got := statusOf(err); got != tt.wantStatus {
+					t.Fatalf("List() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			var ids []string
+			for _, ev := range list.Events {
+				ids = append(ids, ev.PublicID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("events = %v, want %v", ids, tt.wantIDs)
+			}
+			if list.NextCursor != tt.wantCursor {
+				t.Errorf("next cursor = %q, want %q", list.NextCursor, tt.wantCursor)
+			}
+			if repo.filter.Limit != tt.query.Limit+1 {
+				t.Errorf("filter limit = %d, want %d", repo.filter.Limit, tt.query.Limit+1)
+			}
+			if repo.filter.AfterID != tt.wantAfterID || !repo.filter.AfterStartDate.Equal(tt.wantAfterDate) {
+				t.Errorf("filter after = %v, %d, want %v, %d", repo.filter.AfterStartDate, repo.filter.AfterID, tt.wantAfterDate, tt.wantAfterID)
+			}
+		})
+	}
+}
+
+func TestEventListCacheKey(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := eventListCacheKey(1, &ListEventsQuery{Q: "jazz", From: from}, 20)
+
+	tests := []struct {
+		name     string
+		version  int64
+		query    ListEventsQuery
+		limit    int
+		wantSame bool
+	}{
+		{name: "same query", version: 1, query: ListEventsQuery{Q: "jazz", From: from}, limit: 20, wantSame: true},
+		{name: "keyword case and spaces", version: 1, query: ListEventsQuery{Q: "  Jazz ", From: from}, limit: 20, wantSame: true},
+		{name: "same instant in another zone", version: 1, query: ListEventsQuery{Q: "jazz", From: from.In(jakarta)}, limit: 20, wantSame: true},
+		{name: "listing version bumped", version: 2, query: ListEventsQuery{Q: "jazz", From: from}, limit: 20},
+		{name: "other keyword", version: 1, query: ListEventsQuery{Q: "rock", From: from}, limit: 20},
+		{name: "other limit", version: 1, query: ListEventsQuery{Q: "jazz", From: from}, limit: 10},
+		{name: "other order", version: 1, query: ListEventsQuery{Q: "jazz", From: from, Sort: SortStartDateDesc}, limit: 20},
+		{name: "available only", version: 1, query: ListEventsQuery{Q: "jazz", From: from, Available: true}, limit: 20},
+		{name: "from moved to to", version: 1, query: ListEventsQuery{Q: "jazz", To: from}, limit: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := eventListCacheKey(tt.version, &tt.query, tt.limit)
+			if (got == base) != tt.wantSame {
+				t.Errorf("key %q, base %q, want same = %v", got, base, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type VenueHandler struct {
+	VenueService VenueServiceInterface
+	logger       zerolog.Logger
+}
+
+func NewVenueHandler(venueService VenueServiceInterface, logger zerolog.Logger) *VenueHandler {
+	return &VenueHandler{
+		VenueService: venueService,
+		logger:       logger,
+	}
+}
+
+// Create godoc
+// @Summary Create venue
+// @Description Create a venue events can be held at (Admin/Organizer only). Event times at the venue are read and validated in its time zone.
+// @Tags Venues
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateVenueRequest true "Venue data"
+// @Success 201 {object} VenueSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden (role restriction)"
+// @Router /api/v1/venues [post]
+func (h *VenueHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var req CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid venue data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	venue, err := h.VenueService.Create(ctx, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := VenueSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue created successfully",
+		},
+		Venue: *venue,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Get godoc
+// @Summary Get venue
+// @Description Get a venue
+// @Tags Venues - Public
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Success 200 {object} VenueSuccessResponse
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Router /api/v1/venues/{publicID} [get]
+func (h *VenueHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	venue, err := h.VenueService.FindByPublicID(ctx, c.Param("publicID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := VenueSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue retrieved successfully",
+		},
+		Venue: *venue,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List venues
+// @Description Page through venues in creation order; pass next_cursor back as cursor for the next page.
+// @Tags Venues - Public
+// @Produce json
+// @Param owner query string false "Owner public ID"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} ListVenuesSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Router /api/v1/venues [get]
+func (h *VenueHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query ListVenuesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.VenueService.List(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListVenuesSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venues retrieved successfully",
+		},
+		VenueListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update godoc
+// @Summary Update venue
+// @Description Change a venue (venue owner or admin only). The time zone of a venue with events can not change.
+// @Tags Venues
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Param request body UpdateVenueRequest true "Fields to change"
+// @Success 200 {object} VenueSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Failure 409 {object} errs.ErrorResponse "Venue has events"
+// @Router /api/v1/venues/{publicID} [patch]
+func (h *VenueHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var req UpdateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid venue data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	venue, err := h.VenueService.Update(ctx, c.Param("publicID"), &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := VenueSuccessResponse{
+		ResponseSuccess: ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue updated successfully",
+		},
+		Venue: *venue,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Delete godoc
+// @Summary Delete venue
+// @Description Delete a venue no event is held at (venue owner or admin only)
+// @Tags Venues
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Failure 409 {object} errs.ErrorResponse "Venue has events"
+// @Router /api/v1/venues/{publicID} [delete]
+func (h *VenueHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	if err := h.VenueService.Delete(ctx, c.Param("publicID"), userPublicID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseSuccess{
+		Code:    "SUCCESS",
+		Message: "Venue deleted successfully",
+	})
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VenueRepositoryInterface interface {
+	CreateVenue(ctx context.Context, venue *Venue) error
+	FindVenueByPublicID(ctx context.Context, publicID string) (*Venue, error)
+	ListVenues(ctx context.Context, filter VenueFilter) ([]Venue, error)
+	UpdateVenue(ctx context.Context, venueID uint, update VenueUpdate) (*Venue, error)
+	DeleteVenue(ctx context.Context, venueID uint) error
+}
+
+// VenueFilter narrows the venues returned by ListVenues, which orders them
+// by id. A zero AfterID starts from the first venue.
+type VenueFilter struct {
+	OwnerID uint
+	AfterID uint
+	Limit int
+}
+
+// VenueUpdate holds the fields of a venue to change; nil fields are left as
+// they are. Latitude and Longitude change together.
+type VenueUpdate struct {
+	Name *string
+	Address *string
+	TimeZone *string
+	Capacity *uint64
+	Latitude *float64
+	Longitude *float64
+}
+
+func (r *EventRepository) CreateVenue(ctx context.Context, venue *Venue) error {
+	if err := r.db.WithContext(ctx).Create(venue).Error; err != nil {
+		if isConnectionError(err) {
+			return errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("venue_public_id", venue.PublicID).
+			Msg("insert venue failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func (r *EventRepository) FindVenueByPublicID(ctx context.Context, publicID string) (*Venue, error) {
+	venue := &Venue{}
+	if err := r.db.WithContext(ctx).Take(venue, "public_id = ?", publicID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVenueNotFound
+		}
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("venue_public_id", publicID).
+			Msg("select venue failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return venue, nil
+}
+
+func (r *EventRepository) ListVenues(ctx context.Context, filter VenueFilter) ([]Venue, error) {
+	q := r.db.WithContext(ctx).Model(&Venue{})
+	if filter.OwnerID > 0 {
+		q = q.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("id > ?", filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var venues []Venue
+	if err := q.Order("id").Find(&venues).Error; err != nil {
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Uint("owner_id", filter.OwnerID).
+			Msg("list venues failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return venues, nil
+}
+
+// UpdateVenue applies update to the venue. The time zone of a venue that
+// has events is fixed, since their times would silently move on the clock.
+func (r *EventRepository) UpdateVenue(ctx context.Context, venueID uint, update VenueUpdate) (*Venue, error) {
+	var venue Venue
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockVenue(tx, venueID, &venue); err != nil {
+			return err
+		}
+
+		if update.TimeZone != nil && *update.TimeZone != venue.TimeZone {
+			inUse, err := r.venueInUse(tx, venueID)
+			if err != nil {
+				return err
+			}
+			if inUse {
+				return ErrVenueInUse
+			}
+			venue.TimeZone = *update.TimeZone
+		}
+		if update.Name != nil {
+			venue.Name = *update.Name
+		}
+		if update.Address != nil {
+			venue.Address = *update.Address
+		}
+		if update.Capacity != nil {
+			venue.Capacity = *update.Capacity
+		}
+		if update.Latitude != nil && update.Longitude != nil {
+			venue.Latitude = update.Latitude
+			venue.Longitude = update.Longitude
+		}
+
+		if err := tx.Save(&venue).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Msg("update venue failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// DeleteVenue removes the venue. A venue that events or event series,
+// deleted ones included, still point at is kept.
+func (r *EventRepository) DeleteVenue(ctx context.Context, venueID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var venue Venue
+		if err := r.lockVenue(tx, venueID, &venue); err != nil {
+			return err
+		}
+
+		inUse, err := r.venueInUse(tx, venueID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrVenueInUse
+		}
+
+		if err := tx.Delete(&venue).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Msg("delete venue failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+func (r *EventRepository) lockVenue(tx *gorm.DB, venueID uint, venue *Venue) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Take(venue, venueID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVenueNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("venue_id", venueID).
+			Msg("lock/select venue failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// venueInUse reports whether any event or event series points at the
+// venue.
+func (r *EventRepository) venueInUse(tx *gorm.DB, venueID uint) (bool, error) {
+	for _, table := range []string{"events", "event_series"} {
+		var n int64
+		if err := tx.Table(table).Where("venue_id = ?", venueID).Count(&n).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Str("table", table).
+				Msg("count venue references failed")
+			return false, fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
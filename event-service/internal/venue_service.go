@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anrisys/quicket/event-service/pkg/database"
+	"github.com/anrisys/quicket/event-service/pkg/errs"
+	"github.com/anrisys/quicket/event-service/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+type VenueServiceInterface interface {
+	Create(ctx context.Context, req *CreateVenueRequest, userPublicID string) (*VenueDTO, error)
+	FindByPublicID(ctx context.Context, publicID string) (*VenueDTO, error)
+	List(ctx context.Context, query *ListVenuesQuery) (*VenueListDTO, error)
+	Update(ctx context.Context, publicID string, req *UpdateVenueRequest, userPublicID string) (*VenueDTO, error)
+	Delete(ctx context.Context, publicID string, userPublicID string) error
+}
+
+type VenueService struct {
+	repo   VenueRepositoryInterface
+	users  UserReader
+	logger zerolog.Logger
+	redis *database.RedisClient
+}
+
+func NewVenueService(repo VenueRepositoryInterface, users UserReader, logger zerolog.Logger, redis *database.RedisClient) *VenueService {
+	return &VenueService{
+		repo:   repo,
+		users:  users,
+		logger: logger,
+		redis: redis,
+	}
+}
+
+// Create adds a venue owned by the user, who has to be an organizer or an
+// admin.
+func (s *VenueService) Create(ctx context.Context, req *CreateVenueRequest, userPublicID string) (*VenueDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("venue/service#create: %w", err)
+	}
+	if usr.Role != "admin" && usr.Role != "organizer" {
+		return nil, errs.NewForbiddenError("only organizers can create venues")
+	}
+
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public ID: %w", err)
+	}
+
+	venue := &Venue{
+		PublicID:  publicID,
+		Name:      req.Name,
+		Address:   req.Address,
+		TimeZone:  req.TimeZone,
+		Capacity:  req.Capacity,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		OwnerID:   uint(usr.ID),
+	}
+	if err := s.repo.CreateVenue(ctx, venue); err != nil {
+		return nil, s.mapVenueError(err)
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", venue.PublicID).
+		Str("user_id", userPublicID).
+		Str("time_zone", venue.TimeZone).
+		Msg("Venue created")
+	return prepareVenueDTO(venue), nil
+}
+
+func (s *VenueService) FindByPublicID(ctx context.Context, publicID string) (*VenueDTO, error) {
+	venue, err := s.repo.FindVenueByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapVenueError(err)
+	}
+	return prepareVenueDTO(venue), nil
+}
+
+func (s *VenueService) List(ctx context.Context, query *ListVenuesQuery) (*VenueListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	list := &VenueListDTO{
+		Venues: make([]VenueDTO, 0, limit),
+	}
+
+	filter := VenueFilter{AfterID: afterID}
+	if query.Owner != "" {
+		ownerID, err := s.users.GetUserID(ctx, query.Owner)
+		if errors.Is(err, errs.ErrNotFound) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("venue service#list: %w", err)
+		}
+		filter.OwnerID = *ownerID
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+	venues, err := s.repo.ListVenues(ctx, filter)
+	if err != nil {
+		return nil, s.mapVenueError(err)
+	}
+
+	if len(venues) > limit {
+		venues = venues[:limit]
+		list.NextCursor = util.EncodeCursor(venues[limit-1].ID)
+	}
+	for i := range venues {
+		list.Venues = append(list.Venues, *prepareVenueDTO(&venues[i]))
+	}
+	return list, nil
+}
+
+// Update changes the venue. Cached listings show the venue of each event,
+// so they are dropped; a cached event picks the change up when it expires.
+func (s *VenueService) Update(ctx context.Context, publicID string, req *UpdateVenueRequest, userPublicID string) (*VenueDTO, error) {
+	venue, err := s.findManagedVenue(ctx, publicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateVenue(ctx, venue.ID, VenueUpdate{
+		Name:      req.Name,
+		Address:   req.Address,
+		TimeZone:  req.TimeZone,
+		Capacity:  req.Capacity,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	})
+	if err != nil {
+		return nil, s.mapVenueError(err)
+	}
+	if _, err := s.redis.Incr(ctx, database.EventListVersionKey); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to invalidate cached event listings")
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", publicID).
+		Str("user_id", userPublicID).
+		Msg("Venue updated")
+	return prepareVenueDTO(updated), nil
+}
+
+// Delete removes a venue no event is held at.
+func (s *VenueService) Delete(ctx context.Context, publicID string, userPublicID string) error {
+	venue, err := s.findManagedVenue(ctx, publicID, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteVenue(ctx, venue.ID); err != nil {
+		return s.mapVenueError(err)
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", publicID).
+		Str("user_id", userPublicID).
+		Msg("Venue deleted")
+	return nil
+}
+
+// findManagedVenue loads the venue and makes sure the user is its owner or
+// an admin.
+func (s *VenueService) findManagedVenue(ctx context.Context, publicID, userPublicID string) (*Venue, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("venue/service#managed: %w", err)
+	}
+
+	venue, err := s.repo.FindVenueByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapVenueError(err)
+	}
+
+	if usr.Role != "admin" && venue.OwnerID != uint(usr.ID) {
+		return nil, errs.NewForbiddenError("only the owner of this venue can manage it")
+	}
+	return venue, nil
+}
+
+func (s *VenueService) mapVenueError(err error) error {
+	switch {
+	case errors.Is(err, ErrVenueNotFound):
+		return errs.NewErrNotFound("venue")
+	case errors.Is(err, ErrVenueInUse):
+		return errs.NewConflictError("venue has events; its time zone can not change and it can not be deleted")
+	default:
+		return fmt.Errorf("venue service: %w", err)
+	}
+}
+
+func validateCoordinates(latitude, longitude *float64) error {
+	if (latitude == nil) != (longitude == nil) {
+		return errs.NewValidationError("latitude and longitude must be given together")
+	}
+	return nil
+}
+
+func prepareVenueDTO(venue *Venue) *VenueDTO {
+	return &VenueDTO{
+		PublicID:  venue.PublicID,
+		Name:      venue.Name,
+		Address:   venue.Address,
+		TimeZone:  venue.TimeZone,
+		Capacity:  venue.Capacity,
+		Latitude:  venue.Latitude,
+		Longitude: venue.Longitude,
+		CreatedAt: venue.CreatedAt,
+		UpdatedAt: venue.UpdatedAt,
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ticket_types;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `events` ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1 AFTER `organizer_id`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE `outbox_messages` (
+    `id`            BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `message_id`    CHAR(36) NOT NULL UNIQUE,
+    `exchange`      VARCHAR(100) NOT NULL,
+    `routing_key`   VARCHAR(100) NOT NULL,
+    `payload`       JSON NOT NULL,
+    `attempts`      INT UNSIGNED NOT NULL DEFAULT 0,
+    `last_error`    TEXT NULL,
+    `available_at`  DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `sent_at`       DATETIME(3) NULL,
+    `created_at`    DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX `idx_outbox_messages_sent_at` (`sent_at`, `id`)
+) ENGINE = InnoDB;
\ No newline at end of file
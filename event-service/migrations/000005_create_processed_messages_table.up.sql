@@ -0,0 +1,4 @@
+CREATE TABLE `processed_messages` (
+    `message_id`    CHAR(36) NOT NULL PRIMARY KEY,
+    `processed_at`  DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)
+) ENGINE = InnoDB;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE `events`
+    DROP FOREIGN KEY `fk_events_series`,
+    DROP INDEX `idx_events_series_occurrence`,
+    DROP COLUMN `occurrence_start`,
+    DROP COLUMN `series_id`;
+
+DROP TABLE IF EXISTS `event_series`;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE `event_series`
+    DROP FOREIGN KEY `fk_event_series_venue`,
+    DROP COLUMN `venue_id`;
+
+ALTER TABLE `events`
+    DROP FOREIGN KEY `fk_events_venue`,
+    DROP COLUMN `venue_id`;
+
+DROP TABLE IF EXISTS `venues`;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE `venues` (
+    `id`          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `public_id`   CHAR(36) NOT NULL UNIQUE,
+    `name`        VARCHAR(256) NOT NULL,
+    `address`     VARCHAR(512) NOT NULL,
+    `time_zone`   VARCHAR(64) NOT NULL,
+    `capacity`    BIGINT UNSIGNED NOT NULL,
+    `latitude`    DOUBLE NULL,
+    `longitude`   DOUBLE NULL,
+    `owner_id`    BIGINT UNSIGNED NOT NULL,
+    `created_at`  DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at`  DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    INDEX `idx_venues_owner_id` (`owner_id`)
+) ENGINE = InnoDB;
+
+ALTER TABLE `events`
+    ADD COLUMN `venue_id` BIGINT UNSIGNED NULL AFTER `organizer_id`,
+    ADD CONSTRAINT `fk_events_venue` FOREIGN KEY (`venue_id`) REFERENCES `venues`(`id`) ON UPDATE CASCADE ON DELETE RESTRICT;
+
+ALTER TABLE `event_series`
+    ADD COLUMN `venue_id` BIGINT UNSIGNED NULL AFTER `max_seats`,
+    ADD CONSTRAINT `fk_event_series_venue` FOREIGN KEY (`venue_id`) REFERENCES `venues`(`id`) ON UPDATE CASCADE ON DELETE RESTRICT;
\ No newline at end of file
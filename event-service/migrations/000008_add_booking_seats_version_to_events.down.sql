@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    DROP COLUMN `booking_seats_version`;
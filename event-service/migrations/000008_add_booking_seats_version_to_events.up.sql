@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    ADD COLUMN `booking_seats_version` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `version`;
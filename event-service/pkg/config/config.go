@@ -8,4 +8,6 @@ type Config struct {
 	Server   *ServerConfig
 	Clients  *ClientServices
 	RabbitMQ *RabbitMQConfig
+	Outbox   *OutboxConfig
+	Series   *SeriesConfig
 }
\ No newline at end of file
@@ -12,6 +12,13 @@ func Load() (*Config, error) {
 	viper.AddConfigPath("../..")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "10s")
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "5m")
+	viper.SetDefault("EVENT_SERIES_HORIZON", "2160h")
+	viper.SetDefault("EVENT_SERIES_MATERIALIZE_INTERVAL", "1h")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, err
@@ -53,6 +60,16 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	var outboxConfig OutboxConfig
+	if err := viper.Unmarshal(&outboxConfig); err != nil {
+		return nil, err
+	}
+
+	var seriesConfig SeriesConfig
+	if err := viper.Unmarshal(&seriesConfig); err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		MySQL:  &mysqlConfig,
 		Log:    &logConfig,
@@ -61,6 +78,8 @@ func Load() (*Config, error) {
 		Server: &serverConfig,
 		Clients: &clientsConfig,
 		RabbitMQ: &rabbitMQConfig,
+		Outbox: &outboxConfig,
+		Series: &seriesConfig,
 	}
 
 	if err := validateConfig(cfg); err != nil {
@@ -88,5 +107,11 @@ func validateConfig(config *Config) error {
 	if err := config.RabbitMQ.Validate(); err != nil {
 		return err
 	}
+	if err := config.Outbox.Validate(); err != nil {
+		return err
+	}
+	if err := config.Series.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
\ No newline at end of file
@@ -15,7 +15,7 @@ type MySQLConfig struct {
 }
 
 func (m *MySQLConfig) DSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True",
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True&loc=UTC",
 		m.User, m.Password, m.Host, m.Port, m.Database, m.Charset)
 }
 
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// OutboxConfig tunes the relay that publishes the outbox to RabbitMQ.
+type OutboxConfig struct {
+	// PollInterval is how long the relay waits before looking again once
+	// the outbox is drained.
+	PollInterval time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	// BatchSize bounds the messages published per round.
+	BatchSize int `mapstructure:"OUTBOX_BATCH_SIZE"`
+	// MaxBackoff caps the wait before a message that failed to publish is
+	// tried again.
+	MaxBackoff time.Duration `mapstructure:"OUTBOX_MAX_BACKOFF"`
+}
+
+func (o *OutboxConfig) Validate() error {
+	if o.PollInterval <= 0 {
+		return errors.New("outbox poll interval must be greater than zero")
+	}
+	if o.BatchSize <= 0 {
+		return errors.New("outbox batch size must be greater than zero")
+	}
+	if o.MaxBackoff < o.PollInterval {
+		return errors.New("outbox max backoff must not be shorter than the poll interval")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// SeriesConfig tunes the materializer that creates the occurrences of
+// event series.
+type SeriesConfig struct {
+	// Horizon is how far ahead the occurrences of a series are created.
+	Horizon time.Duration `mapstructure:"EVENT_SERIES_HORIZON"`
+	// MaterializeInterval is how often series are brought up to the horizon.
+	MaterializeInterval time.Duration `mapstructure:"EVENT_SERIES_MATERIALIZE_INTERVAL"`
+}
+
+func (s *SeriesConfig) Validate() error {
+	if s.Horizon <= 0 {
+		return errors.New("event series horizon must be greater than zero")
+	}
+	if s.MaterializeInterval <= 0 {
+		return errors.New("event series materialize interval must be greater than zero")
+	}
+	return nil
+}
@@ -24,7 +24,14 @@ func NewRedisClient(cfg *config.Config) *RedisClient {
 	return &RedisClient{ client: rdb }
 }
 
-const EventKey = "event"
+const (
+	EventKey = "event"
+	// EventListKey prefixes cached event listings. The listings are keyed by
+	// the version stored at EventListVersionKey, which is bumped whenever an
+	// event changes so stale listings are never read again.
+	EventListKey = "event:list"
+	EventListVersionKey = "event:list:version"
+)
 
 func (c *RedisClient) Connect(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
@@ -34,10 +41,12 @@ func (c *RedisClient) Close() error {
 	return c.client.Close()
 }
 
+// Get decodes the value at key into dest. It returns redis.Nil when the key
+// does not exist.
 func (c *RedisClient) Get(ctx context.Context, key string, dest any) error {
 	val, err := c.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return nil
+		return err
 	}
 	if err != nil {
 		return fmt.Errorf("redis get failed: %w", err)
@@ -53,4 +62,12 @@ func (c *RedisClient) Set(ctx context.Context, key string, value any, ttl time.D
 	}
 
 	return c.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (c *RedisClient) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func (c *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
 }
\ No newline at end of file
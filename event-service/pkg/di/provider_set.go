@@ -4,6 +4,8 @@ import (
 	"github.com/anrisys/quicket/event-service/internal"
 	"github.com/anrisys/quicket/event-service/pkg/config"
 	"github.com/anrisys/quicket/event-service/pkg/database"
+	"github.com/anrisys/quicket/event-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/event-service/pkg/outbox"
 	"github.com/google/wire"
 )
 
@@ -13,6 +15,8 @@ var (
 		config.NewZerolog,
 		database.ConnectMySQL,
 		database.NewRedisClient,
+		rabbitmq.SetUpProviderSet,
+		outbox.NewRelay,
 	)
 	AppProviderSet = wire.NewSet(
 		ConfigSet,
@@ -20,9 +24,19 @@ var (
 		internal.NewUserServiceClient,
 		internal.NewEventService,
 		internal.NewEventHandler,
+		internal.NewMaterializer,
+		internal.NewSeriesService,
+		internal.NewSeriesHandler,
+		internal.NewVenueService,
+		internal.NewVenueHandler,
+		internal.NewSeatsConsumer,
 		wire.Bind(new(internal.UserReader), new(*internal.UserServiceClient)),
 		wire.Bind(new(internal.EventRepositoryInterface), new(*internal.EventRepository)),
 		wire.Bind(new(internal.EventServiceInterface), new(*internal.EventService)),
+		wire.Bind(new(internal.SeriesRepositoryInterface), new(*internal.EventRepository)),
+		wire.Bind(new(internal.SeriesServiceInterface), new(*internal.SeriesService)),
+		wire.Bind(new(internal.VenueRepositoryInterface), new(*internal.EventRepository)),
+		wire.Bind(new(internal.VenueServiceInterface), new(*internal.VenueService)),
 		wire.Struct(new(App), "*"),
 	)
 )
\ No newline at end of file
@@ -3,9 +3,24 @@ package di
 import (
 	"github.com/anrisys/quicket/event-service/internal"
 	"github.com/anrisys/quicket/event-service/pkg/config"
+	"github.com/anrisys/quicket/event-service/pkg/database"
+	"github.com/anrisys/quicket/event-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/event-service/pkg/outbox"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
 type App struct {
-	Config  *config.Config
-	Handler *internal.EventHandler
+	Config        *config.Config
+	Logger        zerolog.Logger
+	DB            *gorm.DB
+	Redis         *database.RedisClient
+	RabbitMQ      *rabbitmq.Client
+	Publisher     *rabbitmq.Publisher
+	Relay         *outbox.Relay
+	Handler       *internal.EventHandler
+	SeriesHandler *internal.SeriesHandler
+	VenueHandler  *internal.VenueHandler
+	Materializer  *internal.Materializer
+	SeatsConsumer *internal.SeatsConsumer
 }
\ No newline at end of file
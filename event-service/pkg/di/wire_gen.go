@@ -10,6 +10,8 @@ import (
 	"github.com/anrisys/quicket/event-service/internal"
 	"github.com/anrisys/quicket/event-service/pkg/config"
 	"github.com/anrisys/quicket/event-service/pkg/database"
+	"github.com/anrisys/quicket/event-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/event-service/pkg/outbox"
 )
 
 // Injectors from wire.go:
@@ -28,10 +30,30 @@ func InitializeApp() (*App, error) {
 	userServiceClient := internal.NewUserServiceClient(configConfig)
 	redisClient := database.NewRedisClient(configConfig)
 	eventService := internal.NewEventService(eventRepository, userServiceClient, logger, redisClient)
-	eventHandler := internal.NewEventHandler(eventService, logger)
+	client := rabbitmq.NewClient(configConfig, logger)
+	publisher := rabbitmq.NewPublisher(client)
+	relay := outbox.NewRelay(db, publisher, configConfig, logger)
+	materializer := internal.NewMaterializer(eventRepository, redisClient, configConfig, logger)
+	seriesService := internal.NewSeriesService(eventRepository, eventService, userServiceClient, materializer, logger)
+	eventHandler := internal.NewEventHandler(eventService, seriesService, logger)
+	seriesHandler := internal.NewSeriesHandler(seriesService, logger)
+	venueService := internal.NewVenueService(eventRepository, userServiceClient, logger, redisClient)
+	venueHandler := internal.NewVenueHandler(venueService, logger)
+	consumer := rabbitmq.NewConsumer(client, logger)
+	seatsConsumer := internal.NewSeatsConsumer(consumer, eventService, logger)
 	app := &App{
-		Config:  configConfig,
-		Handler: eventHandler,
+		Config:        configConfig,
+		Logger:        logger,
+		DB:            db,
+		Redis:         redisClient,
+		RabbitMQ:      client,
+		Publisher:     publisher,
+		Relay:         relay,
+		Handler:       eventHandler,
+		SeriesHandler: seriesHandler,
+		VenueHandler:  venueHandler,
+		Materializer:  materializer,
+		SeatsConsumer: seatsConsumer,
 	}
 	return app, nil
 }
@@ -0,0 +1,132 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+// retryDelay is how long Consume waits before setting up again after the
+// channel or the connection dropped.
+const retryDelay = 5 * time.Second
+
+// Subscription describes the durable queue a consumer reads and the topic
+// exchange bindings that feed it. Messages that are rejected without
+// requeue go to DeadLetterExchange when it is set.
+type Subscription struct {
+	Exchange           string
+	Queue              string
+	RoutingKeys        []string
+	DeadLetterExchange string
+}
+
+// Consumer hands the messages of a subscription to a handler one at a
+// time. Like the publisher it opens its own channel and sets it up again
+// after a failure.
+type Consumer struct {
+	client *Client
+	logger zerolog.Logger
+}
+
+func NewConsumer(client *Client, logger zerolog.Logger) *Consumer {
+	return &Consumer{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Consume declares sub and consumes it until ctx is cancelled. The
+// handler acks or nacks every delivery itself. On cancellation the broker
+// stops delivering, the message in hand is finished and anything delivered
+// but not handled yet is requeued.
+func (c *Consumer) Consume(ctx context.Context, sub Subscription, handler func(amqp.Delivery)) error {
+	log := c.logger.With().Str("queue", sub.Queue).Logger()
+
+	for {
+		err := c.consume(ctx, sub, handler)
+		if ctx.Err() != nil {
+			log.Info().Msg("consumer stopped")
+			return nil
+		}
+		log.Warn().Err(err).Dur("retry_in", retryDelay).Msg("consumer interrupted")
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("consumer stopped")
+			return nil
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (c *Consumer) consume(ctx context.Context, sub Subscription, handler func(amqp.Delivery)) error {
+	ch, err := c.client.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.ExchangeDeclare(sub.Exchange, "topic", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", sub.Exchange, err)
+	}
+
+	var args amqp.Table
+	if sub.DeadLetterExchange != "" {
+		args = amqp.Table{"x-dead-letter-exchange": sub.DeadLetterExchange}
+	}
+	if _, err := ch.QueueDeclare(sub.Queue, true, false, false, false, args); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", sub.Queue, err)
+	}
+
+	for _, routingKey := range sub.RoutingKeys {
+		if err := ch.QueueBind(sub.Queue, routingKey, sub.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue with routing key %s: %w", routingKey, err)
+		}
+	}
+
+	// One unacknowledged message at a time, so a slow handler does not
+	// hold back messages another replica could take.
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	// The queue name doubles as the consumer tag; a queue is consumed once
+	// per channel.
+	tag := sub.Queue
+	messages, err := ch.Consume(sub.Queue, tag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume queue %s: %w", sub.Queue, err)
+	}
+
+	c.logger.Info().
+		Str("queue", sub.Queue).
+		Strs("routing_keys", sub.RoutingKeys).
+		Msg("consumer setup complete")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range messages {
+			if ctx.Err() != nil {
+				msg.Nack(false, true)
+				continue
+			}
+			handler(msg)
+		}
+	}()
+
+	select {
+	case <-done:
+		return fmt.Errorf("deliveries of queue %s stopped: %w", sub.Queue, amqp.ErrClosed)
+	case <-ctx.Done():
+	}
+
+	if err := ch.Cancel(tag, false); err != nil {
+		c.logger.Warn().Err(err).Str("queue", sub.Queue).Msg("failed to cancel consumer")
+	}
+	<-done
+	return nil
+}
@@ -0,0 +1,121 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Message is what Publisher sends. ID is set as the AMQP message id so
+// consumers can drop duplicates.
+type Message struct {
+	ID         string
+	Exchange   string
+	RoutingKey string
+	Body       []byte
+	Timestamp  time.Time
+}
+
+// Publisher publishes persistent messages on a channel in confirm mode and
+// waits for the broker to take each one. The channel, and the connection
+// under it, are opened again after a failure.
+type Publisher struct {
+	client *Client
+
+	mu        sync.Mutex
+	channel   *amqp.Channel
+	exchanges map[string]bool
+}
+
+func NewPublisher(client *Client) *Publisher {
+	return &Publisher{
+		client:    client,
+		exchanges: map[string]bool{},
+	}
+}
+
+// Publish sends msg and returns once the broker has confirmed it. The
+// exchange is declared as a durable topic exchange the first time it is
+// used on a channel.
+func (p *Publisher) Publish(ctx context.Context, msg Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, err := p.open()
+	if err != nil {
+		return err
+	}
+
+	if !p.exchanges[msg.Exchange] {
+		if err := ch.ExchangeDeclare(msg.Exchange, "topic", true, false, false, false, nil); err != nil {
+			p.reset()
+			return fmt.Errorf("failed to declare exchange %s: %w", msg.Exchange, err)
+		}
+		p.exchanges[msg.Exchange] = true
+	}
+
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, msg.Exchange, msg.RoutingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.ID,
+		Timestamp:    msg.Timestamp,
+		Body:         msg.Body,
+	})
+	if err != nil {
+		p.reset()
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		p.reset()
+		return fmt.Errorf("failed to confirm message: %w", err)
+	}
+	if !acked {
+		return errors.New("message was not acknowledged by the broker")
+	}
+	return nil
+}
+
+// Close closes the publisher channel.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.channel == nil || p.channel.IsClosed() {
+		return nil
+	}
+	return p.channel.Close()
+}
+
+func (p *Publisher) open() (*amqp.Channel, error) {
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+
+	ch, err := p.client.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to put channel in confirm mode: %w", err)
+	}
+
+	p.channel = ch
+	p.exchanges = map[string]bool{}
+	return ch, nil
+}
+
+// reset drops the channel after a failure so the next publish opens a
+// fresh one.
+func (p *Publisher) reset() {
+	if p.channel != nil {
+		p.channel.Close()
+	}
+	p.channel = nil
+}
@@ -0,0 +1,73 @@
+package outbox
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Second)
+	future := now.Add(time.Second)
+
+	tests := []struct {
+		name  string
+		batch []Message
+		want  []uint64
+	}{
+		{
+			name:  "all due",
+			batch: []Message{{ID: 1, AvailableAt: past}, {ID: 2, AvailableAt: past}},
+			want:  []uint64{1, 2},
+		},
+		{
+			name:  "stops at a message waiting out its backoff",
+			batch: []Message{{ID: 1, AvailableAt: past}, {ID: 2, AvailableAt: future}, {ID: 3, AvailableAt: past}},
+			want:  []uint64{1},
+		},
+		{
+			name:  "another relay holds the head",
+			batch: []Message{{ID: 1, AvailableAt: past, LeasedUntil: &future}, {ID: 2, AvailableAt: past}},
+			want:  []uint64{},
+		},
+		{
+			name:  "takes over an expired lease",
+			batch: []Message{{ID: 1, AvailableAt: past, LeasedUntil: &past}},
+			want:  []uint64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []uint64{}
+			for _, msg := range due(tt.batch, now) {
+				got = append(got, msg.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	r := &Relay{interval: time.Second, maxBackoff: time.Minute}
+
+	tests := []struct {
+		attempts uint
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 6, want: 32 * time.Second},
+		{attempts: 7, want: time.Minute},
+		{attempts: 100, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package rrule parses and expands the subset of RFC 5545 recurrence rules
+// event series use: daily, weekly and monthly frequencies with an
+// interval, weekdays for weekly rules, and an optional count or end.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// untilLayout is the UTC date-time form of UNTIL; untilDateLayout is the
+// date form, which runs to the end of that day in UTC.
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule. A zero Count and a zero Until leave the
+// rule without an end.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	Count    int
+	Until    time.Time
+	// ByDay lists the weekdays of a weekly rule, Monday first. Empty means
+	// the weekday of the first occurrence.
+	ByDay []time.Weekday
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10". An
+// "RRULE:" prefix is allowed.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	r := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: INTERVAL must be a number", ErrInvalidRule)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: COUNT must be a number", ErrInvalidRule)
+			}
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: COUNT must be positive", ErrInvalidRule)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[strings.TrimSpace(day)]
+				if !ok {
+					return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRule, day)
+				}
+				if !slices.Contains(r.ByDay, wd) {
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRule, key)
+		}
+	}
+
+	sortWeekdays(r.ByDay)
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(untilDateLayout, value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must look like 20261231T235959Z or 20261231", ErrInvalidRule)
+}
+
+// Validate checks the rule is one this package can expand.
+func (r *Rule) Validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly:
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	default:
+		return fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRule)
+	}
+	if r.Interval <= 0 {
+		return fmt.Errorf("%w: INTERVAL must be positive", ErrInvalidRule)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("%w: COUNT must be positive", ErrInvalidRule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("%w: COUNT and UNTIL can not be used together", ErrInvalidRule)
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	return nil
+}
+
+// Bounded reports whether the rule ends, by count or by date.
+func (r *Rule) Bounded() bool {
+	return r.Count > 0 || !r.Until.IsZero()
+}
+
+// String formats the rule back into its canonical text.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			days = append(days, strings.ToUpper(wd.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// All yields the start of every occurrence in order, beginning at the
+// first one on or after start. Occurrences keep the wall clock time of
+// start in its location, so they do not drift across daylight saving
+// changes. Monthly occurrences skip months that lack the day of start, as
+// RFC 5545 does. An unbounded rule never stops on its own.
+func (r *Rule) All(start time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		produced := 0
+		emit := func(t time.Time) bool {
+			if t.Before(start) {
+				return true
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return false
+			}
+			produced++
+			if !yield(t) {
+				return false
+			}
+			return r.Count == 0 || produced < r.Count
+		}
+
+		y, m, d := start.Date()
+		hh, mm, ss := start.Clock()
+		ns := start.Nanosecond()
+		loc := start.Location()
+		at := func(year int, month time.Month, day int) time.Time {
+			return time.Date(year, month, day, hh, mm, ss, ns, loc)
+		}
+
+		switch r.Freq {
+		case Daily:
+			for k := 0; ; k++ {
+				if !emit(at(y, m, d+k*r.Interval)) {
+					return
+				}
+			}
+		case Weekly:
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			// Weeks start on Monday, as with the default WKST=MO.
+			monday := d - mondayOffset(start.Weekday())
+			for k := 0; ; k++ {
+				for _, wd := range days {
+					if !emit(at(y, m, monday+7*k*r.Interval+mondayOffset(wd))) {
+						return
+					}
+				}
+			}
+		case Monthly:
+			for k := 0; ; k++ {
+				first := time.Date(y, m+time.Month(k*r.Interval), 1, 0, 0, 0, 0, loc)
+				if d > daysIn(first.Year(), first.Month(), loc) {
+					continue
+				}
+				if !emit(at(first.Year(), first.Month(), d)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Between returns the starts of the occurrences from start on that fall in
+// [from, to).
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	var out []time.Time
+	for t := range r.All(start) {
+		if !t.Before(to) {
+			break
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Shift returns a copy of the rule for a series whose occurrences were all
+// moved by days calendar days: weekly weekdays move along with them.
+func (r *Rule) Shift(days int) *Rule {
+	shifted := *r
+	shifted.ByDay = make([]time.Weekday, 0, len(r.ByDay))
+	for _, wd := range r.ByDay {
+		shifted.ByDay = append(shifted.ByDay, time.Weekday(((int(wd)+days)%7+7)%7))
+	}
+	sortWeekdays(shifted.ByDay)
+	return &shifted
+}
+
+// mondayOffset is how many days wd comes after Monday.
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func sortWeekdays(days []time.Weekday) {
+	slices.SortFunc(days, func(a, b time.Weekday) int {
+		return mondayOffset(a) - mondayOffset(b)
+	})
+}
+
+func daysIn(year int, month time.Month, loc *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+}
@@ -0,0 +1,235 @@
+package rrule
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "daily", in: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and lower case", in: "rrule:freq=daily;interval=2", want: "FREQ=DAILY;INTERVAL=2"},
+		{name: "weekdays are sorted from monday", in: "RRULE:FREQ=WEEKLY;BYDAY=SU,WE,MO,WE;COUNT=4", want: "FREQ=WEEKLY;BYDAY=MO,WE,SU;COUNT=4"},
+		{name: "until date runs to end of day", in: "FREQ=MONTHLY;UNTIL=20261231", want: "FREQ=MONTHLY;UNTIL=20261231T235959Z"},
+		{name: "until date time", in: "FREQ=DAILY;UNTIL=20261231T100000Z", want: "FREQ=DAILY;UNTIL=20261231T100000Z"},
+		{name: "empty", in: "  ", wantErr: true},
+		{name: "missing freq", in: "COUNT=3", wantErr: true},
+		{name: "unsupported freq", in: "FREQ=YEARLY", wantErr: true},
+		{name: "zero interval", in: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "zero count", in: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{name: "count and until", in: "FREQ=DAILY;COUNT=2;UNTIL=20261231", wantErr: true},
+		{name: "byday on daily rule", in: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "unknown weekday", in: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "repeated key", in: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "malformed part", in: "FREQ=DAILY;COUNT", wantErr: true},
+		{name: "unsupported key", in: "FREQ=DAILY;BYMONTH=1", wantErr: true},
+		{name: "bad until", in: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidRule", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBounded(t *testing.T) {
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{rule: "FREQ=DAILY", want: false},
+		{rule: "FREQ=DAILY;COUNT=3", want: true},
+		{rule: "FREQ=DAILY;UNTIL=20261231", want: true},
+	}
+
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+		}
+		if got := r.Bounded(); got != tt.want {
+			t.Errorf("Parse(%q).Bounded() = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily with interval and count",
+			rule:  "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			start: time.Date(2026, 1, 30, 19, 0, 0, 0, jakarta),
+			want: []time.Time{
+				time.Date(2026, 1, 30, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 2, 1, 19, 0, 0, 0, jakarta),
+				time.Date(2026, 2, 3, 19, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name:  "weekly weekdays before start are skipped",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=4",
+			start: time.Date(2026, 3, 4, 10, 0, 0, 0, jakarta), // Wednesday
+			want: []time.Time{
+				time.Date(2026, 3, 4, 10, 0, 0, 0, jakarta),
+				time.Date(2026, 3, 6, 10, 0, 0, 0, jakarta),
+				time.Date(2026, 3, 9, 10, 0, 0, 0, jakarta),
+				time.Date(2026, 3, 11, 10, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name:  "weekly without weekdays uses the start weekday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=2",
+			start: time.Date(2026, 3, 5, 10, 0, 0, 0, jakarta),
+			want: []time.Time{
+				time.Date(2026, 3, 5, 10, 0, 0, 0, jakarta),
+				time.Date(2026, 3, 19, 10, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: time.Date(2026, 1, 31, 20, 0, 0, 0, jakarta),
+			want: []time.Time{
+				time.Date(2026, 1, 31, 20, 0, 0, 0, jakarta),
+				time.Date(2026, 3, 31, 20, 0, 0, 0, jakarta),
+				time.Date(2026, 5, 31, 20, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=DAILY;UNTIL=20260102T120000Z",
+			start: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "wall clock is kept across daylight saving",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: time.Date(2026, 3, 7, 19, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2026, 3, 7, 19, 0, 0, 0, newYork),
+				time.Date(2026, 3, 8, 19, 0, 0, 0, newYork),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			var got []time.Time
+			for occ := range r.All(tt.start) {
+				got = append(got, occ)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	r, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name: "from is inclusive and to exclusive",
+			from: time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "window before start",
+			from: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Between(start, tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		days int
+		want string
+	}{
+		{name: "forward across sunday", rule: "FREQ=WEEKLY;BYDAY=MO,SA", days: 2, want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "backward", rule: "FREQ=WEEKLY;BYDAY=MO,WE", days: -1, want: "FREQ=WEEKLY;BYDAY=TU,SU"},
+		{name: "whole weeks", rule: "FREQ=WEEKLY;BYDAY=TH", days: 14, want: "FREQ=WEEKLY;BYDAY=TH"},
+		{name: "daily rule is unchanged", rule: "FREQ=DAILY;COUNT=5", days: 3, want: "FREQ=DAILY;COUNT=5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			if got := r.Shift(tt.days).String(); got != tt.want {
+				t.Errorf("Shift(%d) = %q, want %q", tt.days, got, tt.want)
+			}
+			if got := r.String(); got != tt.rule {
+				t.Errorf("Shift changed the original rule to %q", got)
+			}
+		})
+	}
+}
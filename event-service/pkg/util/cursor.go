@@ -0,0 +1,72 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turns the id of the last row of a page into an opaque cursor
+// clients pass back to fetch the next page.
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor is the inverse of EncodeCursor. An empty cursor decodes to 0,
+// meaning the first page.
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return uint(id), nil
+}
+
+// EncodeTimeCursor is EncodeCursor for lists ordered by a time column, with
+// the id breaking ties between rows at the same time.
+func EncodeTimeCursor(t time.Time, id uint) string {
+	raw := strconv.FormatInt(t.UnixNano(), 10) + "." + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTimeCursor is the inverse of EncodeTimeCursor. An empty cursor
+// decodes to the zero time and 0, meaning the first page.
+func DecodeTimeCursor(cursor string) (time.Time, uint, error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	nanos, rawID, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	ns, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil || id == 0 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	return time.Unix(0, ns), uint(id), nil
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    uint
+		wantErr bool
+	}{
+		{name: "round trip", cursor: EncodeCursor(42), want: 42},
+		{name: "empty is the first page", cursor: "", want: 0},
+		{name: "not base64", cursor: "!!!", wantErr: true},
+		{name: "not a number", cursor: "YWJj", wantErr: true},
+		{name: "zero id", cursor: EncodeCursor(0), wantErr: true},
+		{name: "time cursor", cursor: EncodeTimeCursor(time.Unix(0, 5), 7), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q) error = %v", tt.cursor, err)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeCursor(%q) = %d, want %d", tt.cursor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeCursor(t *testing.T) {
+	at := time.Date(2026, 5, 4, 19, 0, 0, 123, time.UTC)
+
+	tests := []struct {
+		name    string
+		cursor  string
+		wantAt  time.Time
+		wantID  uint
+		wantErr bool
+	}{
+		{name: "round trip keeps nanoseconds", cursor: EncodeTimeCursor(at, 42), wantAt: at, wantID: 42},
+		{name: "empty is the first page", cursor: ""},
+		{name: "id cursor", cursor: EncodeCursor(42), wantErr: true},
+		{name: "not base64", cursor: "!!!", wantErr: true},
+		{name: "zero id", cursor: EncodeTimeCursor(at, 0), wantErr: true},
+		{name: "bad time", cursor: "eC40Mg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAt, gotID, err := DecodeTimeCursor(tt.cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("DecodeTimeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeTimeCursor(%q) error = %v", tt.cursor, err)
+			}
+			if !gotAt.Equal(tt.wantAt) || gotID != tt.wantID {
+				t.Errorf("DecodeTimeCursor(%q) = %v, %d, want %v, %d", tt.cursor, gotAt, gotID, tt.wantAt, tt.wantID)
+			}
+		})
+	}
+}
@@ -4,12 +4,9 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/anrisys/quicket/event-service/internal"
 	"github.com/anrisys/quicket/event-service/pkg/di"
 	"github.com/anrisys/quicket/event-service/pkg/middleware"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -32,11 +29,6 @@ func SetupRouter(app *di.App) *gin.Engine {
 		)
 	}))
 
-	// Custom validator
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		internal.RegisterCustomValidation(v)
-	}
-
 	// Middlewares
 	r.Use(middleware.ZerologLogger(), gin.Recovery(), middleware.ErrorMiddleware())
 
@@ -51,11 +43,31 @@ func SetupRouter(app *di.App) *gin.Engine {
 
 func registerRoutes(r *gin.Engine, app *di.App) {
 	public := r.Group("/api/v1/events")
+	public.GET("", app.Handler.List)
+	public.GET("/series", app.SeriesHandler.List)
+	public.GET("/series/:publicID", app.SeriesHandler.Get)
 	public.GET("/:publicID", app.Handler.GetEventByPublicID)
 	
 	protected := r.Group("/api/v1/events")
 	protected.Use(middleware.JWTAuthMiddleware(app.Config.JWT.JWTSecret))
 	{
 		protected.POST("/", app.Handler.Create)
+		protected.POST("/series", app.SeriesHandler.Create)
+		protected.PATCH("/:publicID", app.Handler.Update)
+		protected.DELETE("/:publicID", app.Handler.Delete)
+		protected.POST("/:publicID/ticket-types", app.Handler.CreateTicketType)
+		protected.PATCH("/:publicID/ticket-types/:ticketTypeID", app.Handler.UpdateTicketType)
+	}
+
+	publicVenues := r.Group("/api/v1/venues")
+	publicVenues.GET("", app.VenueHandler.List)
+	publicVenues.GET("/:publicID", app.VenueHandler.Get)
+
+	protectedVenues := r.Group("/api/v1/venues")
+	protectedVenues.Use(middleware.JWTAuthMiddleware(app.Config.JWT.JWTSecret))
+	{
+		protectedVenues.POST("/", app.VenueHandler.Create)
+		protectedVenues.PATCH("/:publicID", app.VenueHandler.Update)
+		protectedVenues.DELETE("/:publicID", app.VenueHandler.Delete)
 	}
 }
\ No newline at end of file
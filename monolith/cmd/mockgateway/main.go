@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/payment/mockgateway"
+)
+
+// Runs the mock payment gateway the http payment provider talks to, e.g.
+// PAYMENT_PROVIDER=http PAYMENT_GATEWAY_URL=http://localhost:9090
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	flag.Parse()
+
+	log.Printf("mock payment gateway listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mockgateway.NewServer()); err != nil {
+		log.Fatalf("mock gateway failed: %v", err)
+	}
+}
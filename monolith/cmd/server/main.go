@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	// Venue time zones are loaded from the embedded zone database, so they
+	// do not depend on the image shipping one.
+	_ "time/tzdata"
 
 	"github.com/anrisys/quicket/internal/router"
 	"github.com/anrisys/quicket/pkg/di"
+	"github.com/anrisys/quicket/pkg/lifecycle"
 )
 
 // @title Quicket API
@@ -31,11 +38,43 @@ func main() {
     if err != nil {
         log.Fatalf("Failed to initialize app: %v", err)
     }
-    
+
+    if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+        reconcile(app, os.Args[2:])
+        return
+    }
+
+    lc := lifecycle.New(app.Config.Server.ShutdownTimeout, app.Logger)
+
+    sqlDB, err := app.DB.DB()
+    if err != nil {
+        log.Fatalf("Failed to get database pool: %v", err)
+    }
+    lc.OnClose("mysql", sqlDB.Close)
+
+    lc.Go("booking expirer", loop(app.BookingExpirer.Run))
+    lc.Go("event series materializer", loop(app.EventSeriesMaterializer.Run))
+    lc.Go("waitlist sweeper", loop(app.WaitlistSweeper.Run))
+    lc.Go("payment worker", loop(app.PaymentWorker.Run))
+    lc.Go("refund retrier", loop(app.RefundService.Run))
+    lc.Go("payment reconciler", loop(app.Reconciler.Run))
+
     r := router.SetupRouter(app)
-    
-    addr := fmt.Sprintf(":%s", app.Config.Server.Port)
-	if err := r.Run(addr); err != nil {
-        log.Fatalf("Server failed :%v", err)
+
+    lc.Serve(&http.Server{
+        Addr: fmt.Sprintf(":%s", app.Config.Server.Port),
+        Handler: r,
+    })
+
+    if err := lc.Wait(); err != nil {
+        log.Fatalf("Server stopped with errors: %v", err)
+    }
+}
+
+// loop adapts a background loop that only stops with its context.
+func loop(run func(ctx context.Context)) func(ctx context.Context) error {
+    return func(ctx context.Context) error {
+        run(ctx)
+        return nil
     }
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/anrisys/quicket/internal/payment"
+	"github.com/anrisys/quicket/pkg/di"
+)
+
+// reconcile compares one day of provider charges with our payments, stores
+// the report and prints it. It exits with status 1 when anything is off.
+//
+//	go run ./cmd/server reconcile
+//	go run ./cmd/server reconcile -date 2026-01-31
+//
+// The simulator and fake providers keep their charges in memory, so a
+// fresh process has none on record; use the http provider to reconcile
+// against a gateway that outlives the server.
+func reconcile(app *di.App, args []string) {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	date := fs.String("date", "", "day to reconcile as YYYY-MM-DD (yesterday when empty)")
+	fs.Parse(args)
+
+	from, to := payment.ReconcileWindow(time.Now())
+	if *date != "" {
+		day, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+		if err != nil {
+			log.Fatalf("parse date: %v", err)
+		}
+		from, to = day, day.AddDate(0, 0, 1)
+	}
+
+	report, err := app.Reconciler.Reconcile(context.Background(), from, to)
+	if err != nil {
+		log.Fatalf("reconcile: %v", err)
+	}
+
+	fmt.Printf("report %s (%s, %s to %s): %d charges checked, %d mismatches\n",
+		report.PublicID, report.Provider, from.Format(time.DateTime), to.Format(time.DateTime),
+		report.Checked, report.MismatchCount)
+	for _, m := range report.Mismatches {
+		fmt.Printf("  %-26s charge=%s payment=%s booking=%s provider=%s local=%s\n",
+			m.Kind, value(m.ProviderRef), value(m.PaymentPublicID), value(m.BookingPublicID),
+			value(m.ProviderValue), value(m.LocalValue))
+	}
+
+	if report.MismatchCount > 0 {
+		os.Exit(1)
+	}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
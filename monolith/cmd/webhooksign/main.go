@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/anrisys/quicket/internal/payment"
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/google/uuid"
+)
+
+// Signs a payment webhook payload the way the provider does, and either
+// prints the headers or posts it to a running server.
+//
+//	go run ./cmd/webhooksign -type payment.succeeded -payment <id> -booking <id> -amount 50000.00 -currency IDR
+//	go run ./cmd/webhooksign -file event.json -url http://localhost:8080/api/v1/payments/webhook
+func main() {
+	secret := flag.String("secret", os.Getenv("PAYMENT_WEBHOOK_SECRET"), "webhook secret (defaults to $PAYMENT_WEBHOOK_SECRET)")
+	file := flag.String("file", "", "JSON payload to sign; a sample event is built from the flags below when empty")
+	url := flag.String("url", "", "post the signed payload to this URL instead of printing it")
+	eventType := flag.String("type", payment.EventSucceeded, "sample event type")
+	eventID := flag.String("id", "", "sample event ID (random when empty)")
+	paymentID := flag.String("payment", "", "sample payment public ID")
+	bookingID := flag.String("booking", "", "sample booking public ID")
+	chargeID := flag.String("charge", "", "sample provider charge ID")
+	amount := flag.String("amount", "0", "sample amount, e.g. 50000.00")
+	currency := flag.String("currency", string(money.DefaultCurrency), "sample amount currency")
+	age := flag.Duration("age", 0, "backdate the timestamp, e.g. 10m to try a stale delivery")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("a webhook secret is required")
+	}
+
+	amt, err := money.Parse(*amount, money.Currency(*currency))
+	if err != nil {
+		log.Fatalf("parse amount: %v", err)
+	}
+
+	body, err := payload(*file, *eventType, *eventID, *paymentID, *bookingID, *chargeID, amt)
+	if err != nil {
+		log.Fatalf("build payload: %v", err)
+	}
+
+	ts := time.Now().Add(-*age).Unix()
+	signature := payment.SignWebhook(*secret, ts, body)
+
+	if *url == "" {
+		fmt.Printf("%s: %d\n%s: %s\n\n%s\n", payment.HeaderWebhookTimestamp, ts, payment.HeaderWebhookSignature, signature, body)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(payment.HeaderWebhookTimestamp, fmt.Sprint(ts))
+	req.Header.Set(payment.HeaderWebhookSignature, signature)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatalf("post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	fmt.Printf("%s\n%s\n", resp.Status, respBody)
+}
+
+func payload(file, eventType, eventID, paymentID, bookingID, chargeID string, amount money.Money) ([]byte, error) {
+	if file != "" {
+		return os.ReadFile(file)
+	}
+
+	if eventID == "" {
+		eventID = "evt_" + uuid.NewString()
+	}
+	return json.Marshal(map[string]any{
+		"id": eventID,
+		"type": eventType,
+		"data": map[string]any{
+			"payment_id": paymentID,
+			"booking_id": bookingID,
+			"charge_id": chargeID,
+			"amount": amount,
+		},
+	})
+}
@@ -1,12 +1,64 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
 
 type BookingDTO struct {
 	PublicID  string    `json:"id"`
 	EventID   string    `json:"event_id"`
 	UserID    string    `json:"user_id"`
+	TicketTypeID string `json:"ticket_type_id,omitempty"`
 	Seats     uint      `json:"seats"`
+	SeatIDs   []string  `json:"seat_ids,omitempty"`
+	TotalPrice money.Money `json:"total_price"`
 	Status    string    `json:"status"`
 	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type CancelBookingDTO struct {
+	PublicID string `json:"id"`
+	Seats    uint   `json:"seats"`
+	Status   string `json:"status"`
+}
+
+type BookingEventDTO struct {
+	PublicID  string    `json:"id"`
+	Title     string    `json:"title"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+type BookingSummaryDTO struct {
+	PublicID   string          `json:"id"`
+	Seats      uint            `json:"seats"`
+	TotalPrice money.Money     `json:"total_price"`
+	RefundedAmount money.Money `json:"refunded_amount"`
+	Status     string          `json:"status"`
+	ExpiredAt  time.Time       `json:"expired_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Event      BookingEventDTO `json:"event"`
+}
+
+// StatusChangeDTO is one entry of a booking's status history. From is null
+// for the entry written when the booking was created.
+type StatusChangeDTO struct {
+	From   *string   `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason"`
+	Actor  string    `json:"actor"`
+	At     time.Time `json:"at"`
+}
+
+type BookingDetailDTO struct {
+	BookingSummaryDTO `json:",inline"`
+	PaymentStatus     *string           `json:"payment_status"`
+	History           []StatusChangeDTO `json:"history"`
+}
+
+type BookingListDTO struct {
+	Bookings   []BookingSummaryDTO `json:"bookings"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
\ No newline at end of file
@@ -1,5 +1,14 @@
 package dto
 
 type CreateBookingRequest struct {
-	Seats uint `json:"seats" binding:"required,gt=0"`
+	Seats        uint     `json:"seats" binding:"required_without=SeatIDs"`
+	TicketTypeID string   `json:"ticket_type_id" binding:"omitempty,max=36"`
+	SeatIDs      []string `json:"seat_ids" binding:"omitempty,max=50,dive,required,max=36"`
+}
+
+type ListBookingsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending confirmed failed expired cancelled refunded"`
+	When   string `form:"when" binding:"omitempty,oneof=upcoming past"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
 }
\ No newline at end of file
@@ -8,4 +8,24 @@ type ResponseSuccess struct {
 type CreateBookingSuccessResponse struct {
 	ResponseSuccess `json:",inline"`
 	Booking         BookingDTO `json:"booking"`
+}
+
+type CancelBookingSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Booking         CancelBookingDTO `json:"booking"`
+}
+
+type ListBookingsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	BookingListDTO  `json:",inline"`
+}
+
+type BookingDetailSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Booking         BookingDetailDTO `json:"booking"`
+}
+
+type RetryPaymentSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Booking         BookingSummaryDTO `json:"booking"`
 }
\ No newline at end of file
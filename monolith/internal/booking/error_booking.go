@@ -8,5 +8,17 @@ var (
 	ErrEventNotFound = errors.New("event not found")
 	ErrSeatsUnavailable = errors.New("no available seats")
 	ErrNotEnoughSeats = errors.New("not enough setas")
+	ErrWaitlistAhead = errors.New("seats are held for the waitlist")
+	ErrTicketTypeRequired = errors.New("ticket type is required for this event")
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrExceedsMaxPerOrder = errors.New("seats exceed the ticket type per-order limit")
+	ErrSeatSelectionRequired = errors.New("seat selection is required for this event")
+	ErrNoSeatMap = errors.New("event has no seat map")
+	ErrSeatNotFound = errors.New("seat not found")
+	ErrSeatUnavailable = errors.New("seat is not available")
+	ErrNotBookingOwner = errors.New("booking belongs to another user")
+	ErrBookingNotCancellable = errors.New("booking can not be cancelled in its current status")
+	ErrEventAlreadyStarted = errors.New("event has already started")
+	ErrInvalidTransition = errors.New("invalid booking status transition")
 	ErrDB = errors.New("database error")
 )
\ No newline at end of file
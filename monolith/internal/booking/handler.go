@@ -29,14 +29,17 @@ func NewHandler(svc ServiceInterface, logger zerolog.Logger) *Handler {
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateBookingRequest true "Event creation data" 
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 201 {object} dto.CreateBookingSuccessResponse
 // @Failure 400 {object} errs.ErrorResponse "Validation error"
 // @Failure 401 {object} errs.ErrorResponse "Unauthorized"
-// @Router /api/v1/events/bookings/:eventID [post]
+// @Failure 409 {object} errs.ErrorResponse "Request with the same Idempotency-Key in progress"
+// @Failure 422 {object} errs.ErrorResponse "Idempotency-Key reused with a different request"
+// @Router /api/v1/bookings/{id} [post]
 func (h *Handler) Create(c *gin.Context) {
 	ctx := c.Request.Context()
 	userPublicID := c.GetString("publicID")
-	eventID := c.Param("eventID")
+	eventID := c.Param("id")
 
 	var req *dto.CreateBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -60,4 +63,151 @@ func (h *Handler) Create(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, response)
+}
+
+// RetryPayment godoc
+// @Summary Retry booking payment
+// @Description Queue a new payment for a pending booking whose last payment failed
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Booking public ID"
+// @Success 202 {object} dto.RetryPaymentSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not found"
+// @Failure 409 {object} errs.ErrorResponse "Conflict error"
+// @Failure 503 {object} errs.ErrorResponse "Payment system busy"
+// @Router /api/v1/bookings/{id}/payments [post]
+func (h *Handler) RetryPayment(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingID := c.Param("id")
+
+	booking, err := h.svc.RetryPayment(ctx, userPublicID, bookingID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.RetryPaymentSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Payment queued",
+		},
+		Booking: *booking,
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// Cancel godoc
+// @Summary Cancel booking
+// @Description Cancel a booking owned by the current user and release its seats
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Booking public ID"
+// @Success 200 {object} dto.CancelBookingSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not found"
+// @Failure 409 {object} errs.ErrorResponse "Conflict error"
+// @Router /api/v1/bookings/{id}/cancel [post]
+func (h *Handler) Cancel(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingID := c.Param("id")
+
+	booking, err := h.svc.Cancel(ctx, userPublicID, bookingID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.CancelBookingSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Booking cancelled successfully",
+		},
+		Booking: *booking,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List my bookings
+// @Description List the bookings of the current user, newest first
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Booking status" Enums(pending, success, failed, expired, cancelled)
+// @Param when query string false "Event time" Enums(upcoming, past)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} dto.ListBookingsSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Router /api/v1/bookings [get]
+func (h *Handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var query dto.ListBookingsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.svc.ListMine(ctx, userPublicID, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListBookingsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Bookings retrieved successfully",
+		},
+		BookingListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get booking
+// @Description Get a booking of the current user with its event and payment status
+// @Tags Bookings
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Booking public ID"
+// @Success 200 {object} dto.BookingDetailSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not found"
+// @Router /api/v1/bookings/{publicID} [get]
+func (h *Handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingID := c.Param("publicID")
+
+	booking, err := h.svc.GetMine(ctx, userPublicID, bookingID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.BookingDetailSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code: "SUCCESS",
+			Message: "Booking retrieved successfully",
+		},
+		Booking: *booking,
+	}
+
+	c.JSON(http.StatusOK, response)
 }
\ No newline at end of file
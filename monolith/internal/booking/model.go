@@ -3,20 +3,63 @@ package booking
 import (
 	"time"
 
+	"github.com/anrisys/quicket/pkg/money"
 	"gorm.io/gorm"
 )
 
+const (
+	StatusPending = "pending"
+	StatusConfirmed = "confirmed"
+	StatusFailed = "failed"
+	StatusExpired = "expired"
+	StatusCancelled = "cancelled"
+	StatusRefunded = "refunded"
+)
+
+// Actors recorded in the status history.
+const (
+	ActorUser = "user"
+	ActorSystem = "system"
+)
+
+// Seat statuses of events with a seat map.
+const (
+	SeatAvailable = "available"
+	SeatHeld = "held"
+	SeatSold = "sold"
+)
+
 type Booking struct {
 	gorm.Model
 	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex"`
 	EventID uint `gorm:"column:event_id;not null"`
+	TicketTypeID *uint `gorm:"column:ticket_type_id"`
 	UserID uint `gorm:"column:user_id;not null"`
 	Seats uint `gorm:"column:seats;not null"`
-	TotalPrice float32 `gorm:"column:total_price;not null"`
-	Status string `gorm:"column:status;type:ENUM('success', 'failed', 'pending');default:'pending'"`
+	TotalPrice money.Money `gorm:"column:total_price;not null"`
+	Currency money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Status string `gorm:"column:status;type:ENUM('pending', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded');default:'pending'"`
 	ExpiredAt time.Time `gorm:"column:expired_at;not null"`
 }
 
 func (b *Booking) TableName() string {
 	return "bookings"
+}
+
+// StatusChange is one transition of a booking. FromStatus is nil for the
+// row written when the booking is created. ActorID is the user who caused
+// the change, and nil for system actors.
+type StatusChange struct {
+	ID uint `gorm:"primarykey"`
+	BookingID uint `gorm:"column:booking_id;not null;index"`
+	FromStatus *string `gorm:"column:from_status"`
+	ToStatus string `gorm:"column:to_status;not null"`
+	Reason string `gorm:"column:reason;size:255;not null"`
+	ActorType string `gorm:"column:actor_type;type:ENUM('user', 'system');not null"`
+	ActorID *uint `gorm:"column:actor_id"`
+	CreatedAt time.Time
+}
+
+func (c *StatusChange) TableName() string {
+	return "booking_status_history"
 }
\ No newline at end of file
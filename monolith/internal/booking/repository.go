@@ -4,23 +4,121 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	commonDTO "github.com/anrisys/quicket/internal/dto"
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
-	Create(ctx context.Context, b *Booking) (*Booking, error)
+	Create(ctx context.Context, b *Booking, sel Selection) (*Booking, error)
 	FindSimpleDTO(ctx context.Context, publicID string) (*commonDTO.SimpleBookingDTO, error)
+	ExpirePending(ctx context.Context, now time.Time, limit int) ([]ReleasedBooking, error)
+	Cancel(ctx context.Context, publicID string, userID uint, now time.Time) (*Booking, error)
+	FailPending(ctx context.Context, bookingID uint, reason string) error
+	ListByUser(ctx context.Context, filter ListFilter) ([]BookingView, error)
+	FindView(ctx context.Context, publicID string) (*BookingView, error)
+	History(ctx context.Context, bookingID uint) ([]StatusChange, error)
 }
 
+// Selection is what a booking asks for besides a number of seats: a ticket
+// type and, for events with a seat map, the exact seats.
+type Selection struct {
+	TicketTypePublicID string
+	SeatPublicIDs []string
+}
+
+// ListFilter narrows the bookings returned by ListByUser. AfterID is the id
+// of the last booking of the previous page; zero starts from the newest.
+type ListFilter struct {
+	UserID uint
+	Status string
+	Upcoming *bool
+	Now time.Time
+	AfterID uint
+	Limit int
+}
+
+// BookingView is a booking joined with the event it is for and, when
+// loaded through FindView, the status of its latest payment.
+type BookingView struct {
+	ID uint
+	PublicID string
+	UserID uint
+	Seats uint
+	TotalPrice money.Money
+	Currency money.Currency
+	Status string
+	ExpiredAt time.Time
+	CreatedAt time.Time
+	EventPublicID string
+	EventTitle string
+	EventStartDate time.Time
+	EventEndDate time.Time
+	PaymentStatus *string
+	RefundedAmount money.Money
+}
+
+const bookingViewColumns = "bookings.id, bookings.public_id, bookings.user_id, bookings.seats, " +
+	"bookings.total_price, bookings.currency, bookings.status, bookings.expired_at, bookings.created_at, " +
+	"events.public_id AS event_public_id, events.title AS event_title, " +
+	"events.start_date AS event_start_date, events.end_date AS event_end_date, " +
+	"COALESCE((SELECT SUM(refunds.amount) FROM refunds " +
+	"WHERE refunds.booking_id = bookings.id AND refunds.status = 'succeeded'), 0) AS refunded_amount"
+
 type eventRow struct {
 	ID uint
 	AvailableSeats uint64
 }
 
+type eventStartRow struct {
+	StartDate time.Time
+}
+
+type paymentRow struct {
+	ID uint
+	Amount money.Money
+	Currency money.Currency
+}
+
+type refundRow struct {
+	PublicID string
+	PaymentID uint
+	BookingID uint
+	UserID uint
+	Amount money.Money
+	Currency money.Currency
+	Status string
+}
+
+type seatRow struct {
+	ID uint
+	Status string
+}
+
+type ticketTypeRow struct {
+	ID uint
+	Price money.Money
+	Currency money.Currency
+	Available uint64
+	MaxPerOrder uint
+}
+
+// ReleasedBooking is a booking whose seats were given back to its event and
+// ticket type.
+type ReleasedBooking struct {
+	ID uint
+	EventID uint
+	TicketTypeID *uint
+	Seats uint
+}
+
 type GormRepository struct {
 	db *gorm.DB
 	logger zerolog.Logger
@@ -35,7 +133,7 @@ func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
 
 func (r *GormRepository) FindSimpleDTO(ctx context.Context, publicID string) (*commonDTO.SimpleBookingDTO, error) {
 	var dto commonDTO.SimpleBookingDTO
-	if err := r.db.WithContext(ctx).Select("id, user_id, total_price AS amount").Where("public_id = ?", publicID).Take(dto).Error; err != nil {
+	if err := r.db.WithContext(ctx).Table("bookings").Select("id, user_id, total_price AS amount, currency").Where("public_id = ?", publicID).Take(&dto).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrBookingNotFound
 		}
@@ -47,8 +145,28 @@ func (r *GormRepository) FindSimpleDTO(ctx context.Context, publicID string) (*c
 	return &dto, nil
 }
 
-func (r *GormRepository) Create(ctx context.Context, b *Booking) (*Booking, error) {
+// Create persists b and takes its seats from the event and, when
+// sel.TicketTypePublicID is set, from that ticket type. The total price is
+// computed here from the ticket type price read under the row lock. Events
+// with a seat map are booked through createReserved instead.
+//
+// Seats that waiting entries of the waitlist could be offered belong to
+// them, so a booking that would take any of them is refused; see
+// checkWaitlist. Offered entries do not count: their seats are already taken
+// from the event.
+func (r *GormRepository) Create(ctx context.Context, b *Booking, sel Selection) (*Booking, error) {
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reserved, err := r.hasSeatMap(tx, b.EventID)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return r.createReserved(tx, b, sel)
+		}
+		if len(sel.SeatPublicIDs) > 0 {
+			return ErrNoSeatMap
+		}
+
 		var ev eventRow
 		if err := tx.Table("events").
 			Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
@@ -70,12 +188,12 @@ func (r *GormRepository) Create(ctx context.Context, b *Booking) (*Booking, erro
 			return ErrNotEnoughSeats
 		}
 
-		if err := tx.Create(b).Error; err != nil  {
-			r.logger.Error().Err(err).
-				Uint("event_id", b.EventID).
-				Uint("user_id", b.UserID).
-				Msg("insert booking failed")
-			return fmt.Errorf("%w: %v", ErrDB, err)
+		if err := r.takeTicketTypeSeats(tx, b, sel.TicketTypePublicID); err != nil {
+			return err
+		}
+
+		if err := r.insert(tx, b); err != nil {
+			return err
 		}
 
 		if err := tx.Table("events").
@@ -89,8 +207,517 @@ func (r *GormRepository) Create(ctx context.Context, b *Booking) (*Booking, erro
 			return fmt.Errorf("%w: %v", ErrDB, err)
 		}
 
-		return nil
+		return r.checkWaitlist(tx, b)
 	})
 
 	return b, err
+}
+
+// createReserved books the seats picked in sel. Only the requested seat rows
+// are locked, so bookings for different seats of the same event do not
+// contend on the event row; the event counter is decremented with a guarded
+// update instead, which also locks the event row for checkWaitlist. Locks
+// are taken in the order seats, event, ticket type, the same order
+// ReleaseSeats uses.
+func (r *GormRepository) createReserved(tx *gorm.DB, b *Booking, sel Selection) error {
+	if len(sel.SeatPublicIDs) == 0 {
+		return ErrSeatSelectionRequired
+	}
+
+	var seats []seatRow
+	if err := tx.Table("seats").
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Select("id, status").
+		Where("event_id = ? AND public_id IN ?", b.EventID, sel.SeatPublicIDs).
+		Order("id").
+		Find(&seats).Error; err != nil {
+		if isLockNotAvailable(err) {
+			return ErrSeatUnavailable
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", b.EventID).
+			Msg("lock/select seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if len(seats) != len(sel.SeatPublicIDs) {
+		return ErrSeatNotFound
+	}
+
+	seatIDs := make([]uint, 0, len(seats))
+	for _, seat := range seats {
+		if seat.Status != SeatAvailable {
+			return ErrSeatUnavailable
+		}
+		seatIDs = append(seatIDs, seat.ID)
+	}
+
+	res := tx.Table("events").
+		Where("id = ? AND available_seats >= ?", b.EventID, b.Seats).
+		Update("available_seats", gorm.Expr("available_seats - ?", b.Seats))
+	if res.Error != nil {
+		r.logger.Error().Err(res.Error).
+			Uint("event_id", b.EventID).
+			Uint("seats", b.Seats).
+			Msg("deduct seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotEnoughSeats
+	}
+
+	if err := r.takeTicketTypeSeats(tx, b, sel.TicketTypePublicID); err != nil {
+		return err
+	}
+
+	if err := r.insert(tx, b); err != nil {
+		return err
+	}
+
+	if err := tx.Table("seats").
+		Where("id IN ?", seatIDs).
+		Updates(map[string]any{"status": SeatHeld, "booking_id": b.ID}).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("hold seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return r.checkWaitlist(tx, b)
+}
+
+// insert stores a new pending booking together with the first row of its
+// status history.
+func (r *GormRepository) insert(tx *gorm.DB, b *Booking) error {
+	if err := tx.Create(b).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", b.EventID).
+			Uint("user_id", b.UserID).
+			Msg("insert booking failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if err := RecordCreated(tx, b.ID, "booked", UserActor(b.UserID)); err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("insert booking status history failed")
+		return err
+	}
+	return nil
+}
+
+func (r *GormRepository) hasSeatMap(tx *gorm.DB, eventID uint) (bool, error) {
+	var seats int64
+	if err := tx.Table("seats").Where("event_id = ?", eventID).Count(&seats).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("count seats failed")
+		return false, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return seats > 0, nil
+}
+
+// ExpirePending moves up to limit pending bookings whose hold has passed to
+// the expired status and gives their seats back to the events, all in one
+// transaction. Rows locked by another replica or by a payment worker are
+// skipped and picked up by a later run.
+func (r *GormRepository) ExpirePending(ctx context.Context, now time.Time, limit int) ([]ReleasedBooking, error) {
+	var expired []ReleasedBooking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("bookings").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id, event_id, ticket_type_id, seats").
+			Where("status = ? AND expired_at <= ? AND deleted_at IS NULL", StatusPending, now).
+			Order("expired_at").
+			Limit(limit).
+			Find(&expired).Error; err != nil {
+			r.logger.Error().Err(err).Msg("lock/select expired bookings failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if len(expired) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(expired))
+		for _, b := range expired {
+			ids = append(ids, b.ID)
+		}
+
+		if err := TransitionMany(tx, ids, StatusPending, StatusExpired, "payment hold expired", SystemActor); err != nil {
+			r.logger.Error().Err(err).
+				Int("bookings", len(ids)).
+				Msg("mark bookings expired failed")
+			return err
+		}
+
+		return ReleaseSeats(tx, expired)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+// FailPending moves a pending booking to the failed status and gives its
+// seats back, for bookings whose payment could not even be started.
+func (r *GormRepository) FailPending(ctx context.Context, bookingID uint, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var b Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", bookingID).
+			Take(&b).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBookingNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("booking_id", bookingID).
+				Msg("lock/select booking failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if err := Transition(tx, b.ID, b.Status, StatusFailed, reason, SystemActor); err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", b.ID).
+				Msg("move booking to failed status failed")
+			return err
+		}
+
+		released := ReleasedBooking{ID: b.ID, EventID: b.EventID, TicketTypeID: b.TicketTypeID, Seats: b.Seats}
+		if err := ReleaseSeats(tx, []ReleasedBooking{released}); err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Uint("seats", b.Seats).
+				Msg("release seats failed")
+			return err
+		}
+		return nil
+	})
+}
+
+// Cancel moves a pending or confirmed booking owned by userID to the
+// cancelled status and gives its seats back to the event. When the booking
+// has a successful payment a refund is requested for it in the same
+// transaction.
+func (r *GormRepository) Cancel(ctx context.Context, publicID string, userID uint, now time.Time) (*Booking, error) {
+	var b Booking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&b).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBookingNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("booking_public_id", publicID).
+				Msg("lock/select booking failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if b.UserID != userID {
+			return ErrNotBookingOwner
+		}
+
+		if !CanTransition(b.Status, StatusCancelled) {
+			return ErrBookingNotCancellable
+		}
+
+		var ev eventStartRow
+		if err := tx.Table("events").
+			Select("start_date").
+			Where("id = ?", b.EventID).
+			Take(&ev).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if !now.Before(ev.StartDate) {
+			return ErrEventAlreadyStarted
+		}
+
+		paid := b.Status == StatusConfirmed
+
+		if err := Transition(tx, b.ID, b.Status, StatusCancelled, "cancelled by user", UserActor(userID)); err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", b.ID).
+				Msg("mark booking cancelled failed")
+			return err
+		}
+		b.Status = StatusCancelled
+
+		released := ReleasedBooking{ID: b.ID, EventID: b.EventID, TicketTypeID: b.TicketTypeID, Seats: b.Seats}
+		if err := ReleaseSeats(tx, []ReleasedBooking{released}); err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Uint("seats", b.Seats).
+				Msg("release seats failed")
+			return err
+		}
+
+		if !paid {
+			return nil
+		}
+
+		return r.requestRefund(ctx, tx, &b)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (r *GormRepository) requestRefund(ctx context.Context, tx *gorm.DB, b *Booking) error {
+	// Part of the payment may already have been refunded; only what is left
+	// is requested.
+	var p paymentRow
+	if err := tx.Table("payments").
+		Select("id, currency, amount - COALESCE((SELECT SUM(refunds.amount) FROM refunds "+
+			"WHERE refunds.payment_id = payments.id AND refunds.status IN ?), 0) AS amount",
+			[]string{"requested", "succeeded"}).
+		Where("booking_id = ? AND status = ?", b.ID, "success").
+		Take(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("select payment failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	if !p.Amount.IsPositive() {
+		return nil
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return err
+	}
+
+	refund := refundRow{
+		PublicID: publicID,
+		PaymentID: p.ID,
+		BookingID: b.ID,
+		UserID: b.UserID,
+		Amount: p.Amount,
+		Currency: p.Currency,
+		Status: "requested",
+	}
+	if err := tx.Table("refunds").Create(&refund).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Uint("payment_id", p.ID).
+			Msg("insert refund failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return nil
+}
+
+// ListByUser returns the bookings of filter.UserID, newest first.
+func (r *GormRepository) ListByUser(ctx context.Context, filter ListFilter) ([]BookingView, error) {
+	q := r.db.WithContext(ctx).
+		Table("bookings").
+		Select(bookingViewColumns).
+		Joins("JOIN events ON events.id = bookings.event_id").
+		Where("bookings.user_id = ? AND bookings.deleted_at IS NULL", filter.UserID)
+
+	if filter.Status != "" {
+		q = q.Where("bookings.status = ?", filter.Status)
+	}
+	if filter.Upcoming != nil {
+		if *filter.Upcoming {
+			q = q.Where("events.start_date > ?", filter.Now)
+		} else {
+			q = q.Where("events.start_date <= ?", filter.Now)
+		}
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("bookings.id < ?", filter.AfterID)
+	}
+
+	var views []BookingView
+	if err := q.Order("bookings.id DESC").Limit(filter.Limit).Find(&views).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("user_id", filter.UserID).
+			Msg("list bookings failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return views, nil
+}
+
+func (r *GormRepository) FindView(ctx context.Context, publicID string) (*BookingView, error) {
+	var view BookingView
+	if err := r.db.WithContext(ctx).
+		Table("bookings").
+		Select(bookingViewColumns+", payments.status AS payment_status").
+		Joins("JOIN events ON events.id = bookings.event_id").
+		// A booking whose payment failed can be paid again; the latest
+		// payment is the one that counts.
+		Joins("LEFT JOIN payments ON payments.id = " +
+			"(SELECT MAX(p.id) FROM payments p WHERE p.booking_id = bookings.id)").
+		Where("bookings.public_id = ? AND bookings.deleted_at IS NULL", publicID).
+		Take(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("booking_public_id", publicID).
+			Msg("find booking failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return &view, nil
+}
+
+// History returns the status changes of a booking, oldest first.
+func (r *GormRepository) History(ctx context.Context, bookingID uint) ([]StatusChange, error) {
+	var changes []StatusChange
+	if err := r.db.WithContext(ctx).
+		Where("booking_id = ?", bookingID).
+		Order("id").
+		Find(&changes).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", bookingID).
+			Msg("select booking status history failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return changes, nil
+}
+
+// takeTicketTypeSeats locks the requested ticket type, checks it can serve
+// the booking and deducts the seats. Without a ticket type the booking is
+// only allowed for events that have no tiers.
+func (r *GormRepository) takeTicketTypeSeats(tx *gorm.DB, b *Booking, ticketTypePublicID string) error {
+	if ticketTypePublicID == "" {
+		var tiers int64
+		if err := tx.Table("ticket_types").Where("event_id = ?", b.EventID).Count(&tiers).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("count ticket types failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if tiers > 0 {
+			return ErrTicketTypeRequired
+		}
+
+		var currency string
+		if err := tx.Table("events").Select("currency").Where("id = ?", b.EventID).Row().Scan(&currency); err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("select event currency failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		b.Currency = money.Currency(currency)
+		b.TotalPrice = money.New(0, b.Currency)
+		return nil
+	}
+
+	var tt ticketTypeRow
+	if err := tx.Table("ticket_types").
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Select("id, price, currency, available, max_per_order").
+		Where("public_id = ? AND event_id = ?", ticketTypePublicID, b.EventID).
+		Take(&tt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketTypeNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("ticket_type_public_id", ticketTypePublicID).
+			Msg("lock/select ticket type failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if b.Seats > tt.MaxPerOrder {
+		return ErrExceedsMaxPerOrder
+	}
+	if tt.Available < uint64(b.Seats) {
+		return ErrNotEnoughSeats
+	}
+
+	if err := tx.Table("ticket_types").
+		Where("id = ?", tt.ID).
+		Update("available", gorm.Expr("available - ?", b.Seats)).
+		Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("ticket_type_id", tt.ID).
+			Uint("seats", b.Seats).
+			Msg("deduct ticket type seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	b.TicketTypeID = &tt.ID
+	b.Currency = tt.Currency
+	total, err := tt.Price.Mul(int64(b.Seats))
+	if err != nil {
+		return fmt.Errorf("booking total price: %w", err)
+	}
+	b.TotalPrice = total
+	return nil
+}
+
+// ReleaseSeats frees the mapped seats of the given bookings and adds their
+// seat counts back to their events and ticket types. Seats are freed first,
+// then events and ticket types each in id order, so concurrent releases and
+// bookings lock rows in the same order. The bookings must be locked by tx.
+func ReleaseSeats(tx *gorm.DB, bookings []ReleasedBooking) error {
+	bookingIDs := make([]uint, 0, len(bookings))
+	seatsByEvent := make(map[uint]uint)
+	seatsByTicketType := make(map[uint]uint)
+	for _, b := range bookings {
+		bookingIDs = append(bookingIDs, b.ID)
+		seatsByEvent[b.EventID] += b.Seats
+		if b.TicketTypeID != nil {
+			seatsByTicketType[*b.TicketTypeID] += b.Seats
+		}
+	}
+
+	if err := tx.Table("seats").
+		Where("booking_id IN ?", bookingIDs).
+		Updates(map[string]any{"status": SeatAvailable, "booking_id": nil}).Error; err != nil {
+		return fmt.Errorf("%w: free seats: %v", ErrDB, err)
+	}
+
+	for _, id := range sortedKeys(seatsByEvent) {
+		if err := tx.Table("events").
+			Where("id = ?", id).
+			Update("available_seats", gorm.Expr("available_seats + ?", seatsByEvent[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: release seats of event %d: %v", ErrDB, id, err)
+		}
+	}
+
+	for _, id := range sortedKeys(seatsByTicketType) {
+		if err := tx.Table("ticket_types").
+			Where("id = ?", id).
+			Update("available", gorm.Expr("available + ?", seatsByTicketType[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: release seats of ticket type %d: %v", ErrDB, id, err)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[uint]uint) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// isLockNotAvailable reports whether err is MySQL refusing a NOWAIT lock
+// because another transaction holds the row.
+func isLockNotAvailable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 3572
 }
\ No newline at end of file
@@ -8,6 +8,7 @@ import (
 
 	bookingDTO "github.com/anrisys/quicket/internal/booking/dto"
 	commonDTO "github.com/anrisys/quicket/internal/dto"
+	"github.com/anrisys/quicket/pkg/config"
 	"github.com/anrisys/quicket/pkg/errs"
 	"github.com/anrisys/quicket/pkg/types"
 	"github.com/anrisys/quicket/pkg/util"
@@ -17,13 +18,21 @@ import (
 
 type ServiceInterface interface {
 	Create(ctx context.Context, req *bookingDTO.CreateBookingRequest, userID, eventID string) (*bookingDTO.BookingDTO, error)
+	Cancel(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.CancelBookingDTO, error)
+	ListMine(ctx context.Context, userPublicID string, query *bookingDTO.ListBookingsQuery) (*bookingDTO.BookingListDTO, error)
+	GetMine(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.BookingDetailDTO, error)
+	RetryPayment(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.BookingSummaryDTO, error)
 }
 
+const defaultListLimit = 20
+
 type Service struct {
 	repo Repository
 	events types.EventReader
 	users types.UserReader
 	payments types.SimulatePayment
+	waitlist types.SeatReleaseListener
+	holdDuration time.Duration
 	logger zerolog.Logger
 }
 
@@ -31,12 +40,16 @@ func NewService(repo Repository,
 	events types.EventReader, 
 	logger zerolog.Logger,
 	payments types.SimulatePayment,
-	users types.UserReader) *Service {
+	users types.UserReader,
+	waitlist types.SeatReleaseListener,
+	cfg *config.AppConfig) *Service {
 	return &Service{
 		repo: repo,
 		events: events,
 		users: users,
 		payments: payments,
+		waitlist: waitlist,
+		holdDuration: cfg.Booking.HoldDuration,
 		logger: logger,
 	}
 }
@@ -68,16 +81,40 @@ func (s *Service) Create(ctx context.Context, req *bookingDTO.CreateBookingReque
 		return nil, fmt.Errorf("booking service#create: %w", err)
 	}
 
-	newB, err := s.prepareBooking(ctx, uint(ev.ID), *userID, req.Seats)
+	seats, err := s.requestedSeats(req)
 	if err != nil {
 		return nil, err
 	}
 
-	persisted, err := s.repo.Create(ctx, newB)
+	newB, err := s.prepareBooking(ctx, uint(ev.ID), *userID, seats)
+	if err != nil {
+		return nil, err
+	}
+
+	persisted, err := s.repo.Create(ctx, newB, Selection{
+		TicketTypePublicID: req.TicketTypeID,
+		SeatPublicIDs: req.SeatIDs,
+	})
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrNotEnoughSeats):
 			return nil, errs.NewConflictError("not enough available seats")
+		case errors.Is(err, ErrWaitlistAhead):
+			return nil, errs.NewConflictError("these seats are held for people on the waitlist, join it to get seats as they free up")
+		case errors.Is(err, ErrSeatUnavailable):
+			return nil, errs.NewConflictError("one or more of the selected seats are no longer available")
+		case errors.Is(err, ErrSeatNotFound):
+			return nil, errs.NewErrNotFound("seat")
+		case errors.Is(err, ErrSeatSelectionRequired):
+			return nil, errs.NewValidationError("seat_ids are required for this event")
+		case errors.Is(err, ErrNoSeatMap):
+			return nil, errs.NewValidationError("this event has no seat map, book by seat count instead")
+		case errors.Is(err, ErrTicketTypeRequired):
+			return nil, errs.NewValidationError("ticket_type_id is required for this event")
+		case errors.Is(err, ErrTicketTypeNotFound):
+			return nil, errs.NewErrNotFound("ticket type")
+		case errors.Is(err, ErrExceedsMaxPerOrder):
+			return nil, errs.NewValidationError("seats exceed the per-order limit of this ticket type")
 		case errors.Is(err, ErrEventNotFound):
 			return nil, errs.NewErrNotFound("event")
 		default:
@@ -89,10 +126,25 @@ func (s *Service) Create(ctx context.Context, req *bookingDTO.CreateBookingReque
 	bookData := commonDTO.SimulateBookingPayment{
 		Amount: persisted.TotalPrice,
 		BookingID: persisted.ID,
+		BookingPublicID: persisted.PublicID,
 		UserID: persisted.UserID,
 	}
 
-	s.payments.SimulatePayment(ctx, &bookData)
+	if _, err := s.payments.SimulatePayment(ctx, &bookData); err != nil {
+		// Without a payment job the booking would only hold its seats until
+		// it expires, so it is failed right away instead.
+		log.Error().Err(err).
+			Str("booking_public_id", persisted.PublicID).
+			Msg("failed to queue booking payment")
+		if failErr := s.repo.FailPending(ctx, persisted.ID, "payment could not be queued"); failErr != nil {
+			log.Error().Err(failErr).
+				Str("booking_public_id", persisted.PublicID).
+				Msg("failed to fail unpaid booking")
+		} else {
+			s.waitlist.SeatsReleased(ctx, persisted.EventID)
+		}
+		return nil, fmt.Errorf("booking#create: queue payment: %w", err)
+	}
 	log.Info().
 		Str("booking_public_id", persisted.PublicID).
 		Msg("payment simulation triggered asynchronously")
@@ -102,9 +154,204 @@ func (s *Service) Create(ctx context.Context, req *bookingDTO.CreateBookingReque
 		Msg("Booking created")
 
 	bDTO := s.prepareBookingDTO(ctx, persisted, eventID, userPublicID)
+	bDTO.TicketTypeID = req.TicketTypeID
+	bDTO.SeatIDs = req.SeatIDs
 	return bDTO, nil
 }
 
+func (s *Service) Cancel(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.CancelBookingDTO, error) {
+	log := s.logger.With().
+		Str("booking_public_id", bookingPublicID).
+		Str("user_public_id", userPublicID).
+		Logger()
+
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#cancel: %w", err)
+	}
+
+	b, err := s.repo.Cancel(ctx, bookingPublicID, *userID, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBookingNotFound):
+			return nil, errs.NewErrNotFound("booking")
+		case errors.Is(err, ErrNotBookingOwner):
+			return nil, errs.NewForbiddenError("booking belongs to another user")
+		case errors.Is(err, ErrBookingNotCancellable):
+			return nil, errs.NewConflictError("booking can not be cancelled anymore")
+		case errors.Is(err, ErrEventAlreadyStarted):
+			return nil, errs.NewConflictError("can not cancel booking after the event has started")
+		case errors.Is(err, ErrEventNotFound):
+			return nil, errs.NewErrNotFound("event")
+		default:
+			return nil, fmt.Errorf("booking#cancel: persist: %w", err)
+		}
+	}
+
+	log.Info().
+		Uint("event_id", b.EventID).
+		Uint("seats", b.Seats).
+		Msg("Booking cancelled")
+
+	s.waitlist.SeatsReleased(ctx, b.EventID)
+
+	return &bookingDTO.CancelBookingDTO{
+		PublicID: b.PublicID,
+		Seats: b.Seats,
+		Status: b.Status,
+	}, nil
+}
+
+func (s *Service) ListMine(ctx context.Context, userPublicID string, query *bookingDTO.ListBookingsQuery) (*bookingDTO.BookingListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#list: %w", err)
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	filter := ListFilter{
+		UserID: *userID,
+		Status: query.Status,
+		Now: time.Now(),
+		AfterID: afterID,
+		// One extra row tells whether there is a next page.
+		Limit: limit + 1,
+	}
+	if query.When != "" {
+		upcoming := query.When == "upcoming"
+		filter.Upcoming = &upcoming
+	}
+
+	views, err := s.repo.ListByUser(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("booking#list: %w", err)
+	}
+
+	list := &bookingDTO.BookingListDTO{
+		Bookings: make([]bookingDTO.BookingSummaryDTO, 0, limit),
+	}
+	if len(views) > limit {
+		views = views[:limit]
+		list.NextCursor = util.EncodeCursor(views[limit-1].ID)
+	}
+	for i := range views {
+		list.Bookings = append(list.Bookings, toBookingSummaryDTO(&views[i]))
+	}
+
+	return list, nil
+}
+
+func (s *Service) GetMine(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.BookingDetailDTO, error) {
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#get: %w", err)
+	}
+
+	view, err := s.repo.FindView(ctx, bookingPublicID)
+	if err != nil {
+		if errors.Is(err, ErrBookingNotFound) {
+			return nil, errs.NewErrNotFound("booking")
+		}
+		return nil, fmt.Errorf("booking#get: %w", err)
+	}
+
+	if view.UserID != *userID {
+		return nil, errs.NewForbiddenError("booking belongs to another user")
+	}
+
+	changes, err := s.repo.History(ctx, view.ID)
+	if err != nil {
+		return nil, fmt.Errorf("booking#get: %w", err)
+	}
+
+	history := make([]bookingDTO.StatusChangeDTO, 0, len(changes))
+	for _, c := range changes {
+		history = append(history, bookingDTO.StatusChangeDTO{
+			From: c.FromStatus,
+			To: c.ToStatus,
+			Reason: c.Reason,
+			Actor: c.ActorType,
+			At: c.CreatedAt,
+		})
+	}
+
+	return &bookingDTO.BookingDetailDTO{
+		BookingSummaryDTO: toBookingSummaryDTO(view),
+		PaymentStatus: view.PaymentStatus,
+		History: history,
+	}, nil
+}
+
+// RetryPayment queues a new payment for a pending booking whose last
+// payment failed. It is only useful under the retry failure policy, since
+// the fail policy closes the booking together with the failed payment, and
+// only while the booking still holds its seats.
+func (s *Service) RetryPayment(ctx context.Context, userPublicID, bookingPublicID string) (*bookingDTO.BookingSummaryDTO, error) {
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("booking service#retryPayment: %w", err)
+	}
+
+	view, err := s.repo.FindView(ctx, bookingPublicID)
+	if err != nil {
+		if errors.Is(err, ErrBookingNotFound) {
+			return nil, errs.NewErrNotFound("booking")
+		}
+		return nil, fmt.Errorf("booking#retryPayment: %w", err)
+	}
+
+	if view.UserID != *userID {
+		return nil, errs.NewForbiddenError("booking belongs to another user")
+	}
+	if view.Status != StatusPending || !time.Now().Before(view.ExpiredAt) {
+		return nil, errs.NewConflictError("booking is no longer awaiting payment")
+	}
+
+	bookData := commonDTO.SimulateBookingPayment{
+		Amount: view.TotalPrice,
+		BookingID: view.ID,
+		BookingPublicID: view.PublicID,
+		UserID: view.UserID,
+	}
+	if _, err := s.payments.SimulatePayment(ctx, &bookData); err != nil {
+		return nil, fmt.Errorf("booking#retryPayment: %w", err)
+	}
+
+	s.logger.Info().
+		Str("booking_public_id", view.PublicID).
+		Msg("payment retry queued")
+
+	summary := toBookingSummaryDTO(view)
+	return &summary, nil
+}
+
+func toBookingSummaryDTO(v *BookingView) bookingDTO.BookingSummaryDTO {
+	return bookingDTO.BookingSummaryDTO{
+		PublicID: v.PublicID,
+		Seats: v.Seats,
+		TotalPrice: v.TotalPrice,
+		RefundedAmount: v.RefundedAmount,
+		Status: v.Status,
+		ExpiredAt: v.ExpiredAt,
+		CreatedAt: v.CreatedAt,
+		Event: bookingDTO.BookingEventDTO{
+			PublicID: v.EventPublicID,
+			Title: v.EventTitle,
+			StartDate: v.EventStartDate,
+			EndDate: v.EventEndDate,
+		},
+	}
+}
+
 func (s *Service) GetSimpleBookingDTO(ctx context.Context, publicID string) (*commonDTO.SimpleBookingDTO, error) {
 	dto, err := s.repo.FindSimpleDTO(ctx, publicID)
 	if err != nil {
@@ -116,19 +363,42 @@ func (s *Service) GetSimpleBookingDTO(ctx context.Context, publicID string) (*co
 	return dto, nil
 }
 
+// requestedSeats returns the number of seats the request books. When seats
+// are picked by ID the count follows from them, and a count sent alongside
+// has to agree.
+func (s *Service) requestedSeats(req *bookingDTO.CreateBookingRequest) (uint, error) {
+	if len(req.SeatIDs) == 0 {
+		return req.Seats, nil
+	}
+
+	picked := make(map[string]struct{}, len(req.SeatIDs))
+	for _, id := range req.SeatIDs {
+		if _, dup := picked[id]; dup {
+			return 0, errs.NewValidationError("seat_ids must not contain duplicates")
+		}
+		picked[id] = struct{}{}
+	}
+
+	if req.Seats != 0 && req.Seats != uint(len(req.SeatIDs)) {
+		return 0, errs.NewValidationError("seats must match the number of seat_ids")
+	}
+	return uint(len(req.SeatIDs)), nil
+}
+
 func (s *Service) prepareBooking(ctx context.Context, eventID uint, userID uint, seats uint) (*Booking, error) {
 	publicID, err := util.GeneratePublicID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("booking#create: generate public id: %w", err)
 	}
 
-	expiredAt := time.Now().Add(5 * time.Minute)
+	expiredAt := time.Now().Add(s.holdDuration)
 
 	return &Booking{
 		PublicID: publicID,
 		EventID: eventID,
 		UserID: uint(userID),
 		Seats: seats,
+		Status: StatusPending,
 		ExpiredAt: expiredAt,
 	}, nil
 }
@@ -139,6 +409,7 @@ func (s *Service) prepareBookingDTO(_ctx context.Context, booking *Booking, even
 		EventID: eventPublicID,
 		UserID: userPublicID,
 		Seats: booking.Seats,
+		TotalPrice: booking.TotalPrice,
 		Status: booking.Status,
 		ExpiredAt: booking.ExpiredAt,
 	}
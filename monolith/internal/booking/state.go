@@ -0,0 +1,95 @@
+package booking
+
+import (
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// transitions lists the statuses a booking may move to from each status.
+// Failed and expired bookings are final; a cancelled booking only moves on
+// once the refund of its payment has been paid out.
+var transitions = map[string][]string{
+	StatusPending: {StatusConfirmed, StatusFailed, StatusExpired, StatusCancelled},
+	StatusConfirmed: {StatusCancelled, StatusRefunded},
+	StatusCancelled: {StatusRefunded},
+}
+
+// Actor is who caused a status change.
+type Actor struct {
+	Type string
+	ID *uint
+}
+
+// UserActor is a change requested by the user with the given id.
+func UserActor(id uint) Actor {
+	return Actor{Type: ActorUser, ID: &id}
+}
+
+// SystemActor is a change made by a background job or the payment flow.
+var SystemActor = Actor{Type: ActorSystem}
+
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Transition moves a booking from status from to status to and records the
+// change. The update is guarded on the current status, so a booking that
+// was changed by someone else since it was read is left alone.
+func Transition(tx *gorm.DB, bookingID uint, from, to, reason string, actor Actor) error {
+	return TransitionMany(tx, []uint{bookingID}, from, to, reason, actor)
+}
+
+// TransitionMany moves every booking in ids from status from to status to.
+// Either all of them move or none do.
+func TransitionMany(tx *gorm.DB, ids []uint, from, to, reason string, actor Actor) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	res := tx.Table("bookings").
+		Where("id IN ? AND status = ?", ids, from).
+		Update("status", to)
+	if res.Error != nil {
+		return fmt.Errorf("%w: %v", ErrDB, res.Error)
+	}
+	if res.RowsAffected != int64(len(ids)) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	changes := make([]StatusChange, 0, len(ids))
+	for _, id := range ids {
+		changes = append(changes, StatusChange{
+			BookingID: id,
+			FromStatus: &from,
+			ToStatus: to,
+			Reason: reason,
+			ActorType: actor.Type,
+			ActorID: actor.ID,
+		})
+	}
+	return recordChanges(tx, changes)
+}
+
+// RecordCreated writes the first history row of a booking that has just
+// been inserted as pending.
+func RecordCreated(tx *gorm.DB, bookingID uint, reason string, actor Actor) error {
+	return recordChanges(tx, []StatusChange{{
+		BookingID: bookingID,
+		ToStatus: StatusPending,
+		Reason: reason,
+		ActorType: actor.Type,
+		ActorID: actor.ID,
+	}})
+}
+
+func recordChanges(tx *gorm.DB, changes []StatusChange) error {
+	if err := tx.Create(&changes).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package booking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{StatusPending, StatusConfirmed, true},
+		{StatusPending, StatusFailed, true},
+		{StatusPending, StatusExpired, true},
+		{StatusPending, StatusCancelled, true},
+		{StatusPending, StatusRefunded, false},
+		{StatusConfirmed, StatusCancelled, true},
+		{StatusConfirmed, StatusRefunded, true},
+		{StatusConfirmed, StatusPending, false},
+		{StatusCancelled, StatusRefunded, true},
+		{StatusCancelled, StatusConfirmed, false},
+		// A late payment must not revive an expired or failed booking.
+		{StatusExpired, StatusConfirmed, false},
+		{StatusFailed, StatusConfirmed, false},
+		{StatusRefunded, StatusCancelled, false},
+		{StatusPending, StatusPending, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanTransition(tt.from, tt.to))
+		})
+	}
+}
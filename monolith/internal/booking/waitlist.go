@@ -0,0 +1,130 @@
+package booking
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// WaitingRequest is what a waiting waitlist entry asks for. The waitlist
+// package imports this one, so entries are read here as plain rows.
+type WaitingRequest struct {
+	ID uint
+	TicketTypeID *uint
+	Seats uint
+}
+
+// RequestsThatFit returns the waiting requests, oldest first, that the
+// available seats of an event and of its ticket types can serve. A request
+// that does not fit is passed over, so one for a sold out ticket type or for
+// more seats than are free does not hold up the requests behind it.
+// tierAvailable is used up in the process.
+func RequestsThatFit(waiting []WaitingRequest, available uint64, tierAvailable map[uint]uint64) []WaitingRequest {
+	var fits []WaitingRequest
+	for _, w := range waiting {
+		if uint64(w.Seats) > available {
+			continue
+		}
+		if w.TicketTypeID != nil && tierAvailable[*w.TicketTypeID] < uint64(w.Seats) {
+			continue
+		}
+
+		available -= uint64(w.Seats)
+		if w.TicketTypeID != nil {
+			tierAvailable[*w.TicketTypeID] -= uint64(w.Seats)
+		}
+		fits = append(fits, w)
+	}
+	return fits
+}
+
+// checkWaitlist runs after the seats of b were taken and refuses the booking
+// when it took seats a waiting entry could have been offered, that is when
+// fewer or other entries fit afterwards than before. Bookings for seats no
+// waiting entry can use go through. The event row is locked by tx, which
+// orders this with joining the waitlist and with offering seats to it.
+func (r *GormRepository) checkWaitlist(tx *gorm.DB, b *Booking) error {
+	var waiting []WaitingRequest
+	if err := tx.Table("waitlist_entries").
+		Select("id, ticket_type_id, seats").
+		Where("event_id = ? AND status = ?", b.EventID, "waiting").
+		Order("id").
+		Find(&waiting).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", b.EventID).
+			Msg("select waiting entries failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	var available uint64
+	if err := tx.Table("events").Select("available_seats").Where("id = ?", b.EventID).Row().Scan(&available); err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", b.EventID).
+			Msg("select event seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	var tierIDs []uint
+	for _, w := range waiting {
+		if w.TicketTypeID != nil {
+			tierIDs = append(tierIDs, *w.TicketTypeID)
+		}
+	}
+	after := make(map[uint]uint64)
+	if len(tierIDs) > 0 {
+		var tiers []ticketTypeRow
+		if err := tx.Table("ticket_types").
+			Select("id, available").
+			Where("id IN ?", tierIDs).
+			Find(&tiers).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", b.EventID).
+				Msg("select ticket type seats failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		for _, tt := range tiers {
+			after[tt.ID] = tt.Available
+		}
+	}
+
+	if takesWaitlistSeats(waiting, available, after, b) {
+		return ErrWaitlistAhead
+	}
+	return nil
+}
+
+// takesWaitlistSeats reports whether b took seats from the waiting requests:
+// available and tierAvailable are what is left after b, and fewer or other
+// requests fit in them than did before it.
+func takesWaitlistSeats(waiting []WaitingRequest, available uint64, tierAvailable map[uint]uint64, b *Booking) bool {
+	before := make(map[uint]uint64, len(tierAvailable))
+	after := make(map[uint]uint64, len(tierAvailable))
+	for id, n := range tierAvailable {
+		before[id] = n
+		after[id] = n
+	}
+	if b.TicketTypeID != nil {
+		if _, ok := before[*b.TicketTypeID]; ok {
+			before[*b.TicketTypeID] += uint64(b.Seats)
+		}
+	}
+
+	wanted := RequestsThatFit(waiting, available+uint64(b.Seats), before)
+	left := RequestsThatFit(waiting, available, after)
+	return !sameRequests(wanted, left)
+}
+
+func sameRequests(a, b []WaitingRequest) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
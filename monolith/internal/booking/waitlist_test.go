@@ -0,0 +1,109 @@
+package booking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestsThatFit(t *testing.T) {
+	vip := uint(7)
+
+	tests := []struct {
+		name      string
+		waiting   []WaitingRequest
+		available uint64
+		tiers     map[uint]uint64
+		want      []uint
+	}{
+		{
+			name:      "serves the oldest requests first",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 2}, {ID: 2, Seats: 2}, {ID: 3, Seats: 1}},
+			available: 3,
+			want:      []uint{1, 3},
+		},
+		{
+			name:      "passes over a head request for an unavailable ticket type",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 1, TicketTypeID: &vip}, {ID: 2, Seats: 2}},
+			available: 4,
+			tiers:     map[uint]uint64{vip: 0},
+			want:      []uint{2},
+		},
+		{
+			name:      "nothing fits",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 5}},
+			available: 4,
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiers := tt.tiers
+			if tiers == nil {
+				tiers = map[uint]uint64{}
+			}
+
+			var got []uint
+			for _, w := range RequestsThatFit(tt.waiting, tt.available, tiers) {
+				got = append(got, w.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTakesWaitlistSeats(t *testing.T) {
+	vip := uint(7)
+	regular := uint(8)
+
+	tests := []struct {
+		name      string
+		waiting   []WaitingRequest
+		available uint64
+		tiers     map[uint]uint64
+		booking   Booking
+		want      bool
+	}{
+		{
+			name:      "seats a waiting request could take",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 2}},
+			available: 1,
+			booking:   Booking{Seats: 1},
+			want:      true,
+		},
+		{
+			name:      "seats left over after the waiting requests",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 2}},
+			available: 2,
+			booking:   Booking{Seats: 1},
+			want:      false,
+		},
+		{
+			name:      "head request needs an unavailable ticket type",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 2, TicketTypeID: &vip}},
+			available: 9,
+			tiers:     map[uint]uint64{vip: 0},
+			booking:   Booking{Seats: 1, TicketTypeID: &regular},
+			want:      false,
+		},
+		{
+			name:      "last seats of the ticket type a request waits for",
+			waiting:   []WaitingRequest{{ID: 1, Seats: 1, TicketTypeID: &vip}},
+			available: 9,
+			tiers:     map[uint]uint64{vip: 0},
+			booking:   Booking{Seats: 1, TicketTypeID: &vip},
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiers := tt.tiers
+			if tiers == nil {
+				tiers = map[uint]uint64{}
+			}
+			assert.Equal(t, tt.want, takesWaitlistSeats(tt.waiting, tt.available, tiers, &tt.booking))
+		})
+	}
+}
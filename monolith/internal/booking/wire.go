@@ -6,6 +6,7 @@ var ProviderSet = wire.NewSet(
 	NewGormRepository,
 	NewService,
 	NewHandler,
+	NewExpirer,
 	wire.Bind(new(Repository), new(*GormRepository)),
 	wire.Bind(new(ServiceInterface), new(*Service)),
 )
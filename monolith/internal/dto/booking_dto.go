@@ -1,19 +1,23 @@
 package dto
 
+import "github.com/anrisys/quicket/pkg/money"
+
 type SimpleBookingDTO struct {
-	ID     uint
-	UserID uint
-	Amount float32
+	ID       uint
+	UserID   uint
+	Amount   money.Money
+	Currency money.Currency
 }
 
 type CreatePaymentRequest struct {
-	Amount    float32 `json:"amount" binding:"required"`
-	BookingID string  `json:"booking_id" binding:"required"`
-	Status    string  `json:"status" binding:"required,payStatus"`
+	Amount    money.Money `json:"amount"`
+	BookingID string      `json:"booking_id" binding:"required"`
+	Status    string      `json:"status" binding:"required,payStatus"`
 }
 
 type SimulateBookingPayment struct {
-	BookingID uint
-	UserID    uint
-	Amount    float32
-}
\ No newline at end of file
+	BookingID       uint
+	BookingPublicID string
+	UserID          uint
+	Amount          money.Money
+}
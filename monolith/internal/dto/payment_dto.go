@@ -1,8 +1,10 @@
 package dto
 
+import "github.com/anrisys/quicket/pkg/money"
+
 type PaymentDTO struct {
-	PublicID  string
-	Amount    float32
-	Status    string
-	BookingID string
+	PublicID  string      `json:"id"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status" example:"success"`
+	BookingID string      `json:"booking_id"`
 }
\ No newline at end of file
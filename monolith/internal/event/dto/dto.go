@@ -1,18 +1,61 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// EventVenueDTO is the venue an event is held at. The event's times are
+// given in its time zone.
+type EventVenueDTO struct {
+	PublicID 		string 		`json:"public_id"`
+	Name 			string 		`json:"name"`
+	TimeZone 		string 		`json:"time_zone" example:"Asia/Jakarta"`
+}
 
 type EventDTO struct {
-	ID             	uint
-	PublicID       	string   
-	Title          	string   
-	Description    	*string  
-	StartDate      	time.Time
-	EndDate        	time.Time
-	MaxSeats       	uint64   
-	AvailableSeats 	uint64
-	CreatedAt		time.Time
-	UpdatedAt		time.Time
+	ID             	uint		`json:"-"`
+	PublicID       	string   	`json:"public_id" example:"evt_123"`
+	Title          	string   	`json:"title" example:"Concert Night"`
+	Description    	*string  	`json:"description,omitempty"`
+	StartDate      	time.Time	`json:"start_date" example:"2023-12-31T20:00:00Z"`
+	EndDate        	time.Time	`json:"end_date" example:"2023-12-31T23:59:59Z"`
+	MaxSeats       	uint64   	`json:"max_seats" example:"500"`
+	AvailableSeats 	uint64		`json:"available_seats" example:"120"`
+	Currency        money.Currency `json:"currency" example:"IDR"`
+	Venue 			*EventVenueDTO `json:"venue,omitempty"`
+	CreatedAt		time.Time	`json:"created_at"`
+	UpdatedAt		time.Time	`json:"updated_at"`
+	// DeletedAt is set on an event that was soft-deleted because it has
+	// bookings.
+	DeletedAt		*time.Time	`json:"deleted_at,omitempty"`
+}
+
+type EventListDTO struct {
+	Events     []EventDTO `json:"events"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type EventSeriesDTO struct {
+	PublicID       	string   	`json:"public_id"`
+	Title          	string   	`json:"title"`
+	Description    	*string  	`json:"description,omitempty"`
+	RRule          	string   	`json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO;COUNT=10"`
+	StartDate      	time.Time	`json:"start_date"`
+	EndDate        	time.Time	`json:"end_date"`
+	MaxSeats       	uint64   	`json:"max_seats"`
+	Currency        money.Currency `json:"currency" example:"IDR"`
+	Venue 			*EventVenueDTO `json:"venue,omitempty"`
+	// MaterializedUntil is how far ahead the occurrences exist.
+	MaterializedUntil time.Time	`json:"materialized_until"`
+	CreatedAt		time.Time	`json:"created_at"`
+	UpdatedAt		time.Time	`json:"updated_at"`
+}
+
+type EventSeriesListDTO struct {
+	Series     []EventSeriesDTO `json:"series"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 type SimpleEventDTO struct {
@@ -20,4 +63,15 @@ type SimpleEventDTO struct {
 	Title          	string		`json:"title" example:"Concert Night"`
 	StartDate      	time.Time	`json:"start_date" example:"2023-12-31T20:00:00Z"`
 	EndDate        	time.Time 	`json:"end_date" example:"2023-12-31T23:59:59Z"`
+	Currency        money.Currency `json:"currency" example:"IDR"`
+	Venue 			*EventVenueDTO `json:"venue,omitempty"`
+}
+
+type TicketTypeDTO struct {
+	PublicID    string  `json:"id"`
+	Name        string  `json:"name"`
+	Price       money.Money `json:"price"`
+	Quota       uint64  `json:"quota"`
+	Available   uint64  `json:"available"`
+	MaxPerOrder uint    `json:"max_per_order"`
 }
\ No newline at end of file
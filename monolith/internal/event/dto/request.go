@@ -1,11 +1,94 @@
 package dto
 
-import "time"
+import (
+	"time"
 
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// CreateEventRequest creates an event. StartDate has to fall after today in
+// the time zone of the venue, or UTC without one. MaxSeats defaults to the
+// capacity of the venue.
 type CreateEventRequest struct {
 	Title     string 	`json:"title" binding:"required,min=3,max=256"`
-	StartDate time.Time `json:"start_date" binding:"required,gttoday"`
+	StartDate time.Time `json:"start_date" binding:"required"`
 	EndDate time.Time `json:"end_date" binding:"required,gtefield=StartDate"`
 	Description string `json:"description" binding:"max=2000,omitempty"`
-	MaxSeats uint64 `json:"max_seats" binding:"required,gt=0"`
+	// VenueID is the public ID of the venue the event is held at.
+	VenueID string `json:"venue_id" binding:"omitempty,max=36"`
+	MaxSeats uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	// Currency of the event's prices; defaults to IDR.
+	Currency string `json:"currency" binding:"omitempty,iso4217"`
+	TicketTypes []CreateTicketTypeRequest `json:"ticket_types" binding:"omitempty,dive"`
+}
+
+// UpdateEventRequest holds the fields of an event to change; omitted fields
+// are left as they are.
+type UpdateEventRequest struct {
+	Title     *string 	`json:"title" binding:"omitempty,min=3,max=256"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate *time.Time `json:"end_date"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	MaxSeats *uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	VenueID *string `json:"venue_id" binding:"omitempty,max=36"`
+}
+
+// UpdateEventQuery tells which events of a series an update applies to:
+// this one only, the default, or this one and the ones that follow.
+type UpdateEventQuery struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=this following"`
+}
+
+// CreateEventSeriesRequest creates a recurring event. RRule is a recurrence
+// rule such as FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10, and StartDate and EndDate
+// are those of its first occurrence. The rule is expanded in the time zone
+// of the venue, so occurrences keep their local time across DST changes.
+type CreateEventSeriesRequest struct {
+	Title     string 	`json:"title" binding:"required,min=3,max=256"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate time.Time `json:"end_date" binding:"required,gtefield=StartDate"`
+	Description string `json:"description" binding:"max=2000,omitempty"`
+	VenueID string `json:"venue_id" binding:"omitempty,max=36"`
+	MaxSeats uint64 `json:"max_seats" binding:"omitempty,gt=0"`
+	// Currency of the occurrences' prices; defaults to IDR.
+	Currency string `json:"currency" binding:"omitempty,iso4217"`
+	RRule string `json:"rrule" binding:"required,max=256"`
+}
+
+// ListEventSeriesQuery pages the series listing, optionally of one
+// organizer.
+type ListEventSeriesQuery struct {
+	Organizer string `form:"organizer"`
+	Cursor    string `form:"cursor"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ListEventsQuery filters and pages the event listing. Q is matched
+// against the title and description; From and To bound the start and end
+// dates; Organizer is the public ID of the organizing user; Series keeps
+// the occurrences of the series with that public ID.
+type ListEventsQuery struct {
+	Q         string    `form:"q" binding:"omitempty,max=100"`
+	From      time.Time `form:"from"`
+	To        time.Time `form:"to"`
+	Available bool      `form:"available"`
+	Organizer string    `form:"organizer"`
+	Series    string    `form:"series"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=start_date -start_date created_at -created_at"`
+	Cursor    string    `form:"cursor"`
+	Limit     int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type CreateTicketTypeRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Price       money.Money `json:"price"`
+	Quota       uint64  `json:"quota" binding:"required,gt=0"`
+	MaxPerOrder uint    `json:"max_per_order" binding:"required,gt=0,ltefield=Quota"`
+}
+
+type UpdateTicketTypeRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Price       *money.Money `json:"price"`
+	Quota       *uint64  `json:"quota" binding:"omitempty,gt=0"`
+	MaxPerOrder *uint    `json:"max_per_order" binding:"omitempty,gt=0"`
 }
\ No newline at end of file
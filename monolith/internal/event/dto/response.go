@@ -8,4 +8,30 @@ type ResponseSuccess struct {
 type CreateEventSuccessResponse struct {
 	ResponseSuccess `json:",inline"`
 	Event           SimpleEventDTO `json:"event"`
+	TicketTypes     []TicketTypeDTO `json:"ticket_types,omitempty"`
+}
+
+type TicketTypeSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	TicketType      TicketTypeDTO `json:"ticket_type"`
+}
+
+type EventSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Event           EventDTO `json:"event"`
+}
+
+type ListEventsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	EventListDTO    `json:",inline"`
+}
+
+type EventSeriesSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Series          EventSeriesDTO `json:"series"`
+}
+
+type ListEventSeriesSuccessResponse struct {
+	ResponseSuccess    `json:",inline"`
+	EventSeriesListDTO `json:",inline"`
 }
\ No newline at end of file
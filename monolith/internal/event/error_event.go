@@ -0,0 +1,18 @@
+package event
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrQuotaExceedsSeats = errors.New("ticket type quotas exceed the event max seats")
+	ErrQuotaBelowSold = errors.New("ticket type quota is below the seats already sold")
+	ErrMaxPerOrderAboveQuota = errors.New("ticket type max per order is greater than its quota")
+	ErrMaxSeatsBelowSold = errors.New("event max seats is below the seats already sold")
+	ErrSeatMapDefined = errors.New("event max seats is fixed by its seat map")
+	ErrInvalidDates = errors.New("event end date is before its start date")
+	ErrSeriesNotFound = errors.New("event series not found")
+	ErrSeriesChanged = errors.New("event series changed since it was read")
+	ErrVenueNotFound = errors.New("venue not found")
+	ErrDB = errors.New("database error")
+)
\ No newline at end of file
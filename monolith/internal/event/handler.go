@@ -11,19 +11,21 @@ import (
 
 type EventHandler struct {
 	EventService EventServiceInterface
+	SeriesService SeriesServiceInterface
 	logger  zerolog.Logger
 }
 
-func NewEventHandler(eventService EventServiceInterface, logger zerolog.Logger) *EventHandler {
+func NewEventHandler(eventService EventServiceInterface, seriesService SeriesServiceInterface, logger zerolog.Logger) *EventHandler {
 	return &EventHandler{
 		EventService: eventService,
+		SeriesService: seriesService,
 		logger: logger,
 	}
 }
 
 // Create godoc
 // @Summary Create new event
-// @Description Create a new event (Admin/Organizer only)
+// @Description Create a new event (Admin/Organizer only). Its times are stored in UTC and given back in the time zone of its venue; max_seats defaults to the venue capacity.
 // @Tags Events
 // @Security BearerAuth
 // @Accept json
@@ -59,10 +61,291 @@ func (h *EventHandler) Create(c *gin.Context) {
 		Event: dto.SimpleEventDTO{
 			PublicID:  event.PublicID,
 			Title:     event.Title,
-			StartDate: event.StartDate,
-			EndDate:   event.EndDate,
+			StartDate: event.StartDate.In(event.Location()),
+			EndDate:   event.EndDate.In(event.Location()),
+			Currency:  event.Currency,
+			Venue:     toEventVenueDTO(event.Venue),
 		},
 	}
+	for i := range event.TicketTypes {
+		response.TicketTypes = append(response.TicketTypes, toTicketTypeDTO(&event.TicketTypes[i]))
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CreateTicketType godoc
+// @Summary Create ticket type
+// @Description Add a ticket type to an event (event organizer or admin only)
+// @Tags Events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param request body dto.CreateTicketTypeRequest true "Ticket type data"
+// @Success 201 {object} dto.TicketTypeSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Failure 409 {object} errs.ErrorResponse "Quotas exceed max seats"
+// @Router /api/v1/events/{publicID}/ticket-types [post]
+func (h *EventHandler) CreateTicketType(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	var req dto.CreateTicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid ticket type data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	tt, err := h.EventService.AddTicketType(ctx, eventPublicID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.TicketTypeSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Ticket type created successfully",
+		},
+		TicketType: toTicketTypeDTO(tt),
+	}
 
 	c.JSON(http.StatusCreated, response)
 }
+
+// UpdateTicketType godoc
+// @Summary Update ticket type
+// @Description Change the name, price, quota or per-order limit of a ticket type (event organizer or admin only)
+// @Tags Events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param ticketTypeID path string true "Ticket type public ID"
+// @Param request body dto.UpdateTicketTypeRequest true "Fields to change"
+// @Success 200 {object} dto.TicketTypeSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event or ticket type not found"
+// @Failure 409 {object} errs.ErrorResponse "Quota conflict"
+// @Router /api/v1/events/{publicID}/ticket-types/{ticketTypeID} [patch]
+func (h *EventHandler) UpdateTicketType(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+	ticketTypeID := c.Param("ticketTypeID")
+
+	var req dto.UpdateTicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid ticket type data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	tt, err := h.EventService.UpdateTicketType(ctx, eventPublicID, ticketTypeID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.TicketTypeSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Ticket type updated successfully",
+		},
+		TicketType: toTicketTypeDTO(tt),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List events
+// @Description Search, filter and page through events. Pages are ordered by start date unless sort says otherwise; pass next_cursor back as cursor for the next page.
+// @Tags Events
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Keyword matched against title and description"
+// @Param from query string false "Only events starting at or after this time (RFC3339)"
+// @Param to query string false "Only events ending at or before this time (RFC3339)"
+// @Param available query bool false "Only events with seats left"
+// @Param organizer query string false "Organizer public ID"
+// @Param series query string false "Event series public ID, to list its occurrences"
+// @Param sort query string false "start_date, -start_date, created_at or -created_at"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} dto.ListEventsSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Router /api/v1/events [get]
+func (h *EventHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.ListEventsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.EventService.List(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListEventsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Events retrieved successfully",
+		},
+		EventListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update godoc
+// @Summary Update event
+// @Description Change the title, description, dates or max seats of an event (event organizer or admin only). With scope=following an occurrence of a series and every occurrence after it change, and the series is split there.
+// @Tags Events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param scope query string false "this (default) or following"
+// @Param request body dto.UpdateEventRequest true "Fields to change"
+// @Success 200 {object} dto.EventSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Failure 409 {object} errs.ErrorResponse "Title taken or max seats conflict"
+// @Router /api/v1/events/{publicID} [patch]
+func (h *EventHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	var query dto.UpdateEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	var req dto.UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid event data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	var event *Event
+	var err error
+	if query.Scope == "following" {
+		event, err = h.SeriesService.UpdateFollowing(ctx, eventPublicID, &req, userPublicID)
+	} else {
+		event, err = h.EventService.Update(ctx, eventPublicID, &req, userPublicID)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.EventSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Event updated successfully",
+		},
+		Event: toEventDTO(event),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Delete godoc
+// @Summary Delete event
+// @Description Delete an event (event organizer or admin only). Events with bookings are soft-deleted and come back with deleted_at set.
+// @Tags Events
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Success 200 {object} dto.EventSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Router /api/v1/events/{publicID} [delete]
+func (h *EventHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	event, err := h.EventService.Delete(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.EventSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Event deleted successfully",
+		},
+		Event: toEventDTO(event),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// toEventDTO renders the event with its times in the time zone of its venue.
+func toEventDTO(ev *Event) dto.EventDTO {
+	loc := ev.Location()
+	res := dto.EventDTO{
+		ID:             ev.ID,
+		PublicID:       ev.PublicID,
+		Title:          ev.Title,
+		Description:    ev.Description,
+		StartDate:      ev.StartDate.In(loc),
+		EndDate:        ev.EndDate.In(loc),
+		MaxSeats:       ev.MaxSeats,
+		AvailableSeats: ev.AvailableSeats,
+		Currency:       ev.Currency,
+		Venue:          toEventVenueDTO(ev.Venue),
+		CreatedAt:      ev.CreatedAt,
+		UpdatedAt:      ev.UpdatedAt,
+	}
+	if ev.DeletedAt.Valid {
+		res.DeletedAt = &ev.DeletedAt.Time
+	}
+	return res
+}
+
+func toEventVenueDTO(venue *Venue) *dto.EventVenueDTO {
+	if venue == nil {
+		return nil
+	}
+	return &dto.EventVenueDTO{
+		PublicID: venue.PublicID,
+		Name:     venue.Name,
+		TimeZone: venue.TimeZone,
+	}
+}
+
+func toTicketTypeDTO(tt *TicketType) dto.TicketTypeDTO {
+	return dto.TicketTypeDTO{
+		PublicID:    tt.PublicID,
+		Name:        tt.Name,
+		Price:       tt.Price,
+		Quota:       tt.Quota,
+		Available:   tt.Available,
+		MaxPerOrder: tt.MaxPerOrder,
+	}
+}
@@ -0,0 +1,166 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/rrule"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+const materializeBatchSize = 100
+
+// Materializer creates the occurrences of event series as they come within
+// the horizon, so a series without an end only ever has a few months of
+// events. Several replicas may run it at the same time; occurrences are
+// unique per series and start.
+type Materializer struct {
+	repo SeriesRepositoryInterface
+	horizon time.Duration
+	interval time.Duration
+	logger zerolog.Logger
+}
+
+func NewMaterializer(repo SeriesRepositoryInterface, cfg *config.AppConfig, logger zerolog.Logger) *Materializer {
+	return &Materializer{
+		repo: repo,
+		horizon: cfg.EventSeries.Horizon,
+		interval: cfg.EventSeries.MaterializeInterval,
+		logger: logger,
+	}
+}
+
+// Run materializes due series every interval until ctx is cancelled.
+func (m *Materializer) Run(ctx context.Context) {
+	m.logger.Info().
+		Dur("interval", m.interval).
+		Dur("horizon", m.horizon).
+		Msg("event series materializer started")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info().Msg("event series materializer stopped")
+			return
+		case <-ticker.C:
+			if _, err := m.MaterializeDue(ctx, time.Now()); err != nil {
+				m.logger.Error().Err(err).Msg("failed to materialize event series")
+			}
+		}
+	}
+}
+
+// MaterializeDue materializes every series that is behind the horizon and
+// returns how many occurrences were created. A series that fails is logged
+// and left for the next run.
+func (m *Materializer) MaterializeDue(ctx context.Context, now time.Time) (int, error) {
+	until := m.until(now)
+	total := 0
+	var afterID uint
+	for {
+		due, err := m.repo.ListDueSeries(ctx, until, afterID, materializeBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for i := range due {
+			created, err := m.Materialize(ctx, &due[i], now)
+			if err != nil {
+				m.logger.Error().Err(err).
+					Uint("series_id", due[i].ID).
+					Msg("failed to materialize event series")
+				continue
+			}
+			total += created
+		}
+
+		if len(due) < materializeBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+		afterID = due[len(due)-1].ID
+	}
+}
+
+// Materialize creates the occurrences of series from its watermark up to the
+// horizon and returns how many were created. The rule is expanded in the
+// time zone of the series' venue.
+func (m *Materializer) Materialize(ctx context.Context, series *EventSeries, now time.Time) (int, error) {
+	until := m.until(now)
+	if series.Finished || !series.MaterializedUntil.Before(until) {
+		return 0, nil
+	}
+
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return 0, err
+	}
+
+	// A bounded rule is finished when it runs out before the horizon.
+	finished := rule.Bounded()
+	var starts []time.Time
+	for start := range rule.All(series.StartDate.In(series.Location())) {
+		if !start.Before(until) {
+			finished = false
+			break
+		}
+		if !start.Before(series.MaterializedUntil) {
+			starts = append(starts, start)
+		}
+	}
+
+	duration := series.EndDate.Sub(series.StartDate)
+	occurrences := make([]Event, 0, len(starts))
+	for _, start := range starts {
+		publicID, err := util.GeneratePublicID(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate public ID: %w", err)
+		}
+		occurrenceStart := start.UTC()
+		occurrences = append(occurrences, Event{
+			PublicID: publicID,
+			Title: series.Title,
+			Description: series.Description,
+			StartDate: start.UTC(),
+			EndDate: start.Add(duration).UTC(),
+			MaxSeats: series.MaxSeats,
+			AvailableSeats: series.MaxSeats,
+			OrganizerID: series.OrganizerID,
+			Currency: series.Currency,
+			VenueID: series.VenueID,
+			SeriesID: &series.ID,
+			OccurrenceStart: &occurrenceStart,
+		})
+	}
+
+	created, err := m.repo.AddOccurrences(ctx, series, occurrences, until, finished)
+	if errors.Is(err, ErrSeriesChanged) {
+		m.logger.Info().
+			Uint("series_id", series.ID).
+			Msg("event series changed while materializing, skipped")
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if created > 0 {
+		m.logger.Info().
+			Uint("series_id", series.ID).
+			Int("occurrences", created).
+			Time("until", until).
+			Msg("event series materialized")
+	}
+	return created, nil
+}
+
+// until is the end of the horizon, cut to the second like the dates stored.
+func (m *Materializer) until(now time.Time) time.Time {
+	return now.Add(m.horizon).Truncate(time.Second)
+}
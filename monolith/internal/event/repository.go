@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/money"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepositoryInterface interface {
@@ -16,6 +19,63 @@ type EventRepositoryInterface interface {
 	FindByTitle(ctx context.Context, title string) (*Event, error)
 	FindByID(ctx context.Context, id uint) (*Event, error)
 	FindByPublicID(ctx context.Context, publicID string) (*Event, error) 
+	AddTicketType(ctx context.Context, eventID uint, tt *TicketType) error
+	UpdateTicketType(ctx context.Context, eventID uint, publicID string, update TicketTypeUpdate) (*TicketType, error)
+	Update(ctx context.Context, eventID uint, update EventUpdate) (*Event, error)
+	Delete(ctx context.Context, eventID uint) (*Event, error)
+	List(ctx context.Context, filter EventFilter) ([]Event, error)
+	FindVenueByPublicID(ctx context.Context, publicID string) (*Venue, error)
+}
+
+// Orders of List. The start date orders use idx_events_start_date; the
+// creation orders follow the id.
+const (
+	SortStartDate = "start_date"
+	SortStartDateDesc = "-start_date"
+	SortCreatedAt = "created_at"
+	SortCreatedAtDesc = "-created_at"
+)
+
+// EventFilter narrows the events returned by List. Zero fields are not
+// filtered on. AfterStartDate and AfterID are the position of the last event
+// of the previous page in the order of Sort; a zero AfterID starts from the
+// first event. SeriesPublicID keeps the occurrences of that series.
+type EventFilter struct {
+	Keyword string
+	From time.Time
+	To time.Time
+	Available bool
+	OrganizerID uint
+	SeriesPublicID string
+	Sort string
+	AfterStartDate time.Time
+	AfterID uint
+	Limit int
+}
+
+// EventUpdate holds the fields of an event to change; nil fields are left
+// as they are.
+type EventUpdate struct {
+	Title *string
+	Description *string
+	StartDate *time.Time
+	EndDate *time.Time
+	MaxSeats *uint64
+	Venue *Venue
+}
+
+// TicketTypeUpdate holds the fields of a ticket type to change; nil fields
+// are left as they are.
+type TicketTypeUpdate struct {
+	Name *string
+	Price *money.Money
+	Quota *uint64
+	MaxPerOrder *uint
+}
+
+type eventSeatsRow struct {
+	ID uint
+	MaxSeats uint64
 }
 
 type EventRepository struct {
@@ -44,9 +104,11 @@ func (r *EventRepository) Create(ctx context.Context, event *Event) (*Event, err
 	return event, nil
 }
 
+// FindByTitle finds the standalone event with the title. Occurrences of a
+// series share the title of their series and are left out.
 func (r *EventRepository) FindByTitle(ctx context.Context, title string) (*Event, error) {
 	event := &Event{}
-	err := r.db.WithContext(ctx).Take(event, "title = ?", title).Error
+	err := r.db.WithContext(ctx).Take(event, "title = ? AND series_id IS NULL", title).Error
 	if err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
             return nil, errs.NewErrNotFound("event")
@@ -61,7 +123,7 @@ func (r *EventRepository) FindByTitle(ctx context.Context, title string) (*Event
 
 func (r *EventRepository) FindByID(ctx context.Context, id uint) (*Event, error)  {
 	event := &Event{}
-	err := r.db.WithContext(ctx).First(event, id).Error
+	err := r.db.WithContext(ctx).Preload("Venue").First(event, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.NewErrNotFound("event")
@@ -76,7 +138,7 @@ func (r *EventRepository) FindByID(ctx context.Context, id uint) (*Event, error)
 
 func (r *EventRepository) FindByPublicID(ctx context.Context, publicID string) (*Event, error) {
 	event := &Event{}
-	err := r.db.WithContext(ctx).Take(event, "public_id = ?", publicID).Error
+	err := r.db.WithContext(ctx).Preload("Venue").Take(event, "public_id = ?", publicID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errs.NewErrNotFound("event")
@@ -89,6 +151,364 @@ func (r *EventRepository) FindByPublicID(ctx context.Context, publicID string) (
 	return event, nil
 }
 
+// List returns the events matching filter in the order of filter.Sort,
+// start date first by default. Keyword is matched against the title and the
+// description; From and To bound the start and end dates.
+func (r *EventRepository) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	q := r.db.WithContext(ctx).Model(&Event{})
+
+	if filter.Keyword != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Keyword) + "%"
+		q = q.Where("(title LIKE ? OR description LIKE ?)", pattern, pattern)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("start_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("end_date <= ?", filter.To)
+	}
+	if filter.Available {
+		q = q.Where("available_seats > 0")
+	}
+	if filter.OrganizerID > 0 {
+		q = q.Where("organizer_id = ?", filter.OrganizerID)
+	}
+	if filter.SeriesPublicID != "" {
+		q = q.Where("series_id IN (?)", r.db.Model(&EventSeries{}).
+			Select("id").
+			Where("public_id = ?", filter.SeriesPublicID))
+	}
+
+	switch filter.Sort {
+	case SortStartDateDesc:
+		if filter.AfterID > 0 {
+			q = q.Where("(start_date < ? OR (start_date = ? AND id < ?))",
+				filter.AfterStartDate, filter.AfterStartDate, filter.AfterID)
+		}
+		q = q.Order("start_date DESC, id DESC")
+	case SortCreatedAt:
+		if filter.AfterID > 0 {
+			q = q.Where("id > ?", filter.AfterID)
+		}
+		q = q.Order("id")
+	case SortCreatedAtDesc:
+		if filter.AfterID > 0 {
+			q = q.Where("id < ?", filter.AfterID)
+		}
+		q = q.Order("id DESC")
+	default:
+		if filter.AfterID > 0 {
+			q = q.Where("(start_date > ? OR (start_date = ? AND id > ?))",
+				filter.AfterStartDate, filter.AfterStartDate, filter.AfterID)
+		}
+		q = q.Order("start_date, id")
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var events []Event
+	if err := q.Preload("Venue").Find(&events).Error; err != nil {
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("keyword", filter.Keyword).
+			Uint("organizer_id", filter.OrganizerID).
+			Msg("list events failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return events, nil
+}
+
+// AddTicketType adds a tier to the event. The event row is locked so
+// concurrent changes can not push the sum of quotas above MaxSeats.
+func (r *EventRepository) AddTicketType(ctx context.Context, eventID uint, tt *TicketType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEventSeats(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		allocated, err := r.allocatedQuota(tx, eventID, 0)
+		if err != nil {
+			return err
+		}
+		if allocated+tt.Quota > ev.MaxSeats {
+			return ErrQuotaExceedsSeats
+		}
+
+		tt.EventID = eventID
+		tt.Available = tt.Quota
+		if err := tx.Create(tt).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("insert ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+// UpdateTicketType applies update to a tier of the event. Changing the quota
+// moves Available by the same amount and is refused when fewer seats than
+// already sold would remain.
+func (r *EventRepository) UpdateTicketType(ctx context.Context, eventID uint, publicID string, update TicketTypeUpdate) (*TicketType, error) {
+	var tt TicketType
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEventSeats(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ? AND event_id = ?", publicID, eventID).
+			Take(&tt).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTicketTypeNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("ticket_type_public_id", publicID).
+				Msg("lock/select ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if update.Name != nil {
+			tt.Name = *update.Name
+		}
+		if update.Price != nil {
+			tt.Price = *update.Price
+		}
+		if update.MaxPerOrder != nil {
+			tt.MaxPerOrder = *update.MaxPerOrder
+		}
+		if update.Quota != nil {
+			sold := tt.Quota - tt.Available
+			if *update.Quota < sold {
+				return ErrQuotaBelowSold
+			}
+
+			allocated, err := r.allocatedQuota(tx, eventID, tt.ID)
+			if err != nil {
+				return err
+			}
+			if allocated+*update.Quota > ev.MaxSeats {
+				return ErrQuotaExceedsSeats
+			}
+
+			tt.Quota = *update.Quota
+			tt.Available = *update.Quota - sold
+		}
+
+		if uint64(tt.MaxPerOrder) > tt.Quota {
+			return ErrMaxPerOrderAboveQuota
+		}
+
+		if err := tx.Save(&tt).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("ticket_type_id", tt.ID).
+				Msg("update ticket type failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tt, nil
+}
+
+// Update applies update to the event. The event row is locked so bookings
+// can not take seats while MaxSeats changes: it can not drop below the
+// seats already sold nor below the quotas of the ticket types, and it is
+// fixed once a seat map is laid out.
+func (r *EventRepository) Update(ctx context.Context, eventID uint, update EventUpdate) (*Event, error) {
+	var ev Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Venue").
+			Take(&ev, eventID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("lock/select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return r.applyUpdate(tx, &ev, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// applyUpdate changes the event, locked by the caller, and saves it.
+func (r *EventRepository) applyUpdate(tx *gorm.DB, ev *Event, update EventUpdate) error {
+	eventID := ev.ID
+	if update.Title != nil {
+		ev.Title = *update.Title
+	}
+	if update.Description != nil {
+		ev.Description = update.Description
+	}
+	if update.StartDate != nil {
+		ev.StartDate = *update.StartDate
+	}
+	if update.EndDate != nil {
+		ev.EndDate = *update.EndDate
+	}
+	if update.Venue != nil {
+		ev.VenueID = &update.Venue.ID
+		ev.Venue = update.Venue
+	}
+	if ev.EndDate.Before(ev.StartDate) {
+		return ErrInvalidDates
+	}
+
+	if update.MaxSeats != nil && *update.MaxSeats != ev.MaxSeats {
+		sold := ev.MaxSeats - ev.AvailableSeats
+		if *update.MaxSeats < sold {
+			return ErrMaxSeatsBelowSold
+		}
+
+		allocated, err := r.allocatedQuota(tx, eventID, 0)
+		if err != nil {
+			return err
+		}
+		if allocated > *update.MaxSeats {
+			return ErrQuotaExceedsSeats
+		}
+
+		var seats int64
+		if err := tx.Table("seats").Where("event_id = ?", eventID).Count(&seats).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("count seats failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if seats > 0 {
+			return ErrSeatMapDefined
+		}
+
+		ev.MaxSeats = *update.MaxSeats
+		ev.AvailableSeats = *update.MaxSeats - sold
+	}
+
+	// The venue is only read here; saving it would write a partial row.
+	if err := tx.Omit("Venue").Save(ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errs.NewConflictError("event with this title already exists")
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("update event failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// Delete removes the event. An event that has bookings is soft-deleted so
+// its bookings, payments and tickets keep pointing at it; one without is
+// removed together with its ticket types and seat map.
+func (r *EventRepository) Delete(ctx context.Context, eventID uint) (*Event, error) {
+	var ev Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Venue").
+			Take(&ev, eventID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("lock/select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		var bookings int64
+		if err := tx.Table("bookings").Where("event_id = ?", eventID).Count(&bookings).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("count bookings failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		del := tx
+		if bookings == 0 {
+			del = tx.Unscoped()
+		}
+		if err := del.Delete(&ev).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Int64("bookings", bookings).
+				Msg("delete event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// FindVenueByPublicID finds the venue an event is held at.
+func (r *EventRepository) FindVenueByPublicID(ctx context.Context, publicID string) (*Venue, error) {
+	venue := &Venue{}
+	if err := r.db.WithContext(ctx).Take(venue, "public_id = ?", publicID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVenueNotFound
+		}
+		if isConnectionError(err) {
+			return nil, errs.NewServiceUnavailableError("database unavailable")
+		}
+		r.logger.Error().Err(err).
+			Str("venue_public_id", publicID).
+			Msg("select venue failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return venue, nil
+}
+
+func (r *EventRepository) lockEventSeats(tx *gorm.DB, eventID uint) (*eventSeatsRow, error) {
+	var ev eventSeatsRow
+	if err := tx.Table("events").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, max_seats").
+		Where("id = ? AND deleted_at IS NULL", eventID).
+		Take(&ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("lock/select event failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &ev, nil
+}
+
+// allocatedQuota sums the quotas of the event's tiers, leaving out the tier
+// with id exclude.
+func (r *EventRepository) allocatedQuota(tx *gorm.DB, eventID, exclude uint) (uint64, error) {
+	var total uint64
+	if err := tx.Model(&TicketType{}).
+		Select("COALESCE(SUM(quota), 0)").
+		Where("event_id = ? AND id <> ?", eventID, exclude).
+		Scan(&total).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("sum ticket type quotas failed")
+		return 0, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return total, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func isConnectionError(err error) bool {
     // Implement proper connection error detection
     return strings.Contains(err.Error(), "connection refused") || 
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	commonDTO "github.com/anrisys/quicket/internal/dto"
 	eventDTO "github.com/anrisys/quicket/internal/event/dto"
 	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/money"
 	"github.com/anrisys/quicket/pkg/types"
 	"github.com/anrisys/quicket/pkg/util"
 	"github.com/rs/zerolog"
@@ -18,9 +21,16 @@ type EventServiceInterface interface {
 	FindByID(ctx context.Context, id uint) (*Event, error)
 	FindByPublicID(ctx context.Context, publicID string) (*Event, error)
 	eventExistsByTitle(ctx context.Context, title string) (bool, error)
-	prepareEvent(ctx context.Context, req *eventDTO.CreateEventRequest, userID int) (*Event, error)
+	prepareEvent(ctx context.Context, req *eventDTO.CreateEventRequest, userID int, venue *Venue) (*Event, error)
+	AddTicketType(ctx context.Context, eventPublicID string, req *eventDTO.CreateTicketTypeRequest, userPublicID string) (*TicketType, error)
+	UpdateTicketType(ctx context.Context, eventPublicID, ticketTypePublicID string, req *eventDTO.UpdateTicketTypeRequest, userPublicID string) (*TicketType, error)
+	Update(ctx context.Context, eventPublicID string, req *eventDTO.UpdateEventRequest, userPublicID string) (*Event, error)
+	Delete(ctx context.Context, eventPublicID string, userPublicID string) (*Event, error)
+	List(ctx context.Context, query *eventDTO.ListEventsQuery) (*eventDTO.EventListDTO, error)
 }
 
+const defaultListLimit = 20
+
 type EventService struct {
 	repo EventRepositoryInterface
 	users types.UserReader
@@ -46,6 +56,14 @@ func (s *EventService) Create(ctx context.Context, req *eventDTO.CreateEventRequ
 		return nil, fmt.Errorf("event/service#create: %w", err)
 	}
 
+	venue, err := s.findVenue(ctx, req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStartDate(req.StartDate, venue.Location(), time.Now()); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.eventExistsByTitle(ctx, req.Title)
 	if err != nil {
 		return nil, fmt.Errorf("event/service#create: %w", err)
@@ -55,16 +73,25 @@ func (s *EventService) Create(ctx context.Context, req *eventDTO.CreateEventRequ
 		return nil, errs.NewConflictError("event with this title already exists")
 	}
 
-	newEv, err := s.prepareEvent(ctx, req, usr.ID)
+	newEv, err := s.prepareEvent(ctx, req, usr.ID, venue)
 	if err != nil {
 		return nil, err
 	}
 
+	var quotas uint64
+	for _, tt := range newEv.TicketTypes {
+		quotas += tt.Quota
+	}
+	if quotas > newEv.MaxSeats {
+		return nil, errs.NewValidationError("sum of ticket type quotas exceeds max seats")
+	}
+
 	registeredEvent, err := s.repo.Create(ctx, newEv)
 
 	if err != nil {
 		return nil, fmt.Errorf("event service#create: %w", err)
 	}
+	registeredEvent.Venue = venue
 
 	return registeredEvent, err
 }
@@ -85,6 +112,73 @@ func (s *EventService) FindByPublicID(ctx context.Context, publicID string) (*Ev
 	return event, nil
 }
 
+func (s *EventService) List(ctx context.Context, query *eventDTO.ListEventsQuery) (*eventDTO.EventListDTO, error) {
+	filter := EventFilter{
+		Keyword: strings.TrimSpace(query.Q),
+		From: query.From,
+		To: query.To,
+		Available: query.Available,
+		SeriesPublicID: query.Series,
+		Sort: query.Sort,
+	}
+
+	var err error
+	switch query.Sort {
+	case SortCreatedAt, SortCreatedAtDesc:
+		filter.AfterID, err = util.DecodeCursor(query.Cursor)
+	default:
+		filter.AfterStartDate, filter.AfterID, err = util.DecodeTimeCursor(query.Cursor)
+	}
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return nil, errs.NewValidationError("from must not be after to")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	list := &eventDTO.EventListDTO{
+		Events: make([]eventDTO.EventDTO, 0, limit),
+	}
+
+	if query.Organizer != "" {
+		usr, err := s.users.FindUserByPublicID(ctx, query.Organizer)
+		if errors.Is(err, errs.ErrNotFound) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event service#list: %w", err)
+		}
+		filter.OrganizerID = uint(usr.ID)
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+	events, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("event service#list: %w", err)
+	}
+
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		switch query.Sort {
+		case SortCreatedAt, SortCreatedAtDesc:
+			list.NextCursor = util.EncodeCursor(last.ID)
+		default:
+			list.NextCursor = util.EncodeTimeCursor(last.StartDate, last.ID)
+		}
+	}
+	for i := range events {
+		list.Events = append(list.Events, toEventDTO(&events[i]))
+	}
+	return list, nil
+}
+
 func (s *EventService) GetEventDateTimeAndSeats(ctx context.Context, publicID string) (*commonDTO.EventDateTimeAndSeats, error){
 	event, err := s.repo.FindByPublicID(ctx, publicID)
 	if err != nil {
@@ -97,6 +191,186 @@ func (s *EventService) GetEventDateTimeAndSeats(ctx context.Context, publicID st
 	}, nil
 }
 
+func (s *EventService) AddTicketType(ctx context.Context, eventPublicID string, req *eventDTO.CreateTicketTypeRequest, userPublicID string) (*TicketType, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	tt, err := s.prepareTicketType(ctx, req, ev.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddTicketType(ctx, ev.ID, tt); err != nil {
+		return nil, s.mapTicketTypeError(err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("ticket_type_public_id", tt.PublicID).
+		Msg("Ticket type created")
+	return tt, nil
+}
+
+func (s *EventService) UpdateTicketType(ctx context.Context, eventPublicID, ticketTypePublicID string, req *eventDTO.UpdateTicketTypeRequest, userPublicID string) (*TicketType, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Price != nil {
+		if err := validatePrice(*req.Price, ev.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	tt, err := s.repo.UpdateTicketType(ctx, ev.ID, ticketTypePublicID, TicketTypeUpdate{
+		Name: req.Name,
+		Price: req.Price,
+		Quota: req.Quota,
+		MaxPerOrder: req.MaxPerOrder,
+	})
+	if err != nil {
+		return nil, s.mapTicketTypeError(err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("ticket_type_public_id", tt.PublicID).
+		Msg("Ticket type updated")
+	return tt, nil
+}
+
+func (s *EventService) Update(ctx context.Context, eventPublicID string, req *eventDTO.UpdateEventRequest, userPublicID string) (*Event, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences share the title of their series.
+	if req.Title != nil && *req.Title != ev.Title && ev.SeriesID == nil {
+		other, err := s.repo.FindByTitle(ctx, *req.Title)
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return nil, fmt.Errorf("event service#update: %w", err)
+		}
+		if err == nil && other.ID != ev.ID {
+			return nil, errs.NewConflictError("event with this title already exists")
+		}
+	}
+
+	venue := ev.Venue
+	var newVenue *Venue
+	if req.VenueID != nil {
+		newVenue, err = s.findVenue(ctx, *req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if newVenue != nil {
+			venue = newVenue
+		}
+	}
+	if req.StartDate != nil {
+		if err := validateStartDate(*req.StartDate, venue.Location(), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, ev.ID, EventUpdate{
+		Title: req.Title,
+		Description: req.Description,
+		StartDate: inUTC(req.StartDate),
+		EndDate: inUTC(req.EndDate),
+		MaxSeats: req.MaxSeats,
+		Venue: newVenue,
+	})
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("user_id", userPublicID).
+		Msg("Event updated")
+	return updated, nil
+}
+
+// Delete removes the event. Events with bookings are soft-deleted, which
+// the returned event tells by its DeletedAt.
+func (s *EventService) Delete(ctx context.Context, eventPublicID string, userPublicID string) (*Event, error) {
+	ev, err := s.findManagedEvent(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.repo.Delete(ctx, ev.ID)
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("user_id", userPublicID).
+		Bool("soft", deleted.DeletedAt.Valid).
+		Msg("Event deleted")
+	return deleted, nil
+}
+
+// findManagedEvent loads the event and makes sure the user is its organizer
+// or an admin.
+func (s *EventService) findManagedEvent(ctx context.Context, eventPublicID, userPublicID string) (*Event, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("event/service#managed: %w", err)
+	}
+
+	ev, err := s.repo.FindByPublicID(ctx, eventPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if usr.Role != "admin" && ev.OrganizerID != uint(usr.ID) {
+		return nil, errs.NewForbiddenError("only the organizer of this event can manage it")
+	}
+	return ev, nil
+}
+
+func (s *EventService) mapTicketTypeError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrTicketTypeNotFound):
+		return errs.NewErrNotFound("ticket type")
+	case errors.Is(err, ErrQuotaExceedsSeats):
+		return errs.NewConflictError("sum of ticket type quotas exceeds max seats")
+	case errors.Is(err, ErrQuotaBelowSold):
+		return errs.NewConflictError("quota can not be lower than the seats already sold")
+	case errors.Is(err, ErrMaxPerOrderAboveQuota):
+		return errs.NewValidationError("max per order can not be greater than the quota")
+	default:
+		return fmt.Errorf("event service#ticket type: %w", err)
+	}
+}
+
+func (s *EventService) mapEventError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrInvalidDates):
+		return errs.NewValidationError("end date can not be before the start date")
+	case errors.Is(err, ErrMaxSeatsBelowSold):
+		return errs.NewConflictError("max seats can not be lower than the seats already sold")
+	case errors.Is(err, ErrQuotaExceedsSeats):
+		return errs.NewConflictError("max seats can not be lower than the sum of ticket type quotas")
+	case errors.Is(err, ErrSeatMapDefined):
+		return errs.NewConflictError("max seats can not change once the seat map is laid out")
+	case errors.Is(err, ErrVenueNotFound):
+		return errs.NewErrNotFound("venue")
+	default:
+		return fmt.Errorf("event service#event: %w", err)
+	}
+}
+
 func (s *EventService) eventExistsByTitle(ctx context.Context, title string) (bool, error) {
 	_, err := s.repo.FindByTitle(ctx, title)
 	if err == nil {
@@ -108,20 +382,121 @@ func (s *EventService) eventExistsByTitle(ctx context.Context, title string) (bo
 	return false, err
 }
 
-func (s *EventService) prepareEvent(ctx context.Context, req *eventDTO.CreateEventRequest, userID int) (*Event, error) {
+func (s *EventService) prepareEvent(ctx context.Context, req *eventDTO.CreateEventRequest, userID int, venue *Venue) (*Event, error) {
+	maxSeats, err := maxSeatsAt(req.MaxSeats, venue)
+	if err != nil {
+		return nil, err
+	}
+
 	publicID, err := util.GeneratePublicID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate public ID: %w", err)
 	}
 
-	return &Event{
+	currency := money.DefaultCurrency
+	if req.Currency != "" {
+		currency = money.Currency(req.Currency)
+	}
+
+	ticketTypes := make([]TicketType, 0, len(req.TicketTypes))
+	for i := range req.TicketTypes {
+		tt, err := s.prepareTicketType(ctx, &req.TicketTypes[i], currency)
+		if err != nil {
+			return nil, err
+		}
+		ticketTypes = append(ticketTypes, *tt)
+	}
+
+	ev := &Event{
 		PublicID: publicID,
 		Title: req.Title,
 		Description: &req.Description,
 		OrganizerID: uint(userID),
-		StartDate: req.StartDate,
-		EndDate: req.EndDate,
-		MaxSeats: req.MaxSeats,
-		AvailableSeats: req.MaxSeats,
+		StartDate: req.StartDate.UTC(),
+		EndDate: req.EndDate.UTC(),
+		MaxSeats: maxSeats,
+		AvailableSeats: maxSeats,
+		Currency: currency,
+		TicketTypes: ticketTypes,
+	}
+	if venue != nil {
+		ev.VenueID = &venue.ID
+	}
+	return ev, nil
+}
+
+// findVenue loads the venue an event is to be held at. An empty publicID is
+// no venue.
+func (s *EventService) findVenue(ctx context.Context, publicID string) (*Venue, error) {
+	if publicID == "" {
+		return nil, nil
+	}
+	venue, err := s.repo.FindVenueByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapEventError(err)
+	}
+	return venue, nil
+}
+
+// maxSeatsAt is maxSeats, or the capacity of the venue when it is not given.
+func maxSeatsAt(maxSeats uint64, venue *Venue) (uint64, error) {
+	if maxSeats > 0 {
+		return maxSeats, nil
+	}
+	if venue == nil {
+		return 0, errs.NewValidationError("max seats is required for an event without a venue")
+	}
+	return venue.Capacity, nil
+}
+
+// validateStartDate makes sure start falls after today in loc, the time
+// zone of the venue, rather than that of the server.
+func validateStartDate(start time.Time, loc *time.Location, now time.Time) error {
+	y, m, d := now.In(loc).Date()
+	if !start.After(time.Date(y, m, d+1, 0, 0, 0, 0, loc)) {
+		return errs.NewValidationError("start date must be after today")
+	}
+	return nil
+}
+
+// inUTC is t in UTC, the zone event times are stored in.
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func (s *EventService) prepareTicketType(ctx context.Context, req *eventDTO.CreateTicketTypeRequest, currency money.Currency) (*TicketType, error) {
+	if err := validatePrice(req.Price, currency); err != nil {
+		return nil, err
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public ID: %w", err)
+	}
+
+	return &TicketType{
+		PublicID: publicID,
+		Name: req.Name,
+		Price: req.Price,
+		Currency: currency,
+		Quota: req.Quota,
+		Available: req.Quota,
+		MaxPerOrder: req.MaxPerOrder,
 	}, nil
+}
+
+// validatePrice makes sure a ticket type price is not negative and is in
+// the currency of its event.
+func validatePrice(price money.Money, currency money.Currency) error {
+	if price.Currency != currency {
+		return errs.NewValidationError(fmt.Sprintf("ticket type price must be in %s", currency))
+	}
+	if price.Amount < 0 {
+		return errs.NewValidationError("ticket type price can not be negative")
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStartDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+
+	// 20:00 UTC on the 1st is already 03:00 on the 2nd in Jakarta.
+	now := time.Date(2030, 1, 1, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		loc   *time.Location
+		ok    bool
+	}{
+		{"tomorrow in UTC", time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC), time.UTC, true},
+		{"today in UTC", time.Date(2030, 1, 1, 23, 0, 0, 0, time.UTC), time.UTC, false},
+		{"today at the venue", time.Date(2030, 1, 2, 10, 0, 0, 0, jakarta), jakarta, false},
+		{"tomorrow at the venue", time.Date(2030, 1, 3, 10, 0, 0, 0, jakarta), jakarta, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStartDate(tt.start, tt.loc, now)
+			assert.Equal(t, tt.ok, err == nil)
+		})
+	}
+}
+
+func TestMaxSeatsAt(t *testing.T) {
+	venue := &Venue{Capacity: 300}
+
+	seats, err := maxSeatsAt(0, venue)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), seats)
+
+	seats, err = maxSeatsAt(120, venue)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120), seats)
+
+	_, err = maxSeatsAt(0, nil)
+	assert.Error(t, err)
+}
@@ -6,6 +6,11 @@ var ProviderSet = wire.NewSet(
 	NewEventRepository,
 	NewEventService,
 	NewEventHandler,
+	NewMaterializer,
+	NewSeriesService,
+	NewSeriesHandler,
 	wire.Bind(new(EventRepositoryInterface), new(*EventRepository)),
+	wire.Bind(new(SeriesRepositoryInterface), new(*EventRepository)),
 	wire.Bind(new(EventServiceInterface), new(*EventService)),
+	wire.Bind(new(SeriesServiceInterface), new(*SeriesService)),
 )
@@ -0,0 +1,135 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+const (
+	HeaderKey = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	maxKeyLength = 255
+)
+
+type Middleware struct {
+	repo Repository
+	ttl time.Duration
+	logger zerolog.Logger
+}
+
+func NewMiddleware(repo Repository, cfg *config.AppConfig, logger zerolog.Logger) *Middleware {
+	return &Middleware{
+		repo: repo,
+		ttl: cfg.Idempotency.KeyTTL,
+		logger: logger,
+	}
+}
+
+// Handle makes the wrapped route safe to retry. Requests carrying an
+// Idempotency-Key header are keyed per authenticated user: the first one
+// runs and its response is stored, later ones with the same body get the
+// stored response back. Responses that end in an error are not stored, so
+// the client can retry them with the same key.
+func (m *Middleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.Error(errs.NewValidationError("Idempotency-Key is too long"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(errs.NewValidationError("Invalid request body", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := c.GetString("publicID")
+		hash := requestHash(c.Request, body)
+
+		record, err := m.repo.Begin(ctx, scope, key, hash, m.ttl)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrKeyMismatch):
+				c.Error(errs.NewUnprocessableEntityError("Idempotency-Key was already used for a different request"))
+			case errors.Is(err, ErrKeyInProgress):
+				c.Error(errs.NewConflictError("a request with this Idempotency-Key is still in progress"))
+			default:
+				c.Error(err)
+			}
+			c.Abort()
+			return
+		}
+
+		if record != nil {
+			c.Header(HeaderReplayed, "true")
+			c.Data(record.ResponseCode, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// The client may have gone away; the outcome still has to be saved.
+		ctx = context.WithoutCancel(ctx)
+		status := recorder.Status()
+		if len(c.Errors) > 0 || status >= http.StatusInternalServerError || !recorder.Written() {
+			if err := m.repo.Release(ctx, scope, key); err != nil {
+				m.logger.Error().Err(err).Str("user_public_id", scope).Msg("failed to release idempotency key")
+			}
+			return
+		}
+
+		if err := m.repo.Complete(ctx, scope, key, status, recorder.body.Bytes()); err != nil {
+			m.logger.Error().Err(err).Str("user_public_id", scope).Msg("failed to store idempotent response")
+		}
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
\ No newline at end of file
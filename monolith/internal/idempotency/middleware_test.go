@@ -0,0 +1,152 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRepo struct {
+	mu   sync.Mutex
+	keys map[string]*Key
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{keys: map[string]*Key{}}
+}
+
+func (m *memoryRepo) Begin(ctx context.Context, scope, key, requestHash string, ttl time.Duration) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k, ok := m.keys[scope+"/"+key]
+	if !ok {
+		m.keys[scope+"/"+key] = &Key{RequestHash: requestHash, Status: StatusInProgress}
+		return nil, nil
+	}
+	if k.RequestHash != requestHash {
+		return nil, ErrKeyMismatch
+	}
+	if k.Status != StatusCompleted {
+		return nil, ErrKeyInProgress
+	}
+	return &Record{ResponseCode: *k.ResponseCode, ResponseBody: k.ResponseBody}, nil
+}
+
+func (m *memoryRepo) Complete(ctx context.Context, scope, key string, code int, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := m.keys[scope+"/"+key]
+	k.Status = StatusCompleted
+	k.ResponseCode = &code
+	k.ResponseBody = body
+	return nil
+}
+
+func (m *memoryRepo) Release(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, scope+"/"+key)
+	return nil
+}
+
+func setupRouter(repo Repository, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.AppConfig{Idempotency: config.IdempotencyConfig{KeyTTL: time.Hour}}
+	m := NewMiddleware(repo, cfg, zerolog.Nop())
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/bookings/:id", func(c *gin.Context) {
+		c.Set("publicID", "user-1")
+	}, m.Handle(), handler)
+	return r
+}
+
+func doRequest(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings/event-1", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	r := setupRouter(newMemoryRepo(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := doRequest(r, "key-1", `{"seats":2}`)
+	second := doRequest(r, "key-1", `{"seats":2}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+}
+
+func TestMiddleware_DifferentBody(t *testing.T) {
+	r := setupRouter(newMemoryRepo(), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	doRequest(r, "key-1", `{"seats":2}`)
+	w := doRequest(r, "key-1", `{"seats":3}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	repo := newMemoryRepo()
+	r := setupRouter(repo, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+	_, _ = repo.Begin(context.Background(), "user-1", "key-1", requestHash(httptest.NewRequest(http.MethodPost, "/bookings/event-1", nil), []byte(`{"seats":2}`)), time.Hour)
+
+	w := doRequest(r, "key-1", `{"seats":2}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMiddleware_ErrorIsNotStored(t *testing.T) {
+	calls := 0
+	r := setupRouter(newMemoryRepo(), func(c *gin.Context) {
+		calls++
+		c.Error(assert.AnError)
+	})
+
+	doRequest(r, "key-1", `{"seats":2}`)
+	doRequest(r, "key-1", `{"seats":2}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddleware_WithoutKey(t *testing.T) {
+	calls := 0
+	r := setupRouter(newMemoryRepo(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	doRequest(r, "", `{"seats":2}`)
+	doRequest(r, "", `{"seats":2}`)
+
+	assert.Equal(t, 2, calls)
+}
\ No newline at end of file
@@ -0,0 +1,25 @@
+package idempotency
+
+import "time"
+
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted = "completed"
+)
+
+type Key struct {
+	ID uint `gorm:"primarykey"`
+	Scope string `gorm:"column:scope;size:64;not null;uniqueIndex:uq_idempotency_keys_scope_key"`
+	Key string `gorm:"column:idempotency_key;size:255;not null;uniqueIndex:uq_idempotency_keys_scope_key"`
+	RequestHash string `gorm:"column:request_hash;type:char(64);not null"`
+	Status string `gorm:"column:status;type:ENUM('in_progress', 'completed');default:'in_progress'"`
+	ResponseCode *int `gorm:"column:response_code"`
+	ResponseBody []byte `gorm:"column:response_body"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (k *Key) TableName() string {
+	return "idempotency_keys"
+}
\ No newline at end of file
@@ -0,0 +1,146 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Record is a completed response stored under an idempotency key.
+type Record struct {
+	ResponseCode int
+	ResponseBody []byte
+}
+
+type Repository interface {
+	// Begin claims key within scope for a request with the given hash. It
+	// returns a nil record when the caller owns the key and should run the
+	// request, or the stored record when the request already completed.
+	Begin(ctx context.Context, scope, key, requestHash string, ttl time.Duration) (*Record, error)
+	Complete(ctx context.Context, scope, key string, code int, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
+	return &GormRepository{
+		db: db,
+		logger: logger,
+	}
+}
+
+func (r *GormRepository) Begin(ctx context.Context, scope, key, requestHash string, ttl time.Duration) (*Record, error) {
+	now := time.Now()
+	k := Key{
+		Scope: scope,
+		Key: key,
+		RequestHash: requestHash,
+		Status: StatusInProgress,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&k)
+	if res.Error != nil {
+		r.logger.Error().Err(res.Error).
+			Str("scope", scope).
+			Msg("insert idempotency key failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, res.Error)
+	}
+	if res.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing Key
+	if err := r.db.WithContext(ctx).
+		Where("scope = ? AND idempotency_key = ?", scope, key).
+		Take(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released between the insert and the select.
+			return nil, ErrKeyInProgress
+		}
+		r.logger.Error().Err(err).
+			Str("scope", scope).
+			Msg("select idempotency key failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if !existing.ExpiresAt.After(now) {
+		return nil, r.reclaim(ctx, &existing, requestHash, now.Add(ttl))
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrKeyMismatch
+	}
+
+	if existing.Status != StatusCompleted || existing.ResponseCode == nil {
+		return nil, ErrKeyInProgress
+	}
+
+	return &Record{
+		ResponseCode: *existing.ResponseCode,
+		ResponseBody: existing.ResponseBody,
+	}, nil
+}
+
+// reclaim takes over an expired key. Only one of several concurrent
+// requests wins; the others see the key as in progress.
+func (r *GormRepository) reclaim(ctx context.Context, k *Key, requestHash string, expiresAt time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&Key{}).
+		Where("id = ? AND expires_at = ?", k.ID, k.ExpiresAt).
+		Updates(map[string]any{
+			"request_hash": requestHash,
+			"status": StatusInProgress,
+			"response_code": nil,
+			"response_body": nil,
+			"expires_at": expiresAt,
+		})
+	if res.Error != nil {
+		r.logger.Error().Err(res.Error).
+			Uint("idempotency_key_id", k.ID).
+			Msg("reclaim idempotency key failed")
+		return fmt.Errorf("%w: %v", ErrDB, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrKeyInProgress
+	}
+	return nil
+}
+
+func (r *GormRepository) Complete(ctx context.Context, scope, key string, code int, body []byte) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Key{}).
+		Where("scope = ? AND idempotency_key = ?", scope, key).
+		Updates(map[string]any{
+			"status": StatusCompleted,
+			"response_code": code,
+			"response_body": body,
+		}).Error; err != nil {
+		r.logger.Error().Err(err).
+			Str("scope", scope).
+			Msg("complete idempotency key failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func (r *GormRepository) Release(ctx context.Context, scope, key string) error {
+	if err := r.db.WithContext(ctx).
+		Where("scope = ? AND idempotency_key = ? AND status = ?", scope, key, StatusInProgress).
+		Delete(&Key{}).Error; err != nil {
+		r.logger.Error().Err(err).
+			Str("scope", scope).
+			Msg("release idempotency key failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
\ No newline at end of file
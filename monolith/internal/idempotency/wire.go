@@ -0,0 +1,9 @@
+package idempotency
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	NewGormRepository,
+	NewMiddleware,
+	wire.Bind(new(Repository), new(*GormRepository)),
+)
\ No newline at end of file
@@ -1,7 +1,50 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
 type CreatePaymentRequest struct {
-	Amount    float32 `json:"amount" binding:"required"`
-	BookingID string  `json:"booking_id" binding:"required"`
-	Status    string  `json:"status" binding:"required,payStatus"`
+	Amount    money.Money `json:"amount"`
+	BookingID string      `json:"booking_id" binding:"required"`
+	Status    string      `json:"status" binding:"required,payStatus"`
+}
+// WebhookEventRequest is an event delivered by the payment provider.
+// PaymentID and BookingID are the public IDs sent when the charge was
+// authorized.
+type WebhookEventRequest struct {
+	ID   string           `json:"id" binding:"required,max=64"`
+	Type string           `json:"type" binding:"required,oneof=payment.succeeded payment.failed payment.refunded payment.disputed"`
+	Data WebhookEventData `json:"data" binding:"required"`
+}
+
+type WebhookEventData struct {
+	PaymentID string      `json:"payment_id" binding:"required"`
+	BookingID string      `json:"booking_id"`
+	ChargeID  string      `json:"charge_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+// CreateRefundRequest refunds part of a payment, or what is left of it when
+// Amount is omitted. Amount must be in the currency of the payment.
+type CreateRefundRequest struct {
+	Amount *money.Money `json:"amount"`
+	Reason string       `json:"reason" binding:"max=255"`
+}
+
+// ListPaymentsQuery filters the payment listing. From and To are RFC 3339
+// times bounding when the payment was recorded; To is exclusive.
+type ListPaymentsQuery struct {
+	Status string    `form:"status" binding:"omitempty,oneof=success failed refunded disputed"`
+	From   time.Time `form:"from"`
+	To     time.Time `form:"to"`
+	Cursor string    `form:"cursor"`
+	Limit  int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type ListReconciliationReportsQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
 }
\ No newline at end of file
@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"time"
+
+	commonDTO "github.com/anrisys/quicket/internal/dto"
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+type ResponseSuccess struct {
+	Code    string `json:"code" example:"SUCCESS"`
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type WebhookSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Result          string `json:"result" example:"applied"`
+}
+
+type RefundDTO struct {
+	PublicID       string      `json:"id"`
+	Amount         money.Money `json:"amount"`
+	Status         string      `json:"status" example:"succeeded"`
+	Reason         *string     `json:"reason,omitempty"`
+	FailureMessage *string     `json:"failure_message,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// PaymentSummaryDTO is a payment with the total of its succeeded refunds.
+type PaymentSummaryDTO struct {
+	PublicID       string      `json:"id"`
+	Amount         money.Money `json:"amount"`
+	Status         string      `json:"status"`
+	RefundedAmount money.Money `json:"refunded_amount"`
+}
+
+type IssueRefundDTO struct {
+	Refund  RefundDTO         `json:"refund"`
+	Payment PaymentSummaryDTO `json:"payment"`
+}
+
+type PaymentRefundsDTO struct {
+	Payment PaymentSummaryDTO `json:"payment"`
+	Refunds []RefundDTO       `json:"refunds"`
+}
+
+type IssueRefundSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Refund          RefundDTO         `json:"refund"`
+	Payment         PaymentSummaryDTO `json:"payment"`
+}
+
+type PaymentRefundsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Payment         PaymentSummaryDTO `json:"payment"`
+	Refunds         []RefundDTO       `json:"refunds"`
+}
+
+type PaymentListDTO struct {
+	Payments   []commonDTO.PaymentDTO `json:"payments"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+type PaymentSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Payment         commonDTO.PaymentDTO `json:"payment"`
+}
+
+type BookingPaymentsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Payments        []commonDTO.PaymentDTO `json:"payments"`
+}
+
+type ListPaymentsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	PaymentListDTO  `json:",inline"`
+}
+
+// ReconciliationMismatchDTO is a difference between the provider's record
+// of a charge and ours. The values are left out for a side with no record.
+type ReconciliationMismatchDTO struct {
+	Kind          string  `json:"kind" example:"status_mismatch"`
+	ProviderRef   *string `json:"provider_ref,omitempty"`
+	PaymentID     *string `json:"payment_id,omitempty"`
+	BookingID     *string `json:"booking_id,omitempty"`
+	ProviderValue *string `json:"provider_value,omitempty" example:"refunded"`
+	LocalValue    *string `json:"local_value,omitempty" example:"success"`
+}
+
+// ReconciliationReportDTO covers the charges opened in [from, to). Mismatches
+// is only filled in when a single report is read.
+type ReconciliationReportDTO struct {
+	PublicID      string                      `json:"id"`
+	Provider      string                      `json:"provider"`
+	From          time.Time                   `json:"from"`
+	To            time.Time                   `json:"to"`
+	Checked       int                         `json:"checked"`
+	MismatchCount int                         `json:"mismatch_count"`
+	Mismatches    []ReconciliationMismatchDTO `json:"mismatches,omitempty"`
+	CreatedAt     time.Time                   `json:"created_at"`
+}
+
+type ReconciliationReportListDTO struct {
+	Reports    []ReconciliationReportDTO `json:"reports"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+type ReconciliationReportSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Report          ReconciliationReportDTO `json:"report"`
+}
+
+type ListReconciliationReportsSuccessResponse struct {
+	ResponseSuccess             `json:",inline"`
+	ReconciliationReportListDTO `json:",inline"`
+}
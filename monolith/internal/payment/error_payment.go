@@ -4,5 +4,21 @@ import "errors"
 
 var (
 	ErrBookingNotFound = errors.New("booking not found")
+	ErrBookingNotPending = errors.New("booking is no longer pending")
+	ErrChargeNotFound = errors.New("charge not found at provider")
+	ErrChargeState = errors.New("charge is not in a state that allows this operation")
+	ErrProviderUnavailable = errors.New("payment provider unavailable")
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	ErrWebhookTimestamp = errors.New("webhook timestamp outside tolerance")
+	ErrDuplicateEvent = errors.New("webhook event already processed")
+	ErrPaymentNotRefundable = errors.New("payment can not be refunded in its current status")
+	ErrRefundExceedsPayment = errors.New("refund exceeds the amount left on the payment")
+	ErrRefundCurrencyMismatch = errors.New("refund currency does not match the payment")
+	ErrRefundNotFound = errors.New("refund not found")
+	ErrReportNotFound = errors.New("reconciliation report not found")
+	ErrQueueFull = errors.New("payment job queue is full")
+	ErrPaymentInProgress = errors.New("a payment for this booking is already in progress")
+	ErrLeaseLost = errors.New("payment job lease expired")
 	ErrDB = errors.New("database error")
-)
\ No newline at end of file
+)
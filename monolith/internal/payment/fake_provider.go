@@ -0,0 +1,88 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// Outcomes the fake provider can be scripted with.
+const (
+	FakeApprove = "approve"
+	FakeDecline = "decline"
+	FakeError = "error"
+)
+
+// Magic cents that override the configured outcome of the fake provider,
+// so a single run can exercise every path: an amount of 1051 minor units is
+// declined and 1052 fails as if the processor were unreachable.
+const (
+	FakeDeclineCents = 51
+	FakeErrorCents = 52
+)
+
+// FakeProvider answers instantly and deterministically. Every charge gets
+// the configured outcome unless its amount ends in one of the magic cents.
+type FakeProvider struct {
+	outcome string
+	charges *ledger
+}
+
+func NewFakeProvider(outcome string) *FakeProvider {
+	if outcome == "" {
+		outcome = FakeApprove
+	}
+	return &FakeProvider{
+		outcome: outcome,
+		charges: newLedger(),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+func (p *FakeProvider) Authorize(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	switch p.outcomeFor(req.Amount) {
+	case FakeError:
+		return nil, fmt.Errorf("%w: scripted failure for payment %s", ErrProviderUnavailable, req.PaymentID)
+	case FakeDecline:
+		res := p.charges.open(req, ChargeDeclined)
+		res.Message = "scripted decline"
+		return res, nil
+	default:
+		return p.charges.open(req, ChargeAuthorized), nil
+	}
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, reference string, amount money.Money) (*ChargeResult, error) {
+	return p.charges.move(reference, ChargeAuthorized, ChargeCaptured)
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, reference, refundID string, amount money.Money) (*ChargeResult, error) {
+	if p.outcome == FakeError {
+		return nil, fmt.Errorf("%w: scripted refund failure", ErrProviderUnavailable)
+	}
+	return p.charges.refund(reference, refundID, amount)
+}
+
+func (p *FakeProvider) Status(ctx context.Context, reference string) (*ChargeResult, error) {
+	return p.charges.status(reference)
+}
+
+func (p *FakeProvider) Transactions(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+	return p.charges.transactions(from, to), nil
+}
+
+func (p *FakeProvider) outcomeFor(amount money.Money) string {
+	switch cents(amount) {
+	case FakeDeclineCents:
+		return FakeDecline
+	case FakeErrorCents:
+		return FakeError
+	default:
+		return p.outcome
+	}
+}
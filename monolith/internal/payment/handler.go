@@ -1 +1,353 @@
-package payment
\ No newline at end of file
+package payment
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/payment/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	srv PaymentServiceInterface
+	refunds RefundServiceInterface
+	reconciler ReconciliationServiceInterface
+	logger zerolog.Logger
+}
+
+func NewHandler(srv PaymentServiceInterface, refunds RefundServiceInterface, reconciler ReconciliationServiceInterface, logger zerolog.Logger) *Handler {
+	return &Handler{
+		srv: srv,
+		refunds: refunds,
+		reconciler: reconciler,
+		logger: logger,
+	}
+}
+
+// Webhook godoc
+// @Summary Payment provider webhook
+// @Description Receive a payment event from the provider. The request must carry a timestamp and an HMAC-SHA256 signature of "<timestamp>.<body>". Redelivered events are acknowledged without being applied twice.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Timestamp header string true "Unix time the event was sent"
+// @Param X-Webhook-Signature header string true "Hex HMAC-SHA256 signature"
+// @Param request body dto.WebhookEventRequest true "Provider event"
+// @Success 200 {object} dto.WebhookSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Invalid signature or timestamp"
+// @Failure 404 {object} errs.ErrorResponse "Payment or booking not found"
+// @Router /api/v1/payments/webhook [post]
+func (h *Handler) Webhook(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.Error(errs.NewValidationError("Invalid webhook body", err))
+		return
+	}
+
+	// The signature covers the raw body, so it is checked before parsing.
+	if err := h.srv.VerifyWebhook(
+		c.GetHeader(HeaderWebhookTimestamp),
+		c.GetHeader(HeaderWebhookSignature),
+		body,
+	); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.WebhookEventRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.Error(errs.NewValidationError("Invalid webhook event", err))
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		c.Error(errs.NewValidationError("Invalid webhook event", err))
+		return
+	}
+
+	result, err := h.srv.ApplyWebhook(ctx, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.WebhookSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Webhook processed successfully",
+		},
+		Result: result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+
+// IssueRefund godoc
+// @Summary Refund a payment
+// @Description Refund part of a successful payment, or what is left of it when no amount is given. Refunds never exceed the amount paid (event organizer or admin only)
+// @Tags Payments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Payment public ID"
+// @Param request body dto.CreateRefundRequest true "Refund data"
+// @Success 201 {object} dto.IssueRefundSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error or amount too large"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Payment not found"
+// @Failure 409 {object} errs.ErrorResponse "Payment can not be refunded"
+// @Router /api/v1/payments/{publicID}/refunds [post]
+func (h *Handler) IssueRefund(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	paymentPublicID := c.Param("publicID")
+
+	var req dto.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid refund data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	issued, err := h.refunds.Issue(ctx, paymentPublicID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.IssueRefundSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Refund issued successfully",
+		},
+		Refund:  issued.Refund,
+		Payment: issued.Payment,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListRefunds godoc
+// @Summary List the refunds of a payment
+// @Description List the refunds of a payment with the total refunded so far (event organizer or admin only)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Payment public ID"
+// @Success 200 {object} dto.PaymentRefundsSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Payment not found"
+// @Router /api/v1/payments/{publicID}/refunds [get]
+func (h *Handler) ListRefunds(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	paymentPublicID := c.Param("publicID")
+
+	refunds, err := h.refunds.List(ctx, paymentPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.PaymentRefundsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Refunds retrieved successfully",
+		},
+		Payment: refunds.Payment,
+		Refunds: refunds.Refunds,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get payment
+// @Description Get a payment (the user who paid, the event organizer or an admin)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Payment public ID"
+// @Success 200 {object} dto.PaymentSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Payment not found"
+// @Router /api/v1/payments/{publicID} [get]
+func (h *Handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	paymentPublicID := c.Param("publicID")
+
+	payment, err := h.srv.Get(ctx, paymentPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.PaymentSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Payment retrieved successfully",
+		},
+		Payment: *payment,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListByBooking godoc
+// @Summary List the payments of a booking
+// @Description List the payments of a booking, newest first (the user who booked, the event organizer or an admin)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Booking public ID"
+// @Success 200 {object} dto.BookingPaymentsSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Booking not found"
+// @Router /api/v1/bookings/{publicID}/payments [get]
+func (h *Handler) ListByBooking(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingPublicID := c.Param("publicID")
+
+	payments, err := h.srv.ListByBooking(ctx, bookingPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.BookingPaymentsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Payments retrieved successfully",
+		},
+		Payments: payments,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List payments
+// @Description List all payments, newest first, by status and by when they were recorded (admin only)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Payment status" Enums(success, failed, refunded, disputed)
+// @Param from query string false "Recorded at or after (RFC 3339)"
+// @Param to query string false "Recorded before (RFC 3339)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} dto.ListPaymentsSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Router /api/v1/payments [get]
+func (h *Handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.ListPaymentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.srv.List(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListPaymentsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Payments retrieved successfully",
+		},
+		PaymentListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListReconciliationReports godoc
+// @Summary List reconciliation reports
+// @Description List payment reconciliation reports, newest first, without their mismatches (admin only)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} dto.ListReconciliationReportsSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Router /api/v1/payments/reconciliations [get]
+func (h *Handler) ListReconciliationReports(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.ListReconciliationReportsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.reconciler.ListReports(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListReconciliationReportsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Reconciliation reports retrieved successfully",
+		},
+		ReconciliationReportListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetReconciliationReport godoc
+// @Summary Get reconciliation report
+// @Description Get a payment reconciliation report with its mismatches (admin only)
+// @Tags Payments
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Report public ID"
+// @Success 200 {object} dto.ReconciliationReportSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Not found"
+// @Router /api/v1/payments/reconciliations/{publicID} [get]
+func (h *Handler) GetReconciliationReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	report, err := h.reconciler.GetReport(ctx, c.Param("publicID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ReconciliationReportSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Reconciliation report retrieved successfully",
+		},
+		Report: *report,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
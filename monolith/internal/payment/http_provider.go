@@ -0,0 +1,173 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// HTTPProvider charges through a payment gateway speaking the JSON API of
+// the mock gateway in cmd/mockgateway. Amounts are sent as integer minor
+// units with their currency.
+type HTTPProvider struct {
+	baseURL string
+	httpClient *http.Client
+}
+
+type gatewayCharge struct {
+	ID string `json:"id"`
+	Status string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type gatewayTransaction struct {
+	ID string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	BookingID string `json:"booking_id"`
+	Amount int64 `json:"amount"`
+	Currency string `json:"currency"`
+	Status string `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type gatewayError struct {
+	Error string `json:"error"`
+}
+
+func NewHTTPProvider(baseURL string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (p *HTTPProvider) Name() string {
+	return ProviderHTTP
+}
+
+func (p *HTTPProvider) Authorize(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	body := map[string]any{
+		"payment_id": req.PaymentID,
+		"booking_id": req.BookingID,
+		"amount": req.Amount.Amount,
+		"currency": req.Amount.Currency,
+	}
+	return p.do(ctx, http.MethodPost, "/v1/authorizations", body)
+}
+
+func (p *HTTPProvider) Capture(ctx context.Context, reference string, amount money.Money) (*ChargeResult, error) {
+	return p.do(ctx, http.MethodPost, "/v1/charges/"+reference+"/capture", map[string]any{
+		"amount": amount.Amount,
+		"currency": amount.Currency,
+	})
+}
+
+func (p *HTTPProvider) Refund(ctx context.Context, reference, refundID string, amount money.Money) (*ChargeResult, error) {
+	body := map[string]any{
+		"refund_id": refundID,
+		"amount": amount.Amount,
+		"currency": amount.Currency,
+	}
+	return p.do(ctx, http.MethodPost, "/v1/charges/"+reference+"/refund", body)
+}
+
+func (p *HTTPProvider) Status(ctx context.Context, reference string) (*ChargeResult, error) {
+	return p.do(ctx, http.MethodGet, "/v1/charges/"+reference, nil)
+}
+
+func (p *HTTPProvider) Transactions(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+	query := url.Values{}
+	query.Set("from", from.UTC().Format(time.RFC3339))
+	query.Set("to", to.UTC().Format(time.RFC3339))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v1/charges?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("payment gateway: create request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var gwErr gatewayError
+		_ = json.NewDecoder(resp.Body).Decode(&gwErr)
+		return nil, fmt.Errorf("%w: list charges: status %d %s", ErrProviderUnavailable, resp.StatusCode, gwErr.Error)
+	}
+
+	var page struct {
+		Charges []gatewayTransaction `json:"charges"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("payment gateway: decode response: %w", err)
+	}
+
+	txs := make([]Transaction, 0, len(page.Charges))
+	for _, c := range page.Charges {
+		txs = append(txs, Transaction{
+			Reference: c.ID,
+			PaymentID: c.PaymentID,
+			BookingID: c.BookingID,
+			Amount: money.New(c.Amount, money.Currency(c.Currency)),
+			Status: c.Status,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return txs, nil
+}
+
+func (p *HTTPProvider) do(ctx context.Context, method, path string, body any) (*ChargeResult, error) {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return nil, fmt.Errorf("payment gateway: encode request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("payment gateway: create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrChargeNotFound
+	case resp.StatusCode == http.StatusConflict:
+		return nil, ErrChargeState
+	case resp.StatusCode >= 300:
+		var gwErr gatewayError
+		_ = json.NewDecoder(resp.Body).Decode(&gwErr)
+		return nil, fmt.Errorf("%w: %s %s: status %d %s", ErrProviderUnavailable, method, path, resp.StatusCode, gwErr.Error)
+	}
+
+	var charge gatewayCharge
+	if err := json.NewDecoder(resp.Body).Decode(&charge); err != nil {
+		return nil, fmt.Errorf("payment gateway: decode response: %w", err)
+	}
+
+	return &ChargeResult{
+		Reference: charge.ID,
+		Status: charge.Status,
+		Message: charge.Message,
+	}, nil
+}
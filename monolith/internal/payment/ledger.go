@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/google/uuid"
+)
+
+// ledger keeps the charges of the in-process providers in memory.
+type ledger struct {
+	mu sync.Mutex
+	charges map[string]*ledgerCharge
+	byPayment map[string]string
+}
+
+type ledgerCharge struct {
+	paymentID string
+	bookingID string
+	status string
+	amount money.Money
+	refunded money.Money
+	refunds map[string]struct{}
+	createdAt time.Time
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		charges: map[string]*ledgerCharge{},
+		byPayment: map[string]string{},
+	}
+}
+
+// open starts a charge for the payment in req. A payment that was seen
+// before gets its existing charge back as it stands, so a worker retrying a
+// job whose outcome it failed to record does not charge the customer twice.
+func (l *ledger) open(req ChargeRequest, status string) *ChargeResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if reference, ok := l.byPayment[req.PaymentID]; ok {
+		return &ChargeResult{Reference: reference, Status: l.charges[reference].status}
+	}
+
+	reference := uuid.NewString()
+	l.byPayment[req.PaymentID] = reference
+	l.charges[reference] = &ledgerCharge{
+		paymentID: req.PaymentID,
+		bookingID: req.BookingID,
+		status: status,
+		amount: req.Amount,
+		refunded: money.New(0, req.Amount.Currency),
+		refunds: map[string]struct{}{},
+		createdAt: time.Now(),
+	}
+	return &ChargeResult{Reference: reference, Status: status}
+}
+
+// move changes a charge from status from to status to.
+func (l *ledger) move(reference, from, to string) (*ChargeResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := l.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if c.status != from {
+		return nil, ErrChargeState
+	}
+	c.status = to
+	return &ChargeResult{Reference: reference, Status: to}, nil
+}
+
+// refund gives back amount of a captured charge. A refundID that was seen
+// before is answered with the current state of the charge.
+func (l *ledger) refund(reference, refundID string, amount money.Money) (*ChargeResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := l.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if _, seen := c.refunds[refundID]; seen {
+		return &ChargeResult{Reference: reference, Status: c.status}, nil
+	}
+	if c.status != ChargeCaptured && c.status != ChargePartiallyRefunded {
+		return nil, ErrChargeState
+	}
+
+	if !amount.IsPositive() {
+		return nil, ErrChargeState
+	}
+	refunded, err := c.refunded.Add(amount)
+	if err != nil || refunded.Amount > c.amount.Amount {
+		return nil, ErrChargeState
+	}
+	c.refunded = refunded
+	c.refunds[refundID] = struct{}{}
+
+	c.status = ChargePartiallyRefunded
+	if c.refunded.Amount == c.amount.Amount {
+		c.status = ChargeRefunded
+	}
+	return &ChargeResult{Reference: reference, Status: c.status}, nil
+}
+
+func (l *ledger) status(reference string) (*ChargeResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := l.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	return &ChargeResult{Reference: reference, Status: c.status}, nil
+}
+
+
+// transactions returns the charges opened in [from, to), oldest first.
+func (l *ledger) transactions(from, to time.Time) []Transaction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var txs []Transaction
+	for reference, c := range l.charges {
+		if c.createdAt.Before(from) || !c.createdAt.Before(to) {
+			continue
+		}
+		txs = append(txs, Transaction{
+			Reference: reference,
+			PaymentID: c.paymentID,
+			BookingID: c.bookingID,
+			Amount: c.amount,
+			Status: c.status,
+			CreatedAt: c.createdAt,
+		})
+	}
+	slices.SortFunc(txs, func(a, b Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return txs
+}
\ No newline at end of file
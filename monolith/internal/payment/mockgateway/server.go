@@ -0,0 +1,242 @@
+// Package mockgateway is a small in-memory payment gateway for local
+// development and tests. Charges are authorized, captured and refunded over
+// a JSON API. Amounts are integer minor units with an ISO 4217 currency;
+// outcomes are scripted through the last two digits of the amount.
+package mockgateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Amounts ending in these two digits are declined or answered with a 503.
+const (
+	DeclineCents = 51
+	UnavailableCents = 52
+)
+
+const (
+	statusAuthorized = "authorized"
+	statusCaptured = "captured"
+	statusDeclined = "declined"
+	statusPartiallyRefunded = "partially_refunded"
+	statusRefunded = "refunded"
+)
+
+type charge struct {
+	ID string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	BookingID string `json:"booking_id,omitempty"`
+	Amount int64 `json:"amount"`
+	Refunded int64 `json:"refunded"`
+	Currency string `json:"currency"`
+	Status string `json:"status"`
+	Message string `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	refunds map[string]struct{}
+}
+
+type authorizeRequest struct {
+	PaymentID string `json:"payment_id"`
+	BookingID string `json:"booking_id"`
+	Amount int64 `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type refundRequest struct {
+	RefundID string `json:"refund_id"`
+	Amount int64 `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type Server struct {
+	mu sync.Mutex
+	charges map[string]*charge
+	byPayment map[string]*charge
+	mux *http.ServeMux
+}
+
+func NewServer() *Server {
+	s := &Server{
+		charges: map[string]*charge{},
+		byPayment: map[string]*charge{},
+		mux: http.NewServeMux(),
+	}
+	s.mux.HandleFunc("POST /v1/authorizations", s.authorize)
+	s.mux.HandleFunc("POST /v1/charges/{id}/capture", s.transition(statusAuthorized, statusCaptured))
+	s.mux.HandleFunc("POST /v1/charges/{id}/refund", s.refund)
+	s.mux.HandleFunc("GET /v1/charges", s.list)
+	s.mux.HandleFunc("GET /v1/charges/{id}", s.get)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorize opens a charge. A payment ID that was seen before gets its
+// existing charge back, so retried requests do not charge twice.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	var req authorizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PaymentID == "" || req.Amount <= 0 || req.Currency == "" {
+		writeError(w, http.StatusBadRequest, "payment_id, a positive amount and a currency are required")
+		return
+	}
+
+	cents := req.Amount % 100
+	if cents == UnavailableCents {
+		writeError(w, http.StatusServiceUnavailable, "gateway temporarily unavailable")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.byPayment[req.PaymentID]; ok {
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+
+	c := &charge{
+		ID: uuid.NewString(),
+		PaymentID: req.PaymentID,
+		BookingID: req.BookingID,
+		Amount: req.Amount,
+		Currency: req.Currency,
+		Status: statusAuthorized,
+		CreatedAt: time.Now().UTC(),
+		refunds: map[string]struct{}{},
+	}
+	if cents == DeclineCents {
+		c.Status = statusDeclined
+		c.Message = "card declined"
+	}
+	s.charges[c.ID] = c
+	s.byPayment[c.PaymentID] = c
+
+	writeJSON(w, http.StatusCreated, c)
+}
+
+func (s *Server) transition(from, to string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		c, ok := s.charges[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "charge not found")
+			return
+		}
+		if c.Status == to {
+			writeJSON(w, http.StatusOK, c)
+			return
+		}
+		if c.Status != from {
+			writeError(w, http.StatusConflict, "charge is "+c.Status)
+			return
+		}
+		c.Status = to
+		writeJSON(w, http.StatusOK, c)
+	}
+}
+
+// refund gives back part or all of a captured charge. A refund ID that was
+// seen before gets the charge back without refunding again.
+func (s *Server) refund(w http.ResponseWriter, r *http.Request) {
+	var req refundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefundID == "" || req.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, "refund_id and a positive amount are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.charges[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "charge not found")
+		return
+	}
+	if _, seen := c.refunds[req.RefundID]; seen {
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+	if c.Status != statusCaptured && c.Status != statusPartiallyRefunded {
+		writeError(w, http.StatusConflict, "charge is "+c.Status)
+		return
+	}
+	if req.Currency != c.Currency {
+		writeError(w, http.StatusConflict, "refund currency does not match the charge")
+		return
+	}
+
+	refunded := c.Refunded + req.Amount
+	if refunded > c.Amount {
+		writeError(w, http.StatusConflict, "refund exceeds the captured amount")
+		return
+	}
+	c.Refunded = refunded
+	c.refunds[req.RefundID] = struct{}{}
+
+	c.Status = statusPartiallyRefunded
+	if refunded == c.Amount {
+		c.Status = statusRefunded
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.charges[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "charge not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// list returns the charges created in [from, to), oldest first. Both bounds
+// are RFC 3339 times.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "from must be an RFC 3339 time")
+		return
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "to must be an RFC 3339 time")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charges := []*charge{}
+	for _, c := range s.charges {
+		if !c.CreatedAt.Before(from) && c.CreatedAt.Before(to) {
+			charges = append(charges, c)
+		}
+	}
+	slices.SortFunc(charges, func(a, b *charge) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"charges": charges})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
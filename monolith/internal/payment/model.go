@@ -1,12 +1,33 @@
 package payment
 
-import "time"
+import (
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailed = "failed"
+	StatusRefunded = "refunded"
+	StatusDisputed = "disputed"
+)
+
+// What a failed payment does to its booking, see the payment failure
+// policy in the config.
+const (
+	FailurePolicyFail = "fail"
+	FailurePolicyRetry = "retry"
+)
 
 type Payment struct {
 	ID uint `gorm:"primarykey"`
 	PublicID 	string `gorm:"column:public_id;type:char(36);uniqueIndex;not null"`
-	Amount float32 `gorm:"column:amount;not null"`
-	Status string `gorm:"column:status;type:ENUM('success', 'failed');not null"`
+	Amount money.Money `gorm:"column:amount;not null"`
+	Currency money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Status string `gorm:"column:status;type:ENUM('success', 'failed', 'refunded', 'disputed');not null"`
+	Provider string `gorm:"column:provider;size:32;not null"`
+	ProviderRef *string `gorm:"column:provider_ref;size:64"`
 	BookingID uint `gorm:"column:booking_id;not null"`
 	UserID uint `gorm:"column:user_id;not null"`
 	CreatedAt time.Time
@@ -15,4 +36,127 @@ type Payment struct {
 
 func (p *Payment) TableName() string {
 	return "payments"
-}
\ No newline at end of file
+}
+
+// WebhookEvent is a provider event that has been processed. Its unique
+// EventID makes redelivered events a no-op.
+type WebhookEvent struct {
+	ID uint `gorm:"primarykey"`
+	EventID string `gorm:"column:event_id;size:64;uniqueIndex;not null"`
+	Provider string `gorm:"column:provider;size:32;not null"`
+	Type string `gorm:"column:type;size:32;not null"`
+	PaymentID *uint `gorm:"column:payment_id"`
+	Result string `gorm:"column:result;type:ENUM('applied', 'ignored');not null"`
+	CreatedAt time.Time
+}
+
+func (e *WebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
+
+const (
+	JobQueued = "queued"
+	JobRunning = "running"
+	JobDone = "done"
+	JobDead = "dead"
+)
+
+// PaymentJob is a payment waiting to be charged. A running job whose lease
+// (AvailableAt) has passed is handed to the next worker; Attempts doubles as
+// the lease token so a worker that lost its lease can not finish the job.
+type PaymentJob struct {
+	ID uint `gorm:"primarykey"`
+	PublicID string `gorm:"column:payment_public_id;type:char(36);uniqueIndex;not null"`
+	BookingID uint `gorm:"column:booking_id;not null"`
+	BookingPublicID string `gorm:"column:booking_public_id;type:char(36);not null"`
+	UserID uint `gorm:"column:user_id;not null"`
+	Amount money.Money `gorm:"column:amount;not null"`
+	Currency money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Status string `gorm:"column:status;type:ENUM('queued', 'running', 'done', 'dead');not null"`
+	Attempts int `gorm:"column:attempts;not null"`
+	AvailableAt time.Time `gorm:"column:available_at;not null"`
+	LastError *string `gorm:"column:last_error;size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (j *PaymentJob) TableName() string {
+	return "payment_jobs"
+}
+
+
+const (
+	RefundRequested = "requested"
+	RefundSucceeded = "succeeded"
+	RefundFailed = "failed"
+)
+
+// Refund is money given back on a successful payment. A payment can have
+// several partial refunds; together the requested and succeeded ones never
+// exceed its amount. RequestedBy is nil for refunds requested by a booking
+// cancellation.
+type Refund struct {
+	ID uint `gorm:"primarykey"`
+	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex;not null"`
+	PaymentID uint `gorm:"column:payment_id;not null"`
+	BookingID uint `gorm:"column:booking_id;not null"`
+	UserID uint `gorm:"column:user_id;not null"`
+	Amount money.Money `gorm:"column:amount;not null"`
+	Currency money.Currency `gorm:"column:currency;type:char(3);not null"`
+	Status string `gorm:"column:status;type:ENUM('requested', 'succeeded', 'failed');not null"`
+	Reason *string `gorm:"column:reason;size:255"`
+	FailureMessage *string `gorm:"column:failure_message;size:255"`
+	RequestedBy *uint `gorm:"column:requested_by"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Refund) TableName() string {
+	return "refunds"
+}
+
+// Kinds of mismatch a reconciliation can find.
+const (
+	MismatchMissingLocally = "missing_locally"
+	MismatchAmount = "amount_mismatch"
+	MismatchStatus = "status_mismatch"
+	MismatchUnpaidBooking = "confirmed_without_payment"
+)
+
+// ReconciliationReport is one comparison of the provider's charges opened
+// in [WindowFrom, WindowTo) with the payments recorded for them. Checked is
+// the number of charges the provider listed.
+type ReconciliationReport struct {
+	ID uint `gorm:"primarykey"`
+	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex;not null"`
+	Provider string `gorm:"column:provider;size:32;not null"`
+	WindowFrom time.Time `gorm:"column:window_from;not null"`
+	WindowTo time.Time `gorm:"column:window_to;not null"`
+	Checked int `gorm:"column:checked;not null"`
+	MismatchCount int `gorm:"column:mismatch_count;not null"`
+	Mismatches []ReconciliationMismatch `gorm:"foreignKey:ReportID"`
+	CreatedAt time.Time
+}
+
+func (r *ReconciliationReport) TableName() string {
+	return "reconciliation_reports"
+}
+
+// ReconciliationMismatch is a difference between the provider's record of
+// a charge and ours. ProviderValue and LocalValue hold the status or amount
+// each side has, and are nil when that side has no record at all.
+type ReconciliationMismatch struct {
+	ID uint `gorm:"primarykey"`
+	ReportID uint `gorm:"column:report_id;not null"`
+	Kind string `gorm:"column:kind;type:ENUM('missing_locally', 'amount_mismatch', 'status_mismatch', 'confirmed_without_payment');not null"`
+	ProviderRef *string `gorm:"column:provider_ref;size:64"`
+	PaymentPublicID *string `gorm:"column:payment_public_id;type:char(36)"`
+	BookingPublicID *string `gorm:"column:booking_public_id;type:char(36)"`
+	ProviderValue *string `gorm:"column:provider_value;size:64"`
+	LocalValue *string `gorm:"column:local_value;size:64"`
+	CreatedAt time.Time
+}
+
+func (m *ReconciliationMismatch) TableName() string {
+	return "reconciliation_mismatches"
+}
@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+const (
+	ProviderSimulator = "simulator"
+	ProviderFake = "fake"
+	ProviderHTTP = "http"
+)
+
+// Statuses a provider reports for a charge.
+const (
+	ChargeAuthorized = "authorized"
+	ChargeCaptured = "captured"
+	ChargeDeclined = "declined"
+	ChargePartiallyRefunded = "partially_refunded"
+	ChargeRefunded = "refunded"
+)
+
+// PaymentProvider is the processor booking payments are charged through.
+// A charge is first authorized and then captured; Reference is the
+// provider's ID of the charge and is what Capture, Refund and Status take.
+// Refund gives back part or all of a captured charge; refundID is our public
+// ID of the refund, so a retried refund is not paid out twice. Transactions
+// lists the charges opened in [from, to) for reconciliation.
+type PaymentProvider interface {
+	Name() string
+	Authorize(ctx context.Context, req ChargeRequest) (*ChargeResult, error)
+	Capture(ctx context.Context, reference string, amount money.Money) (*ChargeResult, error)
+	Refund(ctx context.Context, reference, refundID string, amount money.Money) (*ChargeResult, error)
+	Status(ctx context.Context, reference string) (*ChargeResult, error)
+	Transactions(ctx context.Context, from, to time.Time) ([]Transaction, error)
+}
+
+// ChargeRequest asks a provider to authorize an amount. PaymentID and
+// BookingID are our public IDs, passed along so the provider can deduplicate
+// and echo them back in webhooks.
+type ChargeRequest struct {
+	PaymentID string
+	BookingID string
+	Amount money.Money
+}
+
+type ChargeResult struct {
+	Reference string
+	Status string
+	Message string
+}
+
+// Transaction is a charge as the provider has it on record. PaymentID and
+// BookingID are the public IDs the charge was authorized with.
+type Transaction struct {
+	Reference string
+	PaymentID string
+	BookingID string
+	Amount money.Money
+	Status string
+	CreatedAt time.Time
+}
+
+// NewProvider returns the provider configured in cfg.Payment.Provider.
+func NewProvider(cfg *config.AppConfig) (PaymentProvider, error) {
+	switch cfg.Payment.Provider {
+	case ProviderSimulator:
+		return NewSimulatorProvider(), nil
+	case ProviderFake:
+		return NewFakeProvider(cfg.Payment.FakeOutcome), nil
+	case ProviderHTTP:
+		return NewHTTPProvider(cfg.Payment.GatewayURL, cfg.Payment.GatewayTimeout), nil
+	default:
+		return nil, fmt.Errorf("payment: unknown provider %q", cfg.Payment.Provider)
+	}
+}
+
+// cents returns the last two digits of an amount in minor units, which the
+// fake provider and the mock gateway use to script outcomes.
+func cents(amount money.Money) int {
+	return int(amount.Amount % 100)
+}
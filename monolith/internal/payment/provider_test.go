@@ -0,0 +1,128 @@
+package payment
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/internal/payment/mockgateway"
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider_ScriptedOutcomes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		outcome    string
+		amount     money.Money
+		wantStatus string
+		wantErr    error
+	}{
+		{"approve by config", FakeApprove, idr(10000), ChargeCaptured, nil},
+		{"decline by config", FakeDecline, idr(10000), ChargeDeclined, nil},
+		{"error by config", FakeError, idr(10000), "", ErrProviderUnavailable},
+		{"decline by magic amount", FakeApprove, idr(1051), ChargeDeclined, nil},
+		{"error by magic amount", FakeApprove, idr(1052), "", ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewFakeProvider(tt.outcome)
+
+			res, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-1", Amount: tt.amount})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, res.Status)
+
+			status, err := p.Status(ctx, res.Reference)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, status.Status)
+		})
+	}
+}
+
+func TestPartialRefunds(t *testing.T) {
+	ctx := context.Background()
+	gw := httptest.NewServer(mockgateway.NewServer())
+	defer gw.Close()
+
+	providers := map[string]PaymentProvider{
+		"fake": NewFakeProvider(FakeApprove),
+		"http": NewHTTPProvider(gw.URL, 5*time.Second),
+	}
+
+	for name, p := range providers {
+		t.Run(name, func(t *testing.T) {
+			charge, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-" + name, Amount: idr(5000)})
+			require.NoError(t, err)
+
+			res, err := p.Refund(ctx, charge.Reference, "refund-1", idr(2000))
+			require.NoError(t, err)
+			assert.Equal(t, ChargePartiallyRefunded, res.Status)
+
+			// A retried refund is not paid out twice.
+			res, err = p.Refund(ctx, charge.Reference, "refund-1", idr(2000))
+			require.NoError(t, err)
+			assert.Equal(t, ChargePartiallyRefunded, res.Status)
+
+			_, err = p.Refund(ctx, charge.Reference, "refund-2", idr(3001))
+			assert.ErrorIs(t, err, ErrChargeState)
+
+			res, err = p.Refund(ctx, charge.Reference, "refund-3", idr(3000))
+			require.NoError(t, err)
+			assert.Equal(t, ChargeRefunded, res.Status)
+
+			_, err = p.Refund(ctx, charge.Reference, "refund-4", idr(100))
+			assert.ErrorIs(t, err, ErrChargeState)
+		})
+	}
+}
+
+func TestHTTPProvider_AgainstMockGateway(t *testing.T) {
+	ctx := context.Background()
+	gw := httptest.NewServer(mockgateway.NewServer())
+	defer gw.Close()
+
+	p := NewHTTPProvider(gw.URL, 5*time.Second)
+
+	res, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-1", Amount: idr(2500)})
+	require.NoError(t, err)
+	assert.Equal(t, ChargeCaptured, res.Status)
+
+	refunded, err := p.Refund(ctx, res.Reference, "refund-1", idr(2500))
+	require.NoError(t, err)
+	assert.Equal(t, ChargeRefunded, refunded.Status)
+
+	status, err := p.Status(ctx, res.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, ChargeRefunded, status.Status)
+
+	_, err = p.Capture(ctx, res.Reference, idr(2500))
+	assert.ErrorIs(t, err, ErrChargeState)
+
+	_, err = p.Status(ctx, "missing")
+	assert.ErrorIs(t, err, ErrChargeNotFound)
+
+	declined, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-2", Amount: idr(1051)})
+	require.NoError(t, err)
+	assert.Equal(t, ChargeDeclined, declined.Status)
+
+	_, err = chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-3", Amount: idr(1052)})
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+
+	// Authorizing the same payment twice returns the first charge.
+	again, err := p.Authorize(ctx, ChargeRequest{PaymentID: "payment-1", Amount: idr(2500)})
+	require.NoError(t, err)
+	assert.Equal(t, res.Reference, again.Reference)
+}
+
+func idr(minor int64) money.Money {
+	return money.New(minor, money.DefaultCurrency)
+}
@@ -0,0 +1,272 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	paymentDTO "github.com/anrisys/quicket/internal/payment/dto"
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+// expectedStatuses are the payment statuses that agree with a charge
+// status. An authorized charge is captured before its payment is recorded,
+// so no payment agrees with it.
+var expectedStatuses = map[string][]string{
+	ChargeAuthorized: {},
+	ChargeCaptured: {StatusSuccess, StatusDisputed},
+	ChargePartiallyRefunded: {StatusSuccess, StatusDisputed},
+	ChargeRefunded: {StatusRefunded},
+	ChargeDeclined: {StatusFailed},
+}
+
+type ReconciliationServiceInterface interface {
+	ListReports(ctx context.Context, query *paymentDTO.ListReconciliationReportsQuery) (*paymentDTO.ReconciliationReportListDTO, error)
+	GetReport(ctx context.Context, publicID string) (*paymentDTO.ReconciliationReportDTO, error)
+}
+
+// ReconciliationService compares the charges the payment provider has on
+// record with our payments and stores what does not add up. Run reconciles
+// the previous day once a day; Reconcile can be called for any window.
+type ReconciliationService struct {
+	r Repository
+	provider PaymentProvider
+	delay time.Duration
+	logger zerolog.Logger
+}
+
+func NewReconciliationService(r *GormRepository, provider PaymentProvider, cfg *config.AppConfig, logger zerolog.Logger) *ReconciliationService {
+	return &ReconciliationService{
+		r: r,
+		provider: provider,
+		delay: cfg.Payment.ReconcileDelay,
+		logger: logger,
+	}
+}
+
+// Run reconciles the previous day every day, delay after midnight, until
+// ctx is cancelled.
+func (s *ReconciliationService) Run(ctx context.Context) {
+	s.logger.Info().Dur("delay", s.delay).Msg("payment reconciler started")
+
+	for {
+		next := nextReconcileAt(time.Now(), s.delay)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info().Msg("payment reconciler stopped")
+			return
+		case <-timer.C:
+			from, to := ReconcileWindow(next)
+			if _, err := s.Reconcile(ctx, from, to); err != nil {
+				s.logger.Error().Err(err).
+					Time("from", from).
+					Time("to", to).
+					Msg("payment reconciliation failed")
+			}
+		}
+	}
+}
+
+// Reconcile compares the charges opened at the provider in [from, to) with
+// the payments recorded for them, and the bookings confirmed in the same
+// window with their payments. The report is stored and returned.
+func (s *ReconciliationService) Reconcile(ctx context.Context, from, to time.Time) (*ReconciliationReport, error) {
+	txs, err := s.provider.Transactions(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile#transactions: %w", err)
+	}
+
+	refs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		refs = append(refs, tx.Reference)
+	}
+	payments, err := s.r.FindPaymentsByProviderRefs(ctx, s.provider.Name(), refs)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile#payments: %w", err)
+	}
+	byRef := make(map[string]*Payment, len(payments))
+	for i := range payments {
+		byRef[*payments[i].ProviderRef] = &payments[i]
+	}
+
+	mismatches := compareTransactions(txs, byRef)
+
+	unpaid, err := s.r.FindUnpaidConfirmedBookings(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile#bookings: %w", err)
+	}
+	for _, bookingPublicID := range unpaid {
+		mismatches = append(mismatches, ReconciliationMismatch{
+			Kind: MismatchUnpaidBooking,
+			BookingPublicID: &bookingPublicID,
+		})
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile#generate public id: %w", err)
+	}
+	report := &ReconciliationReport{
+		PublicID: publicID,
+		Provider: s.provider.Name(),
+		WindowFrom: from,
+		WindowTo: to,
+		Checked: len(txs),
+		MismatchCount: len(mismatches),
+		Mismatches: mismatches,
+	}
+	if err := s.r.CreateReconciliationReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("reconcile#store: %w", err)
+	}
+
+	log := s.logger.Info()
+	if len(mismatches) > 0 {
+		log = s.logger.Warn()
+	}
+	log.Str("report_public_id", report.PublicID).
+		Str("provider", report.Provider).
+		Time("from", from).
+		Time("to", to).
+		Int("checked", report.Checked).
+		Int("mismatches", report.MismatchCount).
+		Msg("payment reconciliation finished")
+
+	return report, nil
+}
+
+func (s *ReconciliationService) ListReports(ctx context.Context, query *paymentDTO.ListReconciliationReportsQuery) (*paymentDTO.ReconciliationReportListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	// One extra row tells whether there is a next page.
+	reports, err := s.r.ListReconciliationReports(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile#list: %w", err)
+	}
+
+	list := &paymentDTO.ReconciliationReportListDTO{
+		Reports: make([]paymentDTO.ReconciliationReportDTO, 0, limit),
+	}
+	if len(reports) > limit {
+		reports = reports[:limit]
+		list.NextCursor = util.EncodeCursor(reports[limit-1].ID)
+	}
+	for i := range reports {
+		list.Reports = append(list.Reports, toReconciliationReportDTO(&reports[i]))
+	}
+	return list, nil
+}
+
+func (s *ReconciliationService) GetReport(ctx context.Context, publicID string) (*paymentDTO.ReconciliationReportDTO, error) {
+	report, err := s.r.FindReconciliationReport(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, ErrReportNotFound) {
+			return nil, errs.NewErrNotFound("reconciliation report")
+		}
+		return nil, fmt.Errorf("reconcile#get: %w", err)
+	}
+
+	res := toReconciliationReportDTO(report)
+	res.Mismatches = make([]paymentDTO.ReconciliationMismatchDTO, 0, len(report.Mismatches))
+	for _, m := range report.Mismatches {
+		res.Mismatches = append(res.Mismatches, paymentDTO.ReconciliationMismatchDTO{
+			Kind: m.Kind,
+			ProviderRef: m.ProviderRef,
+			PaymentID: m.PaymentPublicID,
+			BookingID: m.BookingPublicID,
+			ProviderValue: m.ProviderValue,
+			LocalValue: m.LocalValue,
+		})
+	}
+	return &res, nil
+}
+
+// ReconcileWindow returns the day before the day of t, in the location of t.
+func ReconcileWindow(t time.Time) (time.Time, time.Time) {
+	to := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return to.AddDate(0, 0, -1), to
+}
+
+// nextReconcileAt is the first time after now that is delay past midnight.
+func nextReconcileAt(now time.Time, delay time.Duration) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(delay)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// compareTransactions checks every transaction against the payment recorded
+// for its reference in payments. A transaction can be off in both amount
+// and status, and then has a mismatch for each.
+func compareTransactions(txs []Transaction, payments map[string]*Payment) []ReconciliationMismatch {
+	var mismatches []ReconciliationMismatch
+	for _, tx := range txs {
+		base := ReconciliationMismatch{
+			ProviderRef: optional(tx.Reference),
+			PaymentPublicID: optional(tx.PaymentID),
+			BookingPublicID: optional(tx.BookingID),
+		}
+
+		p, ok := payments[tx.Reference]
+		if !ok {
+			m := base
+			m.Kind = MismatchMissingLocally
+			m.ProviderValue = optional(tx.Status)
+			mismatches = append(mismatches, m)
+			continue
+		}
+		base.PaymentPublicID = optional(p.PublicID)
+
+		if p.Amount != tx.Amount {
+			m := base
+			m.Kind = MismatchAmount
+			m.ProviderValue = optional(tx.Amount.String())
+			m.LocalValue = optional(p.Amount.String())
+			mismatches = append(mismatches, m)
+		}
+		if !slices.Contains(expectedStatuses[tx.Status], p.Status) {
+			m := base
+			m.Kind = MismatchStatus
+			m.ProviderValue = optional(tx.Status)
+			m.LocalValue = optional(p.Status)
+			mismatches = append(mismatches, m)
+		}
+	}
+	return mismatches
+}
+
+// optional returns nil for an empty s.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func toReconciliationReportDTO(r *ReconciliationReport) paymentDTO.ReconciliationReportDTO {
+	return paymentDTO.ReconciliationReportDTO{
+		PublicID: r.PublicID,
+		Provider: r.Provider,
+		From: r.WindowFrom,
+		To: r.WindowTo,
+		Checked: r.Checked,
+		MismatchCount: r.MismatchCount,
+		CreatedAt: r.CreatedAt,
+	}
+}
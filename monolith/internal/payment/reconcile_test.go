@@ -0,0 +1,124 @@
+package payment
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/internal/payment/mockgateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactions(t *testing.T) {
+	ctx := context.Background()
+	gw := httptest.NewServer(mockgateway.NewServer())
+	defer gw.Close()
+
+	providers := map[string]PaymentProvider{
+		"fake": NewFakeProvider(FakeApprove),
+		"http": NewHTTPProvider(gw.URL, 5*time.Second),
+	}
+
+	for name, p := range providers {
+		t.Run(name, func(t *testing.T) {
+			from := time.Now().Add(-time.Second)
+
+			paid, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-1", BookingPublicID: "booking-1", Amount: idr(5000)})
+			require.NoError(t, err)
+			declined, err := chargeBooking(ctx, p, &PaymentJob{PublicID: "payment-2", BookingPublicID: "booking-2", Amount: idr(1051)})
+			require.NoError(t, err)
+
+			txs, err := p.Transactions(ctx, from, time.Now().Add(time.Second))
+			require.NoError(t, err)
+			require.Len(t, txs, 2)
+
+			byRef := map[string]Transaction{}
+			for _, tx := range txs {
+				byRef[tx.Reference] = tx
+			}
+			assert.Equal(t, "payment-1", byRef[paid.Reference].PaymentID)
+			assert.Equal(t, "booking-1", byRef[paid.Reference].BookingID)
+			assert.Equal(t, idr(5000), byRef[paid.Reference].Amount)
+			assert.Equal(t, ChargeCaptured, byRef[paid.Reference].Status)
+			assert.Equal(t, ChargeDeclined, byRef[declined.Reference].Status)
+
+			txs, err = p.Transactions(ctx, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+			require.NoError(t, err)
+			assert.Empty(t, txs)
+		})
+	}
+}
+
+func TestCompareTransactions(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(FakeApprove)
+	from := time.Now().Add(-time.Second)
+
+	charge := func(paymentID string, amount int64) string {
+		res, err := chargeBooking(ctx, p, &PaymentJob{PublicID: paymentID, BookingPublicID: "booking-" + paymentID, Amount: idr(amount)})
+		require.NoError(t, err)
+		return res.Reference
+	}
+	matched := charge("matched", 5000)
+	declined := charge("declined", 1051)
+	missing := charge("missing", 5000)
+	wrongAmount := charge("wrong-amount", 5000)
+	wrongStatus := charge("wrong-status", 5000)
+	refunded := charge("refunded", 5000)
+	_, err := p.Refund(ctx, refunded, "refund-1", idr(5000))
+	require.NoError(t, err)
+
+	payment := func(publicID, ref string, amount int64, status string) *Payment {
+		return &Payment{PublicID: publicID, ProviderRef: &ref, Amount: idr(amount), Status: status}
+	}
+	payments := map[string]*Payment{
+		matched: payment("matched", matched, 5000, StatusSuccess),
+		declined: payment("declined", declined, 1051, StatusFailed),
+		wrongAmount: payment("wrong-amount", wrongAmount, 4000, StatusSuccess),
+		wrongStatus: payment("wrong-status", wrongStatus, 5000, StatusFailed),
+		refunded: payment("refunded", refunded, 4000, StatusSuccess),
+	}
+
+	txs, err := p.Transactions(ctx, from, time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	type found struct {
+		kind string
+		ref string
+		provider string
+		local string
+	}
+	var got []found
+	for _, m := range compareTransactions(txs, payments) {
+		got = append(got, found{m.Kind, *m.ProviderRef, value(m.ProviderValue), value(m.LocalValue)})
+	}
+
+	assert.ElementsMatch(t, []found{
+		{MismatchMissingLocally, missing, ChargeCaptured, ""},
+		{MismatchAmount, wrongAmount, "50.00 IDR", "40.00 IDR"},
+		{MismatchStatus, wrongStatus, ChargeCaptured, StatusFailed},
+		{MismatchAmount, refunded, "50.00 IDR", "40.00 IDR"},
+		{MismatchStatus, refunded, ChargeRefunded, StatusSuccess},
+	}, got)
+}
+
+func TestReconcileSchedule(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 3, 10, 14, 30, 0, 0, loc)
+
+	from, to := ReconcileWindow(now)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, loc), from)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, loc), to)
+
+	assert.Equal(t, time.Date(2026, 3, 11, 1, 0, 0, 0, loc), nextReconcileAt(now, time.Hour))
+	assert.Equal(t, time.Date(2026, 3, 10, 15, 0, 0, 0, loc), nextReconcileAt(now, 15*time.Hour))
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,223 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonDTO "github.com/anrisys/quicket/internal/dto"
+	paymentDTO "github.com/anrisys/quicket/internal/payment/dto"
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+// refundRetryBatch is the most refunds retried on one tick of Run.
+const refundRetryBatch = 50
+
+type RefundServiceInterface interface {
+	Issue(ctx context.Context, paymentPublicID string, req *paymentDTO.CreateRefundRequest, userPublicID string) (*paymentDTO.IssueRefundDTO, error)
+	List(ctx context.Context, paymentPublicID, userPublicID string) (*paymentDTO.PaymentRefundsDTO, error)
+}
+
+// RefundService gives money back on successful payments through the
+// payment provider. Refunds the provider could not be reached for, the ones
+// requested by booking cancellations, and those of charges captured for a
+// booking that was no longer pending, are sent by Run.
+type RefundService struct {
+	r Repository
+	provider PaymentProvider
+	users types.UserReader
+	retryInterval time.Duration
+	logger zerolog.Logger
+}
+
+func NewRefundService(r *GormRepository, provider PaymentProvider, users types.UserReader, cfg *config.AppConfig, logger zerolog.Logger) *RefundService {
+	return &RefundService{
+		r: r,
+		provider: provider,
+		users: users,
+		retryInterval: cfg.Payment.RefundRetryInterval,
+		logger: logger,
+	}
+}
+
+// Issue refunds part or all of a payment. Admins can refund any payment,
+// organizers only the payments of their own events.
+func (s *RefundService) Issue(ctx context.Context, paymentPublicID string, req *paymentDTO.CreateRefundRequest, userPublicID string) (*paymentDTO.IssueRefundDTO, error) {
+	if req.Amount != nil && !req.Amount.IsPositive() {
+		return nil, errs.NewValidationError("refund amount must be positive")
+	}
+
+	usr, _, err := s.authorize(ctx, paymentPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("refund#issue: generate public id: %w", err)
+	}
+
+	requestedBy := uint(usr.ID)
+	rf := &Refund{
+		PublicID: publicID,
+		RequestedBy: &requestedBy,
+	}
+	if req.Amount != nil {
+		rf.Amount = *req.Amount
+	}
+	if req.Reason != "" {
+		rf.Reason = &req.Reason
+	}
+	if err := s.r.CreateRefund(ctx, paymentPublicID, rf); err != nil {
+		return nil, s.mapError(err)
+	}
+
+	processed, err := s.r.ProcessRefund(ctx, rf.ID, s.send)
+	if err != nil {
+		// Left requested; Run sends it again.
+		s.logger.Warn().Err(err).
+			Str("refund_public_id", rf.PublicID).
+			Msg("refund could not be sent to the provider")
+	} else {
+		rf = processed
+	}
+
+	s.logger.Info().
+		Str("refund_public_id", rf.PublicID).
+		Str("payment_public_id", paymentPublicID).
+		Stringer("amount", rf.Amount).
+		Str("status", rf.Status).
+		Msg("Refund issued")
+
+	view, err := s.r.FindPaymentView(ctx, paymentPublicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	return &paymentDTO.IssueRefundDTO{
+		Refund: toRefundDTO(rf),
+		Payment: toPaymentSummaryDTO(view),
+	}, nil
+}
+
+func (s *RefundService) List(ctx context.Context, paymentPublicID, userPublicID string) (*paymentDTO.PaymentRefundsDTO, error) {
+	_, view, err := s.authorize(ctx, paymentPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.r.ListRefunds(ctx, view.ID)
+	if err != nil {
+		return nil, fmt.Errorf("refund#list: %w", err)
+	}
+
+	res := &paymentDTO.PaymentRefundsDTO{
+		Payment: toPaymentSummaryDTO(view),
+		Refunds: make([]paymentDTO.RefundDTO, 0, len(refunds)),
+	}
+	for i := range refunds {
+		res.Refunds = append(res.Refunds, toRefundDTO(&refunds[i]))
+	}
+	return res, nil
+}
+
+// Run sends requested refunds to the provider every retry interval until
+// ctx is cancelled.
+func (s *RefundService) Run(ctx context.Context) {
+	s.logger.Info().Dur("interval", s.retryInterval).Msg("refund retrier started")
+
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("refund retrier stopped")
+			return
+		case <-ticker.C:
+			s.processRequested(ctx)
+		}
+	}
+}
+
+// processRequested stops at the first refund the provider can not take,
+// the rest would most likely fail the same way.
+func (s *RefundService) processRequested(ctx context.Context) {
+	for i := 0; i < refundRetryBatch && ctx.Err() == nil; i++ {
+		rf, err := s.r.ProcessRefund(ctx, 0, s.send)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to send requested refund")
+			return
+		}
+		if rf == nil {
+			return
+		}
+		s.logger.Info().
+			Str("refund_public_id", rf.PublicID).
+			Str("status", rf.Status).
+			Msg("requested refund processed")
+	}
+}
+
+func (s *RefundService) send(ctx context.Context, rf *Refund, p *Payment) (*ChargeResult, error) {
+	if p.ProviderRef == nil || p.Provider != s.provider.Name() {
+		return nil, fmt.Errorf("%w: payment was not charged through %s", ErrChargeNotFound, s.provider.Name())
+	}
+	return s.provider.Refund(ctx, *p.ProviderRef, rf.PublicID, rf.Amount)
+}
+
+func (s *RefundService) authorize(ctx context.Context, paymentPublicID, userPublicID string) (*commonDTO.UserDTO, *PaymentView, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refund#authorize: %w", err)
+	}
+
+	view, err := s.r.FindPaymentView(ctx, paymentPublicID)
+	if err != nil {
+		return nil, nil, s.mapError(err)
+	}
+
+	if usr.Role == "organizer" && view.OrganizerID != uint(usr.ID) {
+		return nil, nil, errs.NewForbiddenError("only the organizer of this event can refund its payments")
+	}
+	return usr, view, nil
+}
+
+func (s *RefundService) mapError(err error) error {
+	switch {
+	case errors.Is(err, ErrPaymentNotFound):
+		return errs.NewErrNotFound("payment")
+	case errors.Is(err, ErrPaymentNotRefundable):
+		return errs.NewConflictError("payment can not be refunded in its current status")
+	case errors.Is(err, ErrRefundExceedsPayment):
+		return errs.NewValidationError("refund exceeds the amount left on the payment")
+	case errors.Is(err, ErrRefundCurrencyMismatch):
+		return errs.NewValidationError("refund must be in the currency of the payment")
+	default:
+		return fmt.Errorf("refund service: %w", err)
+	}
+}
+
+func toRefundDTO(rf *Refund) paymentDTO.RefundDTO {
+	return paymentDTO.RefundDTO{
+		PublicID: rf.PublicID,
+		Amount: rf.Amount,
+		Status: rf.Status,
+		Reason: rf.Reason,
+		FailureMessage: rf.FailureMessage,
+		CreatedAt: rf.CreatedAt,
+	}
+}
+
+func toPaymentSummaryDTO(v *PaymentView) paymentDTO.PaymentSummaryDTO {
+	return paymentDTO.PaymentSummaryDTO{
+		PublicID: v.PublicID,
+		Amount: v.Amount,
+		Status: v.Status,
+		RefundedAmount: v.RefundedAmount,
+	}
+}
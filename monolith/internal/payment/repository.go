@@ -4,22 +4,108 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/anrisys/quicket/internal/booking"
 	commonDTO "github.com/anrisys/quicket/internal/dto"
+	paymentDTO "github.com/anrisys/quicket/internal/payment/dto"
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookingRow struct {
 	ID uint
 	PublicID string
+	EventID uint
+	TicketTypeID *uint
+	UserID uint
+	Seats uint
 	Status string
 }
 
+// TicketRow is a ticket issued for a paid booking.
+type TicketRow struct {
+	PublicID string
+	BookingID uint
+	EventID uint
+	UserID uint
+	SeatID *uint
+}
+
 type Repository interface {
-	CreatePaymentAndUpdateBookingStatus(ctx context.Context, p *Payment) (*commonDTO.PaymentDTO, error)
+	CreatePaymentAndUpdateBookingStatus(ctx context.Context, p *Payment, failurePolicy string) (*commonDTO.PaymentDTO, error)
+	RefundUnclaimedCharge(ctx context.Context, p *Payment, reason string) (*Refund, error)
+	ApplyWebhookEvent(ctx context.Context, provider string, ev *paymentDTO.WebhookEventRequest, failurePolicy string) (string, error)
+	EnqueueJob(ctx context.Context, job *PaymentJob, depth int) error
+	LeaseJob(ctx context.Context, now time.Time, visibility time.Duration) (*PaymentJob, error)
+	CompleteJob(ctx context.Context, job *PaymentJob) error
+	RetryJob(ctx context.Context, job *PaymentJob, at time.Time, reason string) error
+	BuryJob(ctx context.Context, job *PaymentJob, reason string) error
+	ReleaseJob(ctx context.Context, job *PaymentJob) error
+	BookingStatus(ctx context.Context, bookingID uint) (string, error)
+	FindPaymentView(ctx context.Context, publicID string) (*PaymentView, error)
+	ListPayments(ctx context.Context, filter PaymentFilter) ([]PaymentView, error)
+	FindBookingAccess(ctx context.Context, bookingPublicID string) (*BookingAccess, error)
+	ListRefunds(ctx context.Context, paymentID uint) ([]Refund, error)
+	CreateRefund(ctx context.Context, paymentPublicID string, rf *Refund) error
+	ProcessRefund(ctx context.Context, refundID uint, send RefundSender) (*Refund, error)
+	FindPaymentsByProviderRefs(ctx context.Context, provider string, refs []string) ([]Payment, error)
+	FindUnpaidConfirmedBookings(ctx context.Context, from, to time.Time) ([]string, error)
+	CreateReconciliationReport(ctx context.Context, report *ReconciliationReport) error
+	ListReconciliationReports(ctx context.Context, afterID uint, limit int) ([]ReconciliationReport, error)
+	FindReconciliationReport(ctx context.Context, publicID string) (*ReconciliationReport, error)
+}
+
+// PaymentView is a payment with the organizer of its event and the amount
+// refunded so far.
+type PaymentView struct {
+	ID uint
+	PublicID string
+	Amount money.Money
+	Currency money.Currency
+	Status string
+	Provider string
+	BookingID uint
+	BookingPublicID string
+	UserID uint
+	OrganizerID uint
+	RefundedAmount money.Money
+	CreatedAt time.Time
+}
+
+const paymentViewColumns = "payments.id, payments.public_id, payments.amount, payments.currency, payments.status, payments.provider, " +
+	"payments.booking_id, bookings.public_id AS booking_public_id, payments.user_id, " +
+	"events.organizer_id, payments.created_at, " +
+	"COALESCE((SELECT SUM(refunds.amount) FROM refunds " +
+	"WHERE refunds.payment_id = payments.id AND refunds.status = ?), 0) AS refunded_amount"
+
+// PaymentFilter narrows the payments returned by ListPayments. Zero fields
+// are not filtered on. AfterID is the id of the last payment of the
+// previous page; zero starts from the newest.
+type PaymentFilter struct {
+	BookingID uint
+	Status string
+	From time.Time
+	To time.Time
+	AfterID uint
+	Limit int
 }
 
+// BookingAccess tells who may see the payments of a booking: the user who
+// made it and the organizer of its event.
+type BookingAccess struct {
+	ID uint
+	UserID uint
+	OrganizerID uint
+}
+
+// RefundSender hands a refund to the payment provider.
+type RefundSender func(ctx context.Context, rf *Refund, p *Payment) (*ChargeResult, error)
+
 type GormRepository struct {
 	db *gorm.DB
 	logger zerolog.Logger
@@ -32,47 +118,922 @@ func NewRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
 	}
 }
 
-func (r *GormRepository) CreatePaymentAndUpdateBookingStatus(ctx context.Context, p *Payment) (*commonDTO.PaymentDTO, error) {
-	var b BookingRow
+func (r *GormRepository) CreatePaymentAndUpdateBookingStatus(ctx context.Context, p *Payment, failurePolicy string) (*commonDTO.PaymentDTO, error) {
+	var b *BookingRow
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Table("bookings").
-			Where("id = ?", p.BookingID).
-			Select("id", "public_id", "status").
-			Take(&b).Error; err != nil {
-			
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return ErrBookingNotFound
-				}
-				
-				r.logger.Error().Err(err).
-					Uint("booking_id", p.BookingID).
-					Msg("select booking failed")
-				return fmt.Errorf("%w: %v", ErrDB, err)
+		var err error
+		b, err = r.lockBooking(tx, "id = ?", p.BookingID)
+		if err != nil {
+			return err
+		}
+		return r.settle(ctx, tx, p, b, failurePolicy)
+	})
+
+	dto := &commonDTO.PaymentDTO{
+		PublicID: p.PublicID,
+		Amount: p.Amount,
+		Status: p.Status,
+	}
+	if b != nil {
+		dto.BookingID = b.PublicID
+	}
+	return dto, err
+}
+
+// RefundUnclaimedCharge records a captured payment whose booking was
+// cancelled or expired while it was being charged, and requests a refund of
+// all of it in the same transaction. The booking keeps its status; the
+// refund is sent to the provider by the refund retrier.
+func (r *GormRepository) RefundUnclaimedCharge(ctx context.Context, p *Payment, reason string) (*Refund, error) {
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &Refund{
+		PublicID: publicID,
+		BookingID: p.BookingID,
+		UserID: p.UserID,
+		Amount: p.Amount,
+		Currency: p.Currency,
+		Status: RefundRequested,
+		Reason: &reason,
+	}
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := r.lockBooking(tx, "id = ?", p.BookingID); err != nil {
+			return err
 		}
 
 		if err := tx.Table("payments").Create(p).Error; err != nil {
 			r.logger.Error().Err(err).
 				Uint("booking_id", p.BookingID).
 				Uint("user_id", p.UserID).
-				Msg("insert payment failed")
+				Msg("insert unclaimed payment failed")
 			return fmt.Errorf("%w: %v", ErrDB, err)
 		}
 
-		
-		if err := tx.Table("bookings").Where("id = ?", b.ID).Update("status", p.Status).Error; err != nil {
+		rf.PaymentID = p.ID
+		if err := tx.Create(rf).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("payment_id", p.ID).
+				Msg("insert refund failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// lockBooking locks the booking so the expiry job can not release its seats
+// while a payment result is being written.
+func (r *GormRepository) lockBooking(tx *gorm.DB, query string, arg any) (*BookingRow, error) {
+	var b BookingRow
+	if err := tx.Table("bookings").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, arg).
+		Select("id", "public_id", "event_id", "ticket_type_id", "user_id", "seats", "status").
+		Take(&b).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Interface("booking", arg).
+			Msg("select booking failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &b, nil
+}
+
+// settle records the first result of a payment for the locked booking b and
+// moves the booking on. A paid booking gets its seats sold and its tickets
+// issued. A failed payment fails the booking and gives its seats back, or,
+// under the retry policy, leaves it pending so it can be paid again before
+// it expires.
+func (r *GormRepository) settle(ctx context.Context, tx *gorm.DB, p *Payment, b *BookingRow, failurePolicy string) error {
+	// A payment that arrives after the booking expired or was cancelled
+	// must not bring it back.
+	target, reason := booking.StatusFailed, "payment failed"
+	if p.Status == StatusSuccess {
+		target, reason = booking.StatusConfirmed, "payment succeeded"
+	}
+	if !booking.CanTransition(b.Status, target) {
+		return ErrBookingNotPending
+	}
+
+	if err := tx.Table("payments").Create(p).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", p.BookingID).
+			Uint("user_id", p.UserID).
+			Msg("insert payment failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	if p.Status != StatusSuccess && failurePolicy == FailurePolicyRetry {
+		return nil
+	}
+
+	if err := booking.Transition(tx, b.ID, b.Status, target, reason, booking.SystemActor); err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("failed to update booking status")
+		return err
+	}
+
+	if p.Status != StatusSuccess {
+		released := booking.ReleasedBooking{
+			ID: b.ID,
+			EventID: b.EventID,
+			TicketTypeID: b.TicketTypeID,
+			Seats: b.Seats,
+		}
+		if err := booking.ReleaseSeats(tx, []booking.ReleasedBooking{released}); err != nil {
 			r.logger.Error().Err(err).
 				Uint("booking_id", b.ID).
-				Msg("failed to update booking status")
+				Uint("event_id", b.EventID).
+				Msg("failed to release seats of failed booking")
+			return err
+		}
+		return nil
+	}
+
+	// Seats picked from a seat map stay held until the booking is paid.
+	if err := tx.Table("seats").Where("booking_id = ?", b.ID).Update("status", "sold").Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("failed to mark seats sold")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	return r.issueTickets(ctx, tx, b)
+}
+
+// issueTickets creates one ticket per seat of a paid booking, tied to the
+// picked seat when the event has a seat map.
+func (r *GormRepository) issueTickets(ctx context.Context, tx *gorm.DB, b *BookingRow) error {
+	var seatIDs []uint
+	if err := tx.Table("seats").
+		Where("booking_id = ?", b.ID).
+		Order("id").
+		Pluck("id", &seatIDs).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("select booking seats failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	tickets := make([]TicketRow, 0, b.Seats)
+	for i := uint(0); i < b.Seats; i++ {
+		publicID, err := util.GeneratePublicID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to generate ticket public ID: %w", err)
+		}
+		t := TicketRow{
+			PublicID: publicID,
+			BookingID: b.ID,
+			EventID: b.EventID,
+			UserID: b.UserID,
+		}
+		if int(i) < len(seatIDs) {
+			t.SeatID = &seatIDs[i]
+		}
+		tickets = append(tickets, t)
+	}
+
+	if err := tx.Table("tickets").Create(&tickets).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("insert tickets failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// ApplyWebhookEvent applies a provider event to its payment and booking. The
+// event is recorded in the same transaction, so a redelivered event returns
+// ErrDuplicateEvent and an event that failed half way can be retried.
+func (r *GormRepository) ApplyWebhookEvent(ctx context.Context, provider string, ev *paymentDTO.WebhookEventRequest, failurePolicy string) (string, error) {
+	result := WebhookIgnored
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var seen int64
+		if err := tx.Model(&WebhookEvent{}).Where("event_id = ?", ev.ID).Count(&seen).Error; err != nil {
+			r.logger.Error().Err(err).
+				Str("event_id", ev.ID).
+				Msg("select webhook event failed")
 			return fmt.Errorf("%w: %v", ErrDB, err)
 		}
+		if seen > 0 {
+			return ErrDuplicateEvent
+		}
+
+		p, err := r.lockPayment(tx, ev.Data.PaymentID)
+		if err != nil && !errors.Is(err, ErrPaymentNotFound) {
+			return err
+		}
+
+		switch ev.Type {
+		case EventSucceeded, EventFailed:
+			result, p, err = r.applyResult(ctx, tx, provider, ev, p, failurePolicy)
+		case EventRefunded:
+			result, err = r.applyRefund(tx, p)
+		case EventDisputed:
+			result, err = r.applyDispute(tx, p)
+		}
+		if err != nil {
+			return err
+		}
 
+		record := WebhookEvent{
+			EventID: ev.ID,
+			Provider: provider,
+			Type: ev.Type,
+			Result: result,
+		}
+		if p != nil {
+			record.PaymentID = &p.ID
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			// A concurrent delivery of the same event committed first.
+			if isDuplicateKey(err) {
+				return ErrDuplicateEvent
+			}
+			r.logger.Error().Err(err).
+				Str("event_id", ev.ID).
+				Msg("insert webhook event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
 		return nil
 	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
 
-	return &commonDTO.PaymentDTO{
-		PublicID: p.PublicID,
-		Amount: p.Amount,
-		Status: p.Status,
-		BookingID: b.PublicID,
-	}, err
-}
\ No newline at end of file
+func (r *GormRepository) lockPayment(tx *gorm.DB, publicID string) (*Payment, error) {
+	var p Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("public_id = ?", publicID).
+		Take(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("payment_public_id", publicID).
+			Msg("lock/select payment failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &p, nil
+}
+
+// applyResult records a succeeded or failed payment the worker has not
+// recorded yet, for example because it stopped after the capture. A payment
+// that is already recorded is left as it is.
+func (r *GormRepository) applyResult(ctx context.Context, tx *gorm.DB, provider string, ev *paymentDTO.WebhookEventRequest, p *Payment, failurePolicy string) (string, *Payment, error) {
+	status := StatusFailed
+	if ev.Type == EventSucceeded {
+		status = StatusSuccess
+	}
+
+	if p != nil {
+		if p.Status != status {
+			r.logger.Warn().
+				Str("payment_public_id", p.PublicID).
+				Str("status", p.Status).
+				Str("event_type", ev.Type).
+				Msg("webhook result conflicts with recorded payment, ignored")
+		}
+		return WebhookIgnored, p, nil
+	}
+
+	if ev.Data.BookingID == "" {
+		return "", nil, ErrPaymentNotFound
+	}
+	b, err := r.lockBooking(tx, "public_id = ?", ev.Data.BookingID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	p = &Payment{
+		PublicID: ev.Data.PaymentID,
+		Amount: ev.Data.Amount,
+		Currency: ev.Data.Amount.Currency,
+		Status: status,
+		Provider: provider,
+		BookingID: b.ID,
+		UserID: b.UserID,
+	}
+	if ev.Data.ChargeID != "" {
+		p.ProviderRef = &ev.Data.ChargeID
+	}
+	if err := r.settle(ctx, tx, p, b, failurePolicy); err != nil {
+		if errors.Is(err, ErrBookingNotPending) {
+			r.logger.Warn().
+				Str("payment_public_id", p.PublicID).
+				Str("booking_status", b.Status).
+				Str("event_type", ev.Type).
+				Msg("webhook result for a booking that is no longer pending, ignored")
+			return WebhookIgnored, nil, nil
+		}
+		return "", nil, err
+	}
+	return WebhookApplied, p, nil
+}
+
+// applyRefund marks a paid payment refunded and moves its booking to the
+// refunded status, giving back the seats of a confirmed one.
+func (r *GormRepository) applyRefund(tx *gorm.DB, p *Payment) (string, error) {
+	if p == nil {
+		return "", ErrPaymentNotFound
+	}
+	if p.Status != StatusSuccess && p.Status != StatusDisputed {
+		return WebhookIgnored, nil
+	}
+
+	if err := tx.Model(p).Update("status", StatusRefunded).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", p.ID).
+			Msg("mark payment refunded failed")
+		return "", fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	b, err := r.lockBooking(tx, "id = ?", p.BookingID)
+	if err != nil {
+		return "", err
+	}
+	if err := r.refundBooking(tx, b, "refunded by payment provider", booking.SystemActor); err != nil {
+		return "", err
+	}
+	return WebhookApplied, nil
+}
+
+// applyDispute marks a paid payment disputed. The booking keeps its status
+// until the dispute is settled with a refund or dropped.
+func (r *GormRepository) applyDispute(tx *gorm.DB, p *Payment) (string, error) {
+	if p == nil {
+		return "", ErrPaymentNotFound
+	}
+	if p.Status != StatusSuccess {
+		return WebhookIgnored, nil
+	}
+
+	if err := tx.Model(p).Update("status", StatusDisputed).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", p.ID).
+			Msg("mark payment disputed failed")
+		return "", fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return WebhookApplied, nil
+}
+
+// EnqueueJob stores a job for the workers, unless depth jobs are already
+// waiting or running or the booking already has a job in flight. The
+// booking row is locked so two retries can not queue a job each.
+func (r *GormRepository) EnqueueJob(ctx context.Context, job *PaymentJob, depth int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := r.lockBooking(tx, "id = ?", job.BookingID); err != nil {
+			return err
+		}
+
+		var inFlight int64
+		if err := tx.Model(&PaymentJob{}).
+			Where("booking_id = ? AND status IN ?", job.BookingID, []string{JobQueued, JobRunning}).
+			Count(&inFlight).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", job.BookingID).
+				Msg("count booking payment jobs failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if inFlight > 0 {
+			return ErrPaymentInProgress
+		}
+
+		var waiting int64
+		if err := tx.Model(&PaymentJob{}).
+			Where("status IN ?", []string{JobQueued, JobRunning}).
+			Count(&waiting).Error; err != nil {
+			r.logger.Error().Err(err).Msg("count waiting payment jobs failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if waiting >= int64(depth) {
+			return ErrQueueFull
+		}
+
+		job.Status = JobQueued
+		if err := tx.Create(job).Error; err != nil {
+			r.logger.Error().Err(err).
+				Str("payment_public_id", job.PublicID).
+				Uint("booking_id", job.BookingID).
+				Msg("insert payment job failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+// LeaseJob hands out the oldest due job, either a queued one or a running
+// one whose lease has passed, and hides it from other workers for the
+// visibility timeout. Jobs locked by another worker are skipped. It returns
+// nil when nothing is due.
+func (r *GormRepository) LeaseJob(ctx context.Context, now time.Time, visibility time.Duration) (*PaymentJob, error) {
+	var job PaymentJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND available_at <= ?", []string{JobQueued, JobRunning}, now).
+			Order("available_at").
+			Take(&job).Error; err != nil {
+			return err
+		}
+
+		job.Status = JobRunning
+		job.Attempts++
+		job.AvailableAt = now.Add(visibility)
+		return tx.Model(&job).Updates(map[string]any{
+			"status": job.Status,
+			"attempts": job.Attempts,
+			"available_at": job.AvailableAt,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Msg("lease payment job failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &job, nil
+}
+
+func (r *GormRepository) CompleteJob(ctx context.Context, job *PaymentJob) error {
+	return r.finishJob(ctx, job, map[string]any{"status": JobDone})
+}
+
+// RetryJob puts the job back in the queue to be picked up again at at.
+func (r *GormRepository) RetryJob(ctx context.Context, job *PaymentJob, at time.Time, reason string) error {
+	return r.finishJob(ctx, job, map[string]any{
+		"status": JobQueued,
+		"available_at": at,
+		"last_error": truncate(reason, 255),
+	})
+}
+
+// BuryJob marks the job dead; it is never handed out again.
+func (r *GormRepository) BuryJob(ctx context.Context, job *PaymentJob, reason string) error {
+	return r.finishJob(ctx, job, map[string]any{
+		"status": JobDead,
+		"last_error": truncate(reason, 255),
+	})
+}
+
+// ReleaseJob hands a leased job back untouched: it is due again right away
+// and the attempt it was leased for is not counted.
+func (r *GormRepository) ReleaseJob(ctx context.Context, job *PaymentJob) error {
+	return r.finishJob(ctx, job, map[string]any{
+		"status": JobQueued,
+		"available_at": time.Now(),
+		"attempts": gorm.Expr("attempts - 1"),
+	})
+}
+
+// finishJob updates a job only while the caller still holds its lease.
+func (r *GormRepository) finishJob(ctx context.Context, job *PaymentJob, updates map[string]any) error {
+	res := r.db.WithContext(ctx).Model(&PaymentJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, JobRunning, job.Attempts).
+		Updates(updates)
+	if res.Error != nil {
+		r.logger.Error().Err(res.Error).
+			Uint("job_id", job.ID).
+			Msg("update payment job failed")
+		return fmt.Errorf("%w: %v", ErrDB, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *GormRepository) BookingStatus(ctx context.Context, bookingID uint) (string, error) {
+	var b BookingRow
+	if err := r.db.WithContext(ctx).Table("bookings").
+		Select("status").
+		Where("id = ?", bookingID).
+		Take(&b).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("booking_id", bookingID).
+			Msg("select booking status failed")
+		return "", fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return b.Status, nil
+}
+
+func (r *GormRepository) FindPaymentView(ctx context.Context, publicID string) (*PaymentView, error) {
+	var v PaymentView
+	if err := r.db.WithContext(ctx).
+		Table("payments").
+		Select(paymentViewColumns, RefundSucceeded).
+		Joins("JOIN bookings ON bookings.id = payments.booking_id").
+		Joins("JOIN events ON events.id = bookings.event_id").
+		Where("payments.public_id = ?", publicID).
+		Take(&v).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("payment_public_id", publicID).
+			Msg("select payment failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &v, nil
+}
+
+// ListPayments returns the payments matching filter, newest first.
+func (r *GormRepository) ListPayments(ctx context.Context, filter PaymentFilter) ([]PaymentView, error) {
+	q := r.db.WithContext(ctx).
+		Table("payments").
+		Select(paymentViewColumns, RefundSucceeded).
+		Joins("JOIN bookings ON bookings.id = payments.booking_id").
+		Joins("JOIN events ON events.id = bookings.event_id")
+
+	if filter.BookingID > 0 {
+		q = q.Where("payments.booking_id = ?", filter.BookingID)
+	}
+	if filter.Status != "" {
+		q = q.Where("payments.status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("payments.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("payments.created_at < ?", filter.To)
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("payments.id < ?", filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var views []PaymentView
+	if err := q.Order("payments.id DESC").Find(&views).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", filter.BookingID).
+			Str("status", filter.Status).
+			Msg("list payments failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return views, nil
+}
+
+func (r *GormRepository) FindBookingAccess(ctx context.Context, bookingPublicID string) (*BookingAccess, error) {
+	var a BookingAccess
+	if err := r.db.WithContext(ctx).
+		Table("bookings").
+		Select("bookings.id, bookings.user_id, events.organizer_id").
+		Joins("JOIN events ON events.id = bookings.event_id").
+		Where("bookings.public_id = ? AND bookings.deleted_at IS NULL", bookingPublicID).
+		Take(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("booking_public_id", bookingPublicID).
+			Msg("select booking failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &a, nil
+}
+
+func (r *GormRepository) ListRefunds(ctx context.Context, paymentID uint) ([]Refund, error) {
+	var refunds []Refund
+	if err := r.db.WithContext(ctx).
+		Where("payment_id = ?", paymentID).
+		Order("id").
+		Find(&refunds).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", paymentID).
+			Msg("select refunds failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return refunds, nil
+}
+
+// CreateRefund records a requested refund against a successful payment. An
+// amount without a currency refunds whatever is left of the payment. The
+// payment is locked so concurrent refunds can not together exceed its
+// amount.
+func (r *GormRepository) CreateRefund(ctx context.Context, paymentPublicID string, rf *Refund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		p, err := r.lockPayment(tx, paymentPublicID)
+		if err != nil {
+			return err
+		}
+		if p.Status != StatusSuccess {
+			return ErrPaymentNotRefundable
+		}
+
+		reserved, err := r.sumRefunds(tx, p.ID, RefundRequested, RefundSucceeded)
+		if err != nil {
+			return err
+		}
+		remaining := p.Amount.Amount - reserved
+		if rf.Amount.Currency == "" {
+			rf.Amount = money.New(remaining, p.Currency)
+		}
+		if rf.Amount.Currency != p.Currency {
+			return ErrRefundCurrencyMismatch
+		}
+		if remaining <= 0 || rf.Amount.Amount > remaining {
+			return ErrRefundExceedsPayment
+		}
+		rf.Currency = p.Currency
+
+		rf.PaymentID = p.ID
+		rf.BookingID = p.BookingID
+		rf.UserID = p.UserID
+		rf.Status = RefundRequested
+		if err := tx.Create(rf).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("payment_id", p.ID).
+				Msg("insert refund failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+// ProcessRefund sends a requested refund to the provider and records the
+// outcome. A zero refundID takes the oldest requested refund. The refund
+// stays locked while the provider is called, so it is never sent twice at
+// the same time; when the provider can not be reached it is left requested
+// for a later run. It returns nil when there is nothing to process, and the
+// refund as it is when it was already processed.
+func (r *GormRepository) ProcessRefund(ctx context.Context, refundID uint, send RefundSender) (*Refund, error) {
+	var rf Refund
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		q := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", RefundRequested)
+		if refundID != 0 {
+			q = q.Where("id = ?", refundID)
+		}
+		if err := q.Order("id").Take(&rf).Error; err != nil {
+			return err
+		}
+
+		var p Payment
+		if err := tx.Where("id = ?", rf.PaymentID).Take(&p).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("payment_id", rf.PaymentID).
+				Msg("select payment failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if _, err := send(ctx, &rf, &p); err != nil {
+			if !errors.Is(err, ErrChargeNotFound) && !errors.Is(err, ErrChargeState) {
+				return err
+			}
+			return r.failRefund(tx, &rf, err.Error())
+		}
+		return r.completeRefund(tx, &rf)
+	})
+	if err == nil {
+		return &rf, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if refundID == 0 {
+		return nil, nil
+	}
+
+	// Already processed, or being processed by someone else right now.
+	if err := r.db.WithContext(ctx).Where("id = ?", refundID).Take(&rf).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &rf, nil
+}
+
+func (r *GormRepository) failRefund(tx *gorm.DB, rf *Refund, message string) error {
+	rf.Status = RefundFailed
+	rf.FailureMessage = &message
+	if err := tx.Model(rf).Updates(map[string]any{
+		"status": rf.Status,
+		"failure_message": truncate(message, 255),
+	}).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("refund_id", rf.ID).
+			Msg("mark refund failed failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// completeRefund marks the refund succeeded. Once the payment is refunded in
+// full the payment and its booking move to refunded as well.
+func (r *GormRepository) completeRefund(tx *gorm.DB, rf *Refund) error {
+	rf.Status = RefundSucceeded
+	if err := tx.Model(rf).Update("status", rf.Status).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("refund_id", rf.ID).
+			Msg("mark refund succeeded failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	b, err := r.lockBooking(tx, "id = ?", rf.BookingID)
+	if err != nil {
+		return err
+	}
+	var p Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", rf.PaymentID).
+		Take(&p).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", rf.PaymentID).
+			Msg("lock/select payment failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	refunded, err := r.sumRefunds(tx, p.ID, RefundSucceeded)
+	if err != nil {
+		return err
+	}
+	if refunded < p.Amount.Amount || p.Status == StatusRefunded {
+		return nil
+	}
+
+	if err := tx.Model(&p).Update("status", StatusRefunded).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", p.ID).
+			Msg("mark payment refunded failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+
+	actor := booking.SystemActor
+	if rf.RequestedBy != nil {
+		actor = booking.UserActor(*rf.RequestedBy)
+	}
+	return r.refundBooking(tx, b, "refunded in full", actor)
+}
+
+// refundBooking moves the locked booking b to refunded, if it can still get
+// there. A confirmed booking still holds its seats, which are given back to
+// the event as a cancellation would; a cancelled one gave them back already.
+func (r *GormRepository) refundBooking(tx *gorm.DB, b *BookingRow, reason string, actor booking.Actor) error {
+	if !booking.CanTransition(b.Status, booking.StatusRefunded) {
+		return nil
+	}
+	if err := booking.Transition(tx, b.ID, b.Status, booking.StatusRefunded, reason, actor); err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Msg("mark booking refunded failed")
+		return err
+	}
+	if b.Status != booking.StatusConfirmed {
+		return nil
+	}
+
+	released := booking.ReleasedBooking{
+		ID: b.ID,
+		EventID: b.EventID,
+		TicketTypeID: b.TicketTypeID,
+		Seats: b.Seats,
+	}
+	if err := booking.ReleaseSeats(tx, []booking.ReleasedBooking{released}); err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", b.ID).
+			Uint("event_id", b.EventID).
+			Msg("failed to release seats of refunded booking")
+		return err
+	}
+	return nil
+}
+
+// sumRefunds returns, in minor units, the total of the payment's refunds in
+// the given statuses.
+func (r *GormRepository) sumRefunds(tx *gorm.DB, paymentID uint, statuses ...string) (int64, error) {
+	var total int64
+	if err := tx.Model(&Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("payment_id = ? AND status IN ?", paymentID, statuses).
+		Row().Scan(&total); err != nil {
+		r.logger.Error().Err(err).
+			Uint("payment_id", paymentID).
+			Msg("sum refunds failed")
+		return 0, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return total, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// isDuplicateKey reports whether err is MySQL rejecting a row that breaks a
+// unique index.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// providerRefBatch bounds the IN list of a payment lookup by provider
+// reference.
+const providerRefBatch = 500
+
+// FindPaymentsByProviderRefs returns the payments charged through provider
+// whose reference is one of refs.
+func (r *GormRepository) FindPaymentsByProviderRefs(ctx context.Context, provider string, refs []string) ([]Payment, error) {
+	var payments []Payment
+	for len(refs) > 0 {
+		batch := refs[:min(len(refs), providerRefBatch)]
+		refs = refs[len(batch):]
+
+		var found []Payment
+		if err := r.db.WithContext(ctx).
+			Where("provider = ? AND provider_ref IN ?", provider, batch).
+			Find(&found).Error; err != nil {
+			r.logger.Error().Err(err).
+				Str("provider", provider).
+				Msg("find payments by provider reference failed")
+			return nil, fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		payments = append(payments, found...)
+	}
+	return payments, nil
+}
+
+// FindUnpaidConfirmedBookings returns the public IDs of bookings made in
+// [from, to) that are confirmed without a successful payment.
+func (r *GormRepository) FindUnpaidConfirmedBookings(ctx context.Context, from, to time.Time) ([]string, error) {
+	var publicIDs []string
+	if err := r.db.WithContext(ctx).
+		Table("bookings").
+		Where("bookings.status = ? AND bookings.created_at >= ? AND bookings.created_at < ? AND bookings.deleted_at IS NULL",
+			booking.StatusConfirmed, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.booking_id = bookings.id AND payments.status = ?)", StatusSuccess).
+		Order("bookings.id").
+		Pluck("bookings.public_id", &publicIDs).Error; err != nil {
+		r.logger.Error().Err(err).
+			Time("from", from).
+			Time("to", to).
+			Msg("find unpaid confirmed bookings failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return publicIDs, nil
+}
+
+// CreateReconciliationReport stores report together with its mismatches.
+func (r *GormRepository) CreateReconciliationReport(ctx context.Context, report *ReconciliationReport) error {
+	if err := r.db.WithContext(ctx).Create(report).Error; err != nil {
+		r.logger.Error().Err(err).
+			Str("report_public_id", report.PublicID).
+			Msg("insert reconciliation report failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// ListReconciliationReports returns reports newest first, without their
+// mismatches.
+func (r *GormRepository) ListReconciliationReports(ctx context.Context, afterID uint, limit int) ([]ReconciliationReport, error) {
+	q := r.db.WithContext(ctx).Model(&ReconciliationReport{})
+	if afterID > 0 {
+		q = q.Where("id < ?", afterID)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var reports []ReconciliationReport
+	if err := q.Order("id DESC").Find(&reports).Error; err != nil {
+		r.logger.Error().Err(err).Msg("list reconciliation reports failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return reports, nil
+}
+
+func (r *GormRepository) FindReconciliationReport(ctx context.Context, publicID string) (*ReconciliationReport, error) {
+	var report ReconciliationReport
+	if err := r.db.WithContext(ctx).
+		Preload("Mismatches", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("public_id = ?", publicID).
+		Take(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("report_public_id", publicID).
+			Msg("find reconciliation report failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &report, nil
+}
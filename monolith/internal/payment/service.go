@@ -2,45 +2,51 @@ package payment
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 
 	commonDTO "github.com/anrisys/quicket/internal/dto"
+	paymentDTO "github.com/anrisys/quicket/internal/payment/dto"
+	"github.com/anrisys/quicket/pkg/config"
 	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
 	"github.com/anrisys/quicket/pkg/util"
 	"github.com/rs/zerolog"
 )
 
-const (
-	numWorkers = 5
-	jobQueueSize = 100
-)
-
-type PaymentJob struct {
-	BookingID uint
-	UserID uint
-	Amount float32
-	PublicID string
+type PaymentServiceInterface interface {
+	VerifyWebhook(timestamp, signature string, body []byte) error
+	ApplyWebhook(ctx context.Context, ev *paymentDTO.WebhookEventRequest) (string, error)
+	Get(ctx context.Context, paymentPublicID, userPublicID string) (*commonDTO.PaymentDTO, error)
+	ListByBooking(ctx context.Context, bookingPublicID, userPublicID string) ([]commonDTO.PaymentDTO, error)
+	List(ctx context.Context, query *paymentDTO.ListPaymentsQuery) (*paymentDTO.PaymentListDTO, error)
 }
 
-type PaymentServiceInterface any
+const defaultListLimit = 20
 
 type PaymentService struct {
 	r *GormRepository
+	provider PaymentProvider
+	users types.UserReader
+	webhookSecret string
+	webhookTolerance time.Duration
+	queueDepth int
+	failurePolicy string
 	logger zerolog.Logger
-	jobQueue chan PaymentJob
 }
 
-func NewPaymentService(r *GormRepository, logger zerolog.Logger) *PaymentService {
-	jobQueue := make(chan PaymentJob, jobQueueSize)
-	for i := 1; i <= numWorkers; i++ {
-		go startWorker(i, r, logger, jobQueue)
-	}
+func NewPaymentService(r *GormRepository, provider PaymentProvider, users types.UserReader, cfg *config.AppConfig, logger zerolog.Logger) *PaymentService {
+	logger.Info().Str("provider", provider.Name()).Msg("payment provider selected")
 	return &PaymentService{
 		r: r,
+		provider: provider,
+		users: users,
+		webhookSecret: cfg.Payment.WebhookSecret,
+		webhookTolerance: cfg.Payment.WebhookTolerance,
+		queueDepth: cfg.Payment.QueueDepth,
+		failurePolicy: cfg.Payment.FailurePolicy,
 		logger: logger,
-		jobQueue: jobQueue,
 	}
 }
 
@@ -51,76 +57,196 @@ func (s *PaymentService) SimulatePayment(ctx context.Context, bookData *commonDT
 		return nil, fmt.Errorf("payment#SimulatePayment: %w", err)
 	}
 	
-	job := PaymentJob{
+	job := &PaymentJob{
+		PublicID: publicID,
 		BookingID: bookData.BookingID,
+		BookingPublicID: bookData.BookingPublicID,
 		UserID: bookData.UserID,
 		Amount: bookData.Amount,
-		PublicID: publicID,
+		Currency: bookData.Amount.Currency,
+		AvailableAt: time.Now(),
 	}
 
-	select {
-		case s.jobQueue <- job:
-			s.logger.Info().
-				Str("payment_public_id", publicID).
-				Uint("booking_id", bookData.BookingID).
-				Int("queue_length", len(s.jobQueue)).
-				Msg("payment simulation added into queue")
-		default: 
+	if err := s.r.EnqueueJob(ctx, job, s.queueDepth); err != nil {
+		if errors.Is(err, ErrQueueFull) {
 			s.logger.Warn().
 				Str("payment_public_id", publicID).
+				Int("queue_depth", s.queueDepth).
 				Msg("job queue is full, payment simulation rejected")
 			return nil, errs.NewServiceUnavailableError("payment system busy")
+		}
+		if errors.Is(err, ErrPaymentInProgress) {
+			return nil, errs.NewConflictError("a payment for this booking is already in progress")
+		}
+		if errors.Is(err, ErrBookingNotFound) {
+			return nil, errs.NewErrNotFound("booking")
+		}
+		return nil, fmt.Errorf("payment#SimulatePayment: enqueue: %w", err)
 	}
 
+	s.logger.Info().
+		Str("payment_public_id", publicID).
+		Uint("booking_id", bookData.BookingID).
+		Msg("payment simulation added into queue")
+
 	return nil, nil
 }
 
-func startWorker(id int, r *GormRepository, logger zerolog.Logger, jobQueue <-chan PaymentJob) {
-	logger.Info().Int("worker_id", id).Msg("payment worker started")
+func (s *PaymentService) VerifyWebhook(timestamp, signature string, body []byte) error {
+	if s.webhookSecret == "" {
+		return errs.NewServiceUnavailableError("payment webhooks are not configured")
+	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			logger.Error().
-				Int("worker_id", id).
-				Msg("worker recovered from panice")
-		}
-	}()
-	for job := range jobQueue {
-		logger.Info().
-			Int("worker_id", id).
-			Str("payment_public_id", job.PublicID).
-			Uint("booking_id", job.BookingID).
-			Msg("start process payment")
-		
-		time.Sleep(time.Duration(rand.Intn(5)) * time.Second)
-
-		status := "failed"
-		if rand.Intn(10) < 8 {
-			status = "success"
+	if err := VerifyWebhook(s.webhookSecret, s.webhookTolerance, time.Now(), timestamp, signature, body); err != nil {
+		s.logger.Warn().Err(err).Msg("payment webhook rejected")
+		return errs.NewUnauthorizedError("invalid webhook signature or timestamp", err)
+	}
+	return nil
+}
+
+// ApplyWebhook applies a verified provider event. Redelivered events are
+// answered as duplicates without touching the payment again.
+func (s *PaymentService) ApplyWebhook(ctx context.Context, ev *paymentDTO.WebhookEventRequest) (string, error) {
+	log := s.logger.With().
+		Str("event_id", ev.ID).
+		Str("event_type", ev.Type).
+		Str("payment_public_id", ev.Data.PaymentID).
+		Logger()
+
+	result, err := s.r.ApplyWebhookEvent(ctx, s.provider.Name(), ev, s.failurePolicy)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrDuplicateEvent):
+			log.Info().Msg("duplicate payment webhook skipped")
+			return WebhookDuplicate, nil
+		case errors.Is(err, ErrPaymentNotFound):
+			return "", errs.NewErrNotFound("payment")
+		case errors.Is(err, ErrBookingNotFound):
+			return "", errs.NewErrNotFound("booking")
+		default:
+			return "", fmt.Errorf("payment#applyWebhook: %w", err)
 		}
+	}
+
+	log.Info().Str("result", result).Msg("payment webhook processed")
+	return result, nil
+}
 
-		ctx := context.Background()
-		p := &Payment{
-			PublicID: job.PublicID,
-			Amount: job.Amount,
-			Status: status,
-			BookingID: job.BookingID,
-			UserID: job.UserID,
+// Get returns a payment to the user who paid it, the organizer of its event
+// or an admin.
+func (s *PaymentService) Get(ctx context.Context, paymentPublicID, userPublicID string) (*commonDTO.PaymentDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("payment#get: %w", err)
+	}
+
+	view, err := s.r.FindPaymentView(ctx, paymentPublicID)
+	if err != nil {
+		if errors.Is(err, ErrPaymentNotFound) {
+			return nil, errs.NewErrNotFound("payment")
 		}
-		if _, err := r.CreatePaymentAndUpdateBookingStatus(ctx, p); err != nil {
-			logger.Error().Err(err).
-				Int("worker_id", id).
-				Str("public_payment_id", job.PublicID).
-				Uint("booking_id", p.BookingID).
-				Uint("user_id", p.UserID).
-				Msg("failed to update and create payment")
-				continue
+		return nil, fmt.Errorf("payment#get: %w", err)
+	}
+
+	if !canView(usr, view.UserID, view.OrganizerID) {
+		return nil, errs.NewForbiddenError("payment belongs to another user")
+	}
+
+	dto := toPaymentDTO(view)
+	return &dto, nil
+}
+
+// ListByBooking returns the payments of a booking, newest first, to the
+// user who made it, the organizer of its event or an admin.
+func (s *PaymentService) ListByBooking(ctx context.Context, bookingPublicID, userPublicID string) ([]commonDTO.PaymentDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("payment#listByBooking: %w", err)
+	}
+
+	access, err := s.r.FindBookingAccess(ctx, bookingPublicID)
+	if err != nil {
+		if errors.Is(err, ErrBookingNotFound) {
+			return nil, errs.NewErrNotFound("booking")
 		}
+		return nil, fmt.Errorf("payment#listByBooking: %w", err)
+	}
+
+	if !canView(usr, access.UserID, access.OrganizerID) {
+		return nil, errs.NewForbiddenError("booking belongs to another user")
+	}
+
+	views, err := s.r.ListPayments(ctx, PaymentFilter{BookingID: access.ID})
+	if err != nil {
+		return nil, fmt.Errorf("payment#listByBooking: %w", err)
+	}
+
+	payments := make([]commonDTO.PaymentDTO, 0, len(views))
+	for i := range views {
+		payments = append(payments, toPaymentDTO(&views[i]))
+	}
+	return payments, nil
+}
+
+// List returns all payments, newest first. It is meant for admins; the
+// route is guarded by role.
+func (s *PaymentService) List(ctx context.Context, query *paymentDTO.ListPaymentsQuery) (*paymentDTO.PaymentListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, errs.NewValidationError("from must be before to")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	views, err := s.r.ListPayments(ctx, PaymentFilter{
+		Status: query.Status,
+		From: query.From,
+		To: query.To,
+		AfterID: afterID,
+		// One extra row tells whether there is a next page.
+		Limit: limit + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment#list: %w", err)
+	}
 
-		logger.Info().
-			Int("worker_id", id).
-			Uint("booking_id", job.BookingID).
-			Str("status", status).
-			Msg("Payment job completed")			
+	list := &paymentDTO.PaymentListDTO{
+		Payments: make([]commonDTO.PaymentDTO, 0, limit),
+	}
+	if len(views) > limit {
+		views = views[:limit]
+		list.NextCursor = util.EncodeCursor(views[limit-1].ID)
+	}
+	for i := range views {
+		list.Payments = append(list.Payments, toPaymentDTO(&views[i]))
 	}
-}
\ No newline at end of file
+	return list, nil
+}
+
+// canView reports whether usr may see a payment of a booking made by
+// ownerID for an event organized by organizerID.
+func canView(usr *commonDTO.UserDTO, ownerID, organizerID uint) bool {
+	switch {
+	case usr.Role == "admin":
+		return true
+	case uint(usr.ID) == ownerID:
+		return true
+	default:
+		return usr.Role == "organizer" && uint(usr.ID) == organizerID
+	}
+}
+
+func toPaymentDTO(v *PaymentView) commonDTO.PaymentDTO {
+	return commonDTO.PaymentDTO{
+		PublicID: v.PublicID,
+		Amount: v.Amount,
+		Status: v.Status,
+		BookingID: v.BookingPublicID,
+	}
+}
@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// SimulatorProvider takes up to a few seconds per authorization and
+// approves about 80% of them at random. It stands in for a real processor
+// during manual testing.
+type SimulatorProvider struct {
+	charges *ledger
+}
+
+func NewSimulatorProvider() *SimulatorProvider {
+	return &SimulatorProvider{charges: newLedger()}
+}
+
+func (p *SimulatorProvider) Name() string {
+	return ProviderSimulator
+}
+
+func (p *SimulatorProvider) Authorize(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	select {
+	case <-time.After(time.Duration(rand.Intn(5)) * time.Second):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	status := ChargeDeclined
+	if rand.Intn(10) < 8 {
+		status = ChargeAuthorized
+	}
+	return p.charges.open(req, status), nil
+}
+
+func (p *SimulatorProvider) Capture(ctx context.Context, reference string, amount money.Money) (*ChargeResult, error) {
+	return p.charges.move(reference, ChargeAuthorized, ChargeCaptured)
+}
+
+func (p *SimulatorProvider) Refund(ctx context.Context, reference, refundID string, amount money.Money) (*ChargeResult, error) {
+	return p.charges.refund(reference, refundID, amount)
+}
+
+func (p *SimulatorProvider) Status(ctx context.Context, reference string) (*ChargeResult, error) {
+	return p.charges.status(reference)
+}
+
+
+func (p *SimulatorProvider) Transactions(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+	return p.charges.transactions(from, to), nil
+}
\ No newline at end of file
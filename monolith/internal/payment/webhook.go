@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Headers a webhook delivery is signed with. The signature is the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the shared webhook secret.
+const (
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// Provider events handled by the webhook.
+const (
+	EventSucceeded = "payment.succeeded"
+	EventFailed = "payment.failed"
+	EventRefunded = "payment.refunded"
+	EventDisputed = "payment.disputed"
+)
+
+// Results of a webhook delivery.
+const (
+	WebhookApplied = "applied"
+	WebhookIgnored = "ignored"
+	WebhookDuplicate = "duplicate"
+)
+
+// SignWebhook returns the signature of a delivery sent at timestamp, a unix
+// time in seconds.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the signature of a delivery and that it was sent
+// within tolerance of now. Because the timestamp is signed, an old delivery
+// can not be replayed with a fresh timestamp.
+func VerifyWebhook(secret string, tolerance time.Duration, now time.Time, timestamp, signature string, body []byte) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookTimestamp
+	}
+
+	expected := SignWebhook(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrWebhookSignature
+	}
+
+	sent := time.Unix(ts, 0)
+	if sent.Before(now.Add(-tolerance)) || sent.After(now.Add(tolerance)) {
+		return ErrWebhookTimestamp
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package payment
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	const secret = "whsec_test"
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"payment_id":"p1"}}`)
+	ts := now.Unix()
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   error
+	}{
+		{
+			name: "valid delivery",
+			timestamp: strconv.FormatInt(ts, 10),
+			signature: SignWebhook(secret, ts, body),
+			body: body,
+		},
+		{
+			name: "signed with another secret",
+			timestamp: strconv.FormatInt(ts, 10),
+			signature: SignWebhook("other", ts, body),
+			body: body,
+			wantErr: ErrWebhookSignature,
+		},
+		{
+			name: "tampered body",
+			timestamp: strconv.FormatInt(ts, 10),
+			signature: SignWebhook(secret, ts, body),
+			body: []byte(`{"id":"evt_1","type":"payment.refunded","data":{"payment_id":"p1"}}`),
+			wantErr: ErrWebhookSignature,
+		},
+		{
+			name: "fresh timestamp on an old signature",
+			timestamp: strconv.FormatInt(ts, 10),
+			signature: SignWebhook(secret, ts-600, body),
+			body: body,
+			wantErr: ErrWebhookSignature,
+		},
+		{
+			name: "stale delivery",
+			timestamp: strconv.FormatInt(ts-600, 10),
+			signature: SignWebhook(secret, ts-600, body),
+			body: body,
+			wantErr: ErrWebhookTimestamp,
+		},
+		{
+			name: "timestamp in the future",
+			timestamp: strconv.FormatInt(ts+600, 10),
+			signature: SignWebhook(secret, ts+600, body),
+			body: body,
+			wantErr: ErrWebhookTimestamp,
+		},
+		{
+			name: "missing timestamp",
+			timestamp: "",
+			signature: SignWebhook(secret, ts, body),
+			body: body,
+			wantErr: ErrWebhookTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhook(secret, 5*time.Minute, now, tt.timestamp, tt.signature, tt.body)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -4,7 +4,14 @@ import "github.com/google/wire"
 
 var ProviderSet = wire.NewSet(
 	NewRepository,
+	NewProvider,
 	NewPaymentService,
+	NewWorker,
+	NewRefundService,
+	NewReconciliationService,
+	NewHandler,
 	wire.Bind(new(Repository), new(*GormRepository)),
 	wire.Bind(new(PaymentServiceInterface), new(*PaymentService)),
+	wire.Bind(new(RefundServiceInterface), new(*RefundService)),
+	wire.Bind(new(ReconciliationServiceInterface), new(*ReconciliationService)),
 )
\ No newline at end of file
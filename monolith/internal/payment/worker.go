@@ -0,0 +1,267 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anrisys/quicket/internal/booking"
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/rs/zerolog"
+)
+
+// maxRetryDelay caps the exponential backoff between two attempts of a job.
+const maxRetryDelay = 10 * time.Minute
+
+// Worker charges the jobs of the payment_jobs table. Any number of replicas
+// may run it; a job is leased by one worker at a time and is handed out
+// again when that worker does not finish it within the visibility timeout.
+type Worker struct {
+	r Repository
+	provider PaymentProvider
+	workers int
+	maxAttempts int
+	backoff time.Duration
+	visibility time.Duration
+	pollInterval time.Duration
+	failurePolicy string
+	logger zerolog.Logger
+}
+
+func NewWorker(r *GormRepository, provider PaymentProvider, cfg *config.AppConfig, logger zerolog.Logger) *Worker {
+	return &Worker{
+		r: r,
+		provider: provider,
+		workers: cfg.Payment.Workers,
+		maxAttempts: cfg.Payment.MaxAttempts,
+		backoff: cfg.Payment.RetryBackoff,
+		visibility: cfg.Payment.VisibilityTimeout,
+		pollInterval: cfg.Payment.PollInterval,
+		failurePolicy: cfg.Payment.FailurePolicy,
+		logger: logger,
+	}
+}
+
+// Run starts the workers and blocks until ctx is cancelled and every one
+// of them has returned. A worker finishes or hands back the job in hand
+// before it returns.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 1; i <= w.workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			w.loop(ctx, id)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context, id int) {
+	w.logger.Info().Int("worker_id", id).Msg("payment worker started")
+
+	for ctx.Err() == nil {
+		job, err := w.r.LeaseJob(ctx, time.Now(), w.visibility)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Int("worker_id", id).Msg("failed to lease payment job")
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+		w.process(ctx, id, job)
+	}
+
+	w.logger.Info().Int("worker_id", id).Msg("payment worker stopped")
+}
+
+// process sees a leased job through. Once the provider has been called the
+// charge is recorded even if shutdown starts meanwhile, so the signal in ctx
+// is only checked before that; a job not charged yet is handed back.
+func (w *Worker) process(ctx context.Context, id int, job *PaymentJob) {
+	stopping := ctx
+	ctx = context.WithoutCancel(ctx)
+
+	log := w.logger.With().
+		Int("worker_id", id).
+		Str("payment_public_id", job.PublicID).
+		Uint("booking_id", job.BookingID).
+		Int("attempt", job.Attempts).
+		Logger()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Error().Interface("panic", rec).Msg("payment worker recovered from panic")
+			w.retry(ctx, log, job, fmt.Errorf("panic: %v", rec))
+		}
+	}()
+
+	// The previous worker leased the last attempt and never reported back.
+	if job.Attempts > w.maxAttempts {
+		w.bury(ctx, log, job, "lease expired on the last attempt")
+		return
+	}
+
+	log.Info().Msg("start process payment")
+
+	// Do not charge a booking that expired or was cancelled while its job
+	// was waiting for a retry.
+	status, err := w.r.BookingStatus(ctx, job.BookingID)
+	if err != nil {
+		if errors.Is(err, ErrBookingNotFound) {
+			w.bury(ctx, log, job, err.Error())
+			return
+		}
+		w.retry(ctx, log, job, err)
+		return
+	}
+	if status != booking.StatusPending {
+		log.Warn().Str("booking_status", status).Msg("booking is no longer pending, payment job skipped")
+		w.complete(ctx, log, job)
+		return
+	}
+
+	if stopping.Err() != nil {
+		w.release(ctx, log, job)
+		return
+	}
+
+	charge, err := chargeBooking(ctx, w.provider, job)
+	if err != nil {
+		w.retry(ctx, log, job, err)
+		return
+	}
+
+	paymentStatus := StatusFailed
+	if charge.Status == ChargeCaptured {
+		paymentStatus = StatusSuccess
+	}
+
+	p := &Payment{
+		PublicID: job.PublicID,
+		Amount: job.Amount,
+		Currency: job.Currency,
+		Status: paymentStatus,
+		Provider: w.provider.Name(),
+		BookingID: job.BookingID,
+		UserID: job.UserID,
+	}
+	if charge.Reference != "" {
+		p.ProviderRef = &charge.Reference
+	}
+	if _, err := w.r.CreatePaymentAndUpdateBookingStatus(ctx, p, w.failurePolicy); err != nil {
+		if !errors.Is(err, ErrBookingNotPending) {
+			w.retry(ctx, log, job, err)
+			return
+		}
+		if paymentStatus != StatusSuccess {
+			log.Warn().Msg("booking is no longer pending, payment discarded")
+			w.complete(ctx, log, job)
+			return
+		}
+
+		// The booking was cancelled or expired during the charge, so the
+		// money is given back.
+		rf, err := w.r.RefundUnclaimedCharge(ctx, p, "booking was no longer pending when its payment was captured")
+		if err != nil {
+			w.retry(ctx, log, job, err)
+			return
+		}
+		log.Warn().
+			Str("refund_public_id", rf.PublicID).
+			Msg("booking is no longer pending, captured payment refunded")
+	}
+
+	w.complete(ctx, log, job)
+	log.Info().Str("status", paymentStatus).Msg("Payment job completed")
+}
+
+func (w *Worker) complete(ctx context.Context, log zerolog.Logger, job *PaymentJob) {
+	if err := w.r.CompleteJob(ctx, job); err != nil {
+		log.Error().Err(err).Msg("failed to mark payment job done")
+	}
+}
+
+// retry schedules the job again with exponential backoff, or buries it once
+// it has used all of its attempts. The booking stays pending until then and
+// is released by the expiry job if no attempt succeeds in time.
+func (w *Worker) retry(ctx context.Context, log zerolog.Logger, job *PaymentJob, cause error) {
+	if job.Attempts >= w.maxAttempts {
+		w.bury(ctx, log, job, cause.Error())
+		return
+	}
+
+	at := time.Now().Add(retryDelay(w.backoff, job.Attempts))
+	log.Warn().Err(cause).
+		Str("provider", w.provider.Name()).
+		Time("retry_at", at).
+		Msg("payment attempt failed, retrying")
+	if err := w.r.RetryJob(ctx, job, at, cause.Error()); err != nil {
+		log.Error().Err(err).Msg("failed to reschedule payment job")
+	}
+}
+
+func (w *Worker) release(ctx context.Context, log zerolog.Logger, job *PaymentJob) {
+	log.Info().Msg("worker is stopping, payment job handed back")
+	if err := w.r.ReleaseJob(ctx, job); err != nil {
+		log.Error().Err(err).Msg("failed to hand back payment job")
+	}
+}
+
+func (w *Worker) bury(ctx context.Context, log zerolog.Logger, job *PaymentJob, reason string) {
+	log.Error().
+		Str("provider", w.provider.Name()).
+		Str("reason", reason).
+		Msg("payment job gave up")
+	if err := w.r.BuryJob(ctx, job, reason); err != nil {
+		log.Error().Err(err).Msg("failed to mark payment job dead")
+	}
+}
+
+// retryDelay is base doubled for every attempt already made, capped at
+// maxRetryDelay.
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// chargeBooking authorizes the amount of a job and captures it right away.
+// A declined authorization is returned as is, and so is a charge captured on
+// an earlier attempt, since providers answer a payment they have seen with
+// its existing charge. A free booking has nothing to
+// charge and is settled without asking the provider, which takes positive
+// amounts only.
+func chargeBooking(ctx context.Context, provider PaymentProvider, job *PaymentJob) (*ChargeResult, error) {
+	if !job.Amount.IsPositive() {
+		return &ChargeResult{Status: ChargeCaptured}, nil
+	}
+
+	auth, err := provider.Authorize(ctx, ChargeRequest{
+		PaymentID: job.PublicID,
+		BookingID: job.BookingPublicID,
+		Amount: job.Amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("authorize: %w", err)
+	}
+	if auth.Status != ChargeAuthorized {
+		return auth, nil
+	}
+
+	captured, err := provider.Capture(ctx, auth.Reference, job.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("capture %s: %w", auth.Reference, err)
+	}
+	return captured, nil
+}
@@ -0,0 +1,166 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/internal/booking"
+	commonDTO "github.com/anrisys/quicket/internal/dto"
+	"github.com/anrisys/quicket/internal/payment/mockgateway"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first retry waits the base delay", base: 5 * time.Second, attempts: 1, want: 5 * time.Second},
+		{name: "doubles for each attempt", base: 5 * time.Second, attempts: 4, want: 40 * time.Second},
+		{name: "capped at the max delay", base: 5 * time.Second, attempts: 20, want: maxRetryDelay},
+		{name: "base above the cap", base: time.Hour, attempts: 1, want: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryDelay(tt.base, tt.attempts))
+		})
+	}
+}
+
+// workerRepository stands in for the database in worker tests. The booking
+// is pending when the worker looks and settleErr is what recording the
+// payment returns, after the first failSettles attempts fail with errDB.
+type workerRepository struct {
+	Repository
+	settleErr   error
+	failSettles int
+	settled     []*Payment
+	unclaimed   []*Payment
+	retried     int
+	completed   int
+}
+
+var errDB = errors.New("connection reset")
+
+func (r *workerRepository) BookingStatus(ctx context.Context, bookingID uint) (string, error) {
+	return booking.StatusPending, nil
+}
+
+func (r *workerRepository) CreatePaymentAndUpdateBookingStatus(ctx context.Context, p *Payment, failurePolicy string) (*commonDTO.PaymentDTO, error) {
+	if r.failSettles > 0 {
+		r.failSettles--
+		return nil, errDB
+	}
+	if r.settleErr != nil {
+		return nil, r.settleErr
+	}
+	r.settled = append(r.settled, p)
+	return &commonDTO.PaymentDTO{PublicID: p.PublicID, Status: p.Status}, nil
+}
+
+func (r *workerRepository) RefundUnclaimedCharge(ctx context.Context, p *Payment, reason string) (*Refund, error) {
+	r.unclaimed = append(r.unclaimed, p)
+	return &Refund{PublicID: "refund-1", Amount: p.Amount, Status: RefundRequested}, nil
+}
+
+func (r *workerRepository) RetryJob(ctx context.Context, job *PaymentJob, at time.Time, reason string) error {
+	r.retried++
+	job.Attempts++
+	return nil
+}
+
+func (r *workerRepository) CompleteJob(ctx context.Context, job *PaymentJob) error {
+	r.completed++
+	return nil
+}
+
+func newTestWorker(r Repository, provider PaymentProvider) *Worker {
+	return &Worker{
+		r: r,
+		provider: provider,
+		maxAttempts: 3,
+		backoff: time.Second,
+		logger: zerolog.Nop(),
+	}
+}
+
+// A booking cancelled or expired while its charge is with the provider must
+// not keep the captured money.
+func TestWorker_RefundsChargeOfBookingNoLongerPending(t *testing.T) {
+	repo := &workerRepository{settleErr: ErrBookingNotPending}
+	w := newTestWorker(repo, NewFakeProvider(FakeApprove))
+
+	job := &PaymentJob{PublicID: "payment-1", BookingID: 1, BookingPublicID: "booking-1", UserID: 2, Amount: idr(10000), Currency: "IDR", Attempts: 1}
+	w.process(context.Background(), 1, job)
+
+	require.Len(t, repo.unclaimed, 1)
+	p := repo.unclaimed[0]
+	assert.Equal(t, StatusSuccess, p.Status)
+	assert.Equal(t, idr(10000), p.Amount)
+	require.NotNil(t, p.ProviderRef)
+	assert.NotEmpty(t, *p.ProviderRef)
+	assert.Equal(t, 1, repo.completed)
+}
+
+func TestWorker_DiscardsDeclineOfBookingNoLongerPending(t *testing.T) {
+	repo := &workerRepository{settleErr: ErrBookingNotPending}
+	w := newTestWorker(repo, NewFakeProvider(FakeDecline))
+
+	job := &PaymentJob{PublicID: "payment-1", BookingID: 1, BookingPublicID: "booking-1", UserID: 2, Amount: idr(10000), Currency: "IDR", Attempts: 1}
+	w.process(context.Background(), 1, job)
+
+	assert.Empty(t, repo.unclaimed)
+	assert.Equal(t, 1, repo.completed)
+}
+
+// The gateway refuses zero amounts, so a free booking must be settled
+// without it rather than retried until it expires.
+func TestWorker_SettlesFreeBookingWithoutProvider(t *testing.T) {
+	gw := httptest.NewServer(mockgateway.NewServer())
+	defer gw.Close()
+
+	repo := &workerRepository{}
+	w := newTestWorker(repo, NewHTTPProvider(gw.URL, 5*time.Second))
+
+	job := &PaymentJob{PublicID: "payment-1", BookingID: 1, BookingPublicID: "booking-1", UserID: 2, Amount: idr(0), Currency: "IDR", Attempts: 1}
+	w.process(context.Background(), 1, job)
+
+	require.Len(t, repo.settled, 1)
+	assert.Equal(t, StatusSuccess, repo.settled[0].Status)
+	assert.Nil(t, repo.settled[0].ProviderRef)
+	assert.Equal(t, 1, repo.completed)
+}
+
+// Recording a captured payment can fail after the provider charged the
+// customer. The retry must find that charge rather than make a second one.
+func TestWorker_RetryAfterCaptureDoesNotChargeTwice(t *testing.T) {
+	repo := &workerRepository{failSettles: 1}
+	provider := NewFakeProvider(FakeApprove)
+	w := newTestWorker(repo, provider)
+
+	job := &PaymentJob{PublicID: "payment-1", BookingID: 1, BookingPublicID: "booking-1", UserID: 2, Amount: idr(10000), Currency: "IDR", Attempts: 1}
+	w.process(context.Background(), 1, job)
+	require.Equal(t, 1, repo.retried)
+	require.Empty(t, repo.settled)
+
+	w.process(context.Background(), 1, job)
+
+	require.Len(t, repo.settled, 1)
+	assert.Equal(t, StatusSuccess, repo.settled[0].Status)
+	assert.Equal(t, 1, repo.completed)
+
+	txs, err := provider.Transactions(context.Background(), time.Now().Add(-time.Minute), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, ChargeCaptured, txs[0].Status)
+	require.NotNil(t, repo.settled[0].ProviderRef)
+	assert.Equal(t, txs[0].Reference, *repo.settled[0].ProviderRef)
+}
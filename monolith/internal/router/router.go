@@ -51,6 +51,12 @@ func SetupRouter(app *di.App) *gin.Engine {
 }
 
 func registerRoutes(r *gin.Engine, app *di.App) {
+	public := r.Group("/api/v1")
+	{
+		// Authenticated by the webhook signature instead of a JWT.
+		public.POST("/payments/webhook", app.PaymentHandler.Webhook)
+	}
+
 	protected := r.Group("/api/v1")
 	protected.Use(middleware.JWTAuthMiddleware(app.Config.Security.JWTSecret))
 	{
@@ -58,11 +64,69 @@ func registerRoutes(r *gin.Engine, app *di.App) {
 		events.Use(middleware.AuthorizedRole([]string{"admin", "organizer"}))
 		{
 			events.POST("", app.EventHandler.Create)
+			events.POST("series", app.EventSeriesHandler.Create)
+			events.PATCH(":publicID", app.EventHandler.Update)
+			events.DELETE(":publicID", app.EventHandler.Delete)
+			events.POST(":publicID/ticket-types", app.EventHandler.CreateTicketType)
+			events.PATCH(":publicID/ticket-types/:ticketTypeID", app.EventHandler.UpdateTicketType)
+			events.POST(":publicID/seat-map", app.SeatMapHandler.Create)
+		}
+		venues := protected.Group("/venues")
+		venues.Use(middleware.AuthorizedRole([]string{"admin", "organizer"}))
+		{
+			venues.POST("", app.VenueHandler.Create)
+			venues.PATCH(":publicID", app.VenueHandler.Update)
+			venues.DELETE(":publicID", app.VenueHandler.Delete)
+		}
+		checkIn := protected.Group("/events")
+		checkIn.Use(middleware.AuthorizedRole([]string{"admin", "organizer", "staff"}))
+		{
+			checkIn.POST(":publicID/check-in", app.TicketHandler.CheckIn)
+			checkIn.GET(":publicID/check-in/stats", app.TicketHandler.Stats)
+		}
+		refunds := protected.Group("/payments")
+		refunds.Use(middleware.AuthorizedRole([]string{"admin", "organizer"}))
+		{
+			refunds.POST(":publicID/refunds", app.PaymentHandler.IssueRefund)
+			refunds.GET(":publicID/refunds", app.PaymentHandler.ListRefunds)
 		}
+		payments := protected.Group("/payments")
+		payments.Use(middleware.AuthorizedRole([]string{"admin"}))
+		{
+			payments.GET("", app.PaymentHandler.List)
+			payments.GET("reconciliations", app.PaymentHandler.ListReconciliationReports)
+			payments.GET("reconciliations/:publicID", app.PaymentHandler.GetReconciliationReport)
+		}
+		protected.GET("/payments/:publicID", app.PaymentHandler.Get)
+		protected.GET("/events", app.EventHandler.List)
+		protected.GET("/events/series", app.EventSeriesHandler.List)
+		protected.GET("/events/series/:publicID", app.EventSeriesHandler.Get)
+		protected.GET("/events/:publicID/seat-map", app.SeatMapHandler.Get)
+		protected.POST("/events/:publicID/waitlist", app.WaitlistHandler.Join)
+		protected.GET("/venues", app.VenueHandler.List)
+		protected.GET("/venues/:publicID", app.VenueHandler.Get)
 
 		bookings := protected.Group("/bookings")
 		{
-			bookings.POST(":eventID", app.BookingHandler.Create)
+			bookings.POST(":id", app.Idempotency.Handle(), app.BookingHandler.Create)
+			bookings.POST(":id/cancel", app.BookingHandler.Cancel)
+			bookings.POST(":id/payments", app.BookingHandler.RetryPayment)
+			bookings.GET("", app.BookingHandler.List)
+			bookings.GET(":publicID", app.BookingHandler.Get)
+			bookings.GET(":publicID/tickets", app.TicketHandler.ListByBooking)
+			bookings.GET(":publicID/payments", app.PaymentHandler.ListByBooking)
+		}
+
+		tickets := protected.Group("/tickets")
+		{
+			tickets.GET("verification-key", app.TicketHandler.VerificationKey)
+			tickets.GET(":publicID/qr", app.TicketHandler.QRCode)
+		}
+
+		waitlists := protected.Group("/waitlist")
+		{
+			waitlists.GET("", app.WaitlistHandler.List)
+			waitlists.POST(":publicID/accept", app.WaitlistHandler.Accept)
 		}
 	}
 }
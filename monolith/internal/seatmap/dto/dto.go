@@ -0,0 +1,24 @@
+package dto
+
+type SeatDTO struct {
+	PublicID string `json:"id"`
+	Label    string `json:"label"`
+	Status   string `json:"status"`
+}
+
+type RowDTO struct {
+	Label string    `json:"label"`
+	Seats []SeatDTO `json:"seats"`
+}
+
+type SectionDTO struct {
+	PublicID string   `json:"id"`
+	Name     string   `json:"name"`
+	Rows     []RowDTO `json:"rows"`
+}
+
+type SeatMapDTO struct {
+	EventID   string       `json:"event_id"`
+	Available uint64       `json:"available"`
+	Sections  []SectionDTO `json:"sections"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+type CreateSeatMapRequest struct {
+	Sections []CreateSectionRequest `json:"sections" binding:"required,min=1,dive"`
+}
+
+type CreateSectionRequest struct {
+	Name string             `json:"name" binding:"required,min=1,max=100"`
+	Rows []CreateRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+// CreateRowRequest describes a row of Seats seats numbered from 1.
+type CreateRowRequest struct {
+	Label string `json:"label" binding:"required,min=1,max=20"`
+	Seats uint   `json:"seats" binding:"required,gt=0,lte=500"`
+}
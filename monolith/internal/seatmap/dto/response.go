@@ -0,0 +1,11 @@
+package dto
+
+type ResponseSuccess struct {
+	Code    string `json:"code" example:"SUCCESS"`
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type SeatMapSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	SeatMap         SeatMapDTO `json:"seat_map"`
+}
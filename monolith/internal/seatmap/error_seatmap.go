@@ -0,0 +1,11 @@
+package seatmap
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrSeatMapExists = errors.New("event already has a seat map")
+	ErrSeatCountMismatch = errors.New("seat map size does not match event max seats")
+	ErrEventHasBookings = errors.New("event already has bookings")
+	ErrDB = errors.New("database error")
+)
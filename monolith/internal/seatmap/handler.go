@@ -0,0 +1,99 @@
+package seatmap
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/seatmap/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	srv ServiceInterface
+	logger zerolog.Logger
+}
+
+func NewHandler(srv ServiceInterface, logger zerolog.Logger) *Handler {
+	return &Handler{
+		srv: srv,
+		logger: logger,
+	}
+}
+
+// Create godoc
+// @Summary Create seat map
+// @Description Lay out the sections, rows and seats of an event (event organizer or admin only)
+// @Tags Events
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param request body dto.CreateSeatMapRequest true "Seat map layout"
+// @Success 201 {object} dto.SeatMapSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Failure 409 {object} errs.ErrorResponse "Seat map already exists or seats already booked"
+// @Router /api/v1/events/{publicID}/seat-map [post]
+func (h *Handler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	var req dto.CreateSeatMapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid seat map data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	m, err := h.srv.Create(ctx, eventPublicID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.SeatMapSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Seat map created successfully",
+		},
+		SeatMap: *m,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Get godoc
+// @Summary Get seat map
+// @Description Get the sections, rows and seats of an event with the status of each seat
+// @Tags Events
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Success 200 {object} dto.SeatMapSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errs.ErrorResponse "Event or seat map not found"
+// @Router /api/v1/events/{publicID}/seat-map [get]
+func (h *Handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	eventPublicID := c.Param("publicID")
+
+	m, err := h.srv.Get(ctx, eventPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.SeatMapSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Seat map retrieved successfully",
+		},
+		SeatMap: *m,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,50 @@
+package seatmap
+
+import "time"
+
+const (
+	SeatAvailable = "available"
+	SeatHeld      = "held"
+	SeatSold      = "sold"
+)
+
+// Section is a named area of a venue, such as "Floor" or "Balcony".
+type Section struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	EventID 		uint 		`gorm:"column:event_id;not null;index"`
+	Name 			string 		`gorm:"column:name;size:100;not null"`
+	Rows 			[]Row 		`gorm:"foreignKey:SectionID"`
+}
+
+func (s *Section) TableName() string {
+	return "seat_sections"
+}
+
+type Row struct {
+	ID 				uint 		`gorm:"primarykey"`
+	SectionID 		uint 		`gorm:"column:section_id;not null;index"`
+	Label 			string 		`gorm:"column:label;size:20;not null"`
+	Seats 			[]Seat 		`gorm:"foreignKey:RowID"`
+}
+
+func (r *Row) TableName() string {
+	return "seat_rows"
+}
+
+// Seat is a numbered seat of an event. BookingID is set while the seat is
+// held or sold.
+type Seat struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	EventID 		uint 		`gorm:"column:event_id;not null;index"`
+	RowID 			uint 		`gorm:"column:row_id;not null;index"`
+	Label 			string 		`gorm:"column:label;size:20;not null"`
+	Status 			string 		`gorm:"column:status;type:ENUM('available', 'held', 'sold');default:'available'"`
+	BookingID 		*uint 		`gorm:"column:booking_id;index"`
+	UpdatedAt 		time.Time
+}
+
+func (s *Seat) TableName() string {
+	return "seats"
+}
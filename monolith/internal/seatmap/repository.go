@@ -0,0 +1,117 @@
+package seatmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	FindEvent(ctx context.Context, publicID string) (*EventRow, error)
+	Create(ctx context.Context, eventID uint, sections []Section) error
+	FindByEvent(ctx context.Context, eventID uint) ([]Section, error)
+}
+
+// EventRow holds the columns of an event the seat map needs.
+type EventRow struct {
+	ID uint
+	PublicID string
+	OrganizerID uint
+	MaxSeats uint64
+	AvailableSeats uint64
+}
+
+type GormRepository struct {
+	db *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
+	return &GormRepository{
+		db: db,
+		logger: logger,
+	}
+}
+
+func (r *GormRepository) FindEvent(ctx context.Context, publicID string) (*EventRow, error) {
+	var ev EventRow
+	if err := r.db.WithContext(ctx).
+		Table("events").
+		Select("id, public_id, organizer_id, max_seats, available_seats").
+		Where("public_id = ? AND deleted_at IS NULL", publicID).
+		Take(&ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("event_public_id", publicID).
+			Msg("select event failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &ev, nil
+}
+
+// Create stores the sections of an event together with their rows and
+// seats. The event row is locked so the map can only be laid out once and
+// only before any seat has been booked.
+func (r *GormRepository) Create(ctx context.Context, eventID uint, sections []Section) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ev EventRow
+		if err := tx.Table("events").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, max_seats, available_seats").
+			Where("id = ? AND deleted_at IS NULL", eventID).
+			Take(&ev).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("lock/select event failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		var seats int64
+		if err := tx.Model(&Seat{}).Where("event_id = ?", eventID).Count(&seats).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("count seats failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if seats > 0 {
+			return ErrSeatMapExists
+		}
+
+		if ev.AvailableSeats != ev.MaxSeats {
+			return ErrEventHasBookings
+		}
+
+		if err := tx.Create(&sections).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("insert seat map failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+func (r *GormRepository) FindByEvent(ctx context.Context, eventID uint) ([]Section, error) {
+	var sections []Section
+	if err := r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Rows.Seats", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("event_id = ?", eventID).
+		Order("id").
+		Find(&sections).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("select seat map failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return sections, nil
+}
@@ -0,0 +1,175 @@
+package seatmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	seatMapDTO "github.com/anrisys/quicket/internal/seatmap/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+type ServiceInterface interface {
+	Create(ctx context.Context, eventPublicID string, req *seatMapDTO.CreateSeatMapRequest, userPublicID string) (*seatMapDTO.SeatMapDTO, error)
+	Get(ctx context.Context, eventPublicID string) (*seatMapDTO.SeatMapDTO, error)
+}
+
+type Service struct {
+	repo Repository
+	users types.UserReader
+	logger zerolog.Logger
+}
+
+func NewService(repo Repository, users types.UserReader, logger zerolog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		users: users,
+		logger: logger,
+	}
+}
+
+// Create lays out the seat map of an event. The number of seats has to match
+// the event's MaxSeats so the seat count and the seat map never disagree.
+func (s *Service) Create(ctx context.Context, eventPublicID string, req *seatMapDTO.CreateSeatMapRequest, userPublicID string) (*seatMapDTO.SeatMapDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("seatmap/service#create: %w", err)
+	}
+
+	ev, err := s.repo.FindEvent(ctx, eventPublicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	if usr.Role != "admin" && ev.OrganizerID != uint(usr.ID) {
+		return nil, errs.NewForbiddenError("only the organizer of this event can manage it")
+	}
+
+	sections, total, err := s.prepareSections(ctx, ev.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	if total != ev.MaxSeats {
+		return nil, s.mapError(ErrSeatCountMismatch)
+	}
+
+	if err := s.repo.Create(ctx, ev.ID, sections); err != nil {
+		return nil, s.mapError(err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Uint64("seats", total).
+		Msg("Seat map created")
+
+	return s.prepareSeatMapDTO(ev.PublicID, sections), nil
+}
+
+func (s *Service) Get(ctx context.Context, eventPublicID string) (*seatMapDTO.SeatMapDTO, error) {
+	ev, err := s.repo.FindEvent(ctx, eventPublicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	sections, err := s.repo.FindByEvent(ctx, ev.ID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	if len(sections) == 0 {
+		return nil, errs.NewErrNotFound("seat map")
+	}
+
+	return s.prepareSeatMapDTO(ev.PublicID, sections), nil
+}
+
+func (s *Service) mapError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrSeatMapExists):
+		return errs.NewConflictError("event already has a seat map")
+	case errors.Is(err, ErrEventHasBookings):
+		return errs.NewConflictError("seat map can not be added after seats have been booked")
+	case errors.Is(err, ErrSeatCountMismatch):
+		return errs.NewValidationError("number of seats must equal the event max seats")
+	default:
+		return fmt.Errorf("seatmap service: %w", err)
+	}
+}
+
+// prepareSections builds the sections, rows and seats of the request and
+// returns them with the total number of seats.
+func (s *Service) prepareSections(ctx context.Context, eventID uint, req *seatMapDTO.CreateSeatMapRequest) ([]Section, uint64, error) {
+	var total uint64
+	sections := make([]Section, 0, len(req.Sections))
+	for _, sec := range req.Sections {
+		publicID, err := util.GeneratePublicID(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to generate public ID: %w", err)
+		}
+
+		rows := make([]Row, 0, len(sec.Rows))
+		for _, row := range sec.Rows {
+			seats := make([]Seat, 0, row.Seats)
+			for n := uint(1); n <= row.Seats; n++ {
+				seatPublicID, err := util.GeneratePublicID(ctx)
+				if err != nil {
+					return nil, 0, fmt.Errorf("failed to generate public ID: %w", err)
+				}
+				seats = append(seats, Seat{
+					PublicID: seatPublicID,
+					EventID: eventID,
+					Label: strconv.FormatUint(uint64(n), 10),
+					Status: SeatAvailable,
+				})
+			}
+			total += uint64(row.Seats)
+			rows = append(rows, Row{Label: row.Label, Seats: seats})
+		}
+
+		sections = append(sections, Section{
+			PublicID: publicID,
+			EventID: eventID,
+			Name: sec.Name,
+			Rows: rows,
+		})
+	}
+	return sections, total, nil
+}
+
+func (s *Service) prepareSeatMapDTO(eventPublicID string, sections []Section) *seatMapDTO.SeatMapDTO {
+	m := &seatMapDTO.SeatMapDTO{
+		EventID: eventPublicID,
+		Sections: make([]seatMapDTO.SectionDTO, 0, len(sections)),
+	}
+	for _, sec := range sections {
+		secDTO := seatMapDTO.SectionDTO{
+			PublicID: sec.PublicID,
+			Name: sec.Name,
+			Rows: make([]seatMapDTO.RowDTO, 0, len(sec.Rows)),
+		}
+		for _, row := range sec.Rows {
+			rowDTO := seatMapDTO.RowDTO{
+				Label: row.Label,
+				Seats: make([]seatMapDTO.SeatDTO, 0, len(row.Seats)),
+			}
+			for _, seat := range row.Seats {
+				if seat.Status == SeatAvailable {
+					m.Available++
+				}
+				rowDTO.Seats = append(rowDTO.Seats, seatMapDTO.SeatDTO{
+					PublicID: seat.PublicID,
+					Label: seat.Label,
+					Status: seat.Status,
+				})
+			}
+			secDTO.Rows = append(secDTO.Rows, rowDTO)
+		}
+		m.Sections = append(m.Sections, secDTO)
+	}
+	return m
+}
@@ -0,0 +1,11 @@
+package seatmap
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	NewGormRepository,
+	NewService,
+	NewHandler,
+	wire.Bind(new(Repository), new(*GormRepository)),
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
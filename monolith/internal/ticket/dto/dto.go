@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// TicketDTO is a ticket of a booking. Status is valid, checked_in or void;
+// Token is only given for tickets that can still be used at the door.
+type TicketDTO struct {
+	PublicID    string     `json:"id"`
+	EventID     string     `json:"event_id"`
+	BookingID   string     `json:"booking_id"`
+	Seat        string     `json:"seat,omitempty"`
+	Status      string     `json:"status"`
+	Token       string     `json:"token,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+type CheckInDTO struct {
+	TicketID    string    `json:"ticket_id"`
+	BookingID   string    `json:"booking_id"`
+	Seat        string    `json:"seat,omitempty"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+	CheckedInBy string    `json:"checked_in_by"`
+}
+
+type CheckInStatsDTO struct {
+	EventID   string `json:"event_id"`
+	Issued    int64  `json:"issued"`
+	CheckedIn int64  `json:"checked_in"`
+	Remaining int64  `json:"remaining"`
+}
+
+type VerificationKeyDTO struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
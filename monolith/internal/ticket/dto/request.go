@@ -0,0 +1,5 @@
+package dto
+
+type CheckInRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+type ResponseSuccess struct {
+	Code    string `json:"code" example:"SUCCESS"`
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type TicketsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Tickets         []TicketDTO `json:"tickets"`
+}
+
+type CheckInSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	CheckIn         CheckInDTO `json:"check_in"`
+}
+
+type CheckInStatsSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Stats           CheckInStatsDTO `json:"stats"`
+}
+
+type VerificationKeySuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Key             VerificationKeyDTO `json:"key"`
+}
@@ -0,0 +1,13 @@
+package ticket
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrBookingNotFound = errors.New("booking not found")
+	ErrTicketNotFound = errors.New("ticket not found")
+	ErrTicketVoid = errors.New("ticket booking is no longer paid")
+	ErrAlreadyCheckedIn = errors.New("ticket already checked in")
+	ErrInvalidToken = errors.New("invalid ticket token")
+	ErrDB = errors.New("database error")
+)
@@ -0,0 +1,182 @@
+package ticket
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/ticket/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	srv ServiceInterface
+	logger zerolog.Logger
+}
+
+func NewHandler(srv ServiceInterface, logger zerolog.Logger) *Handler {
+	return &Handler{
+		srv: srv,
+		logger: logger,
+	}
+}
+
+// ListByBooking godoc
+// @Summary List booking tickets
+// @Description List the tickets of one of the authenticated user's bookings. Valid tickets carry a signed token to present at the door.
+// @Tags Tickets
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Booking public ID"
+// @Success 200 {object} dto.TicketsSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errs.ErrorResponse "Booking not found"
+// @Router /api/v1/bookings/{publicID}/tickets [get]
+func (h *Handler) ListByBooking(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	bookingPublicID := c.Param("publicID")
+
+	tickets, err := h.srv.ListByBooking(ctx, bookingPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.TicketsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Tickets retrieved successfully",
+		},
+		Tickets: tickets,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// QRCode godoc
+// @Summary Get ticket QR code
+// @Description Render the signed token of one of the authenticated user's tickets as a PNG QR code
+// @Tags Tickets
+// @Security BearerAuth
+// @Produce png
+// @Param publicID path string true "Ticket public ID"
+// @Success 200 {file} binary
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errs.ErrorResponse "Ticket not found"
+// @Failure 409 {object} errs.ErrorResponse "Ticket no longer valid"
+// @Router /api/v1/tickets/{publicID}/qr [get]
+func (h *Handler) QRCode(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	ticketPublicID := c.Param("publicID")
+
+	png, err := h.srv.QRCode(ctx, ticketPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// VerificationKey godoc
+// @Summary Get ticket verification key
+// @Description Get the Ed25519 public key ticket tokens are signed with, so scanners can verify them offline
+// @Tags Tickets
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.VerificationKeySuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Router /api/v1/tickets/verification-key [get]
+func (h *Handler) VerificationKey(c *gin.Context) {
+	response := dto.VerificationKeySuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Verification key retrieved successfully",
+		},
+		Key: *h.srv.VerificationKey(),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CheckIn godoc
+// @Summary Check in ticket
+// @Description Validate a scanned ticket token and admit its holder. Each ticket can be checked in once. (staff, event organizer or admin only)
+// @Tags Check-in
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param request body dto.CheckInRequest true "Scanned ticket token"
+// @Success 200 {object} dto.CheckInSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Invalid token or ticket for another event"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event or ticket not found"
+// @Failure 409 {object} errs.ErrorResponse "Ticket already checked in or no longer valid"
+// @Router /api/v1/events/{publicID}/check-in [post]
+func (h *Handler) CheckIn(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	var req dto.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid check-in data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	checkIn, err := h.srv.CheckIn(ctx, eventPublicID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.CheckInSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Ticket checked in successfully",
+		},
+		CheckIn: *checkIn,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Stats godoc
+// @Summary Get check-in stats
+// @Description Count the valid tickets of an event and how many have been checked in (staff, event organizer or admin only)
+// @Tags Check-in
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Success 200 {object} dto.CheckInStatsSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Router /api/v1/events/{publicID}/check-in/stats [get]
+func (h *Handler) Stats(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	stats, err := h.srv.Stats(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.CheckInStatsSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Check-in stats retrieved successfully",
+		},
+		Stats: *stats,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
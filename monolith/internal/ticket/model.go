@@ -0,0 +1,22 @@
+package ticket
+
+import "time"
+
+// Ticket admits one attendee to an event. A paid booking gets one ticket
+// per seat; SeatID is only set for events with a seat map.
+type Ticket struct {
+	ID uint `gorm:"primarykey"`
+	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex"`
+	BookingID uint `gorm:"column:booking_id;not null;index"`
+	EventID uint `gorm:"column:event_id;not null"`
+	UserID uint `gorm:"column:user_id;not null"`
+	SeatID *uint `gorm:"column:seat_id;index"`
+	CheckedInAt *time.Time `gorm:"column:checked_in_at"`
+	CheckedInBy *uint `gorm:"column:checked_in_by"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (t *Ticket) TableName() string {
+	return "tickets"
+}
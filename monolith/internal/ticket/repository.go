@@ -0,0 +1,217 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/internal/booking"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const bookingPaid = booking.StatusConfirmed
+
+type Repository interface {
+	FindEvent(ctx context.Context, publicID string) (*EventRow, error)
+	FindBooking(ctx context.Context, publicID string) (*BookingRow, error)
+	ListByBooking(ctx context.Context, bookingID uint) ([]View, error)
+	FindByPublicID(ctx context.Context, publicID string) (*View, error)
+	CheckIn(ctx context.Context, eventID uint, ticketPublicID string, scannerID uint) (*Ticket, error)
+	Stats(ctx context.Context, eventID uint) (*Stats, error)
+}
+
+// EventRow holds the columns of an event check-in needs.
+type EventRow struct {
+	ID uint
+	PublicID string
+	OrganizerID uint
+}
+
+// BookingRow holds the columns of a booking its tickets are listed by.
+type BookingRow struct {
+	ID uint
+	PublicID string
+	UserID uint
+	Status string
+}
+
+// View is a ticket together with the public IDs it is shown with. SeatLabel
+// is empty for events without a seat map.
+type View struct {
+	Ticket
+	EventPublicID string
+	BookingPublicID string
+	BookingStatus string
+	SeatLabel string
+}
+
+// Stats counts the valid tickets of an event.
+type Stats struct {
+	Issued int64
+	CheckedIn int64
+}
+
+type GormRepository struct {
+	db *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
+	return &GormRepository{
+		db: db,
+		logger: logger,
+	}
+}
+
+func (r *GormRepository) FindEvent(ctx context.Context, publicID string) (*EventRow, error) {
+	var ev EventRow
+	if err := r.db.WithContext(ctx).
+		Table("events").
+		Select("id, public_id, organizer_id").
+		Where("public_id = ? AND deleted_at IS NULL", publicID).
+		Take(&ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("event_public_id", publicID).
+			Msg("select event failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &ev, nil
+}
+
+func (r *GormRepository) FindBooking(ctx context.Context, publicID string) (*BookingRow, error) {
+	var b BookingRow
+	if err := r.db.WithContext(ctx).
+		Table("bookings").
+		Select("id, public_id, user_id, status").
+		Where("public_id = ? AND deleted_at IS NULL", publicID).
+		Take(&b).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("booking_public_id", publicID).
+			Msg("select booking failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &b, nil
+}
+
+func (r *GormRepository) ListByBooking(ctx context.Context, bookingID uint) ([]View, error) {
+	var tickets []View
+	if err := r.views(ctx).
+		Where("t.booking_id = ?", bookingID).
+		Order("t.id").
+		Scan(&tickets).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("booking_id", bookingID).
+			Msg("select tickets failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return tickets, nil
+}
+
+func (r *GormRepository) FindByPublicID(ctx context.Context, publicID string) (*View, error) {
+	var t View
+	if err := r.views(ctx).
+		Where("t.public_id = ?", publicID).
+		Take(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("ticket_public_id", publicID).
+			Msg("select ticket failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &t, nil
+}
+
+// CheckIn records the scan of a ticket. The ticket row is locked so two
+// scanners reading the same ticket at once can not both admit it. A ticket
+// scanned before is returned together with ErrAlreadyCheckedIn.
+func (r *GormRepository) CheckIn(ctx context.Context, eventID uint, ticketPublicID string, scannerID uint) (*Ticket, error) {
+	var t Ticket
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ? AND event_id = ?", ticketPublicID, eventID).
+			Take(&t).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTicketNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("ticket_public_id", ticketPublicID).
+				Msg("lock/select ticket failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		var status string
+		if err := tx.Table("bookings").
+			Select("status").
+			Where("id = ?", t.BookingID).
+			Scan(&status).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", t.BookingID).
+				Msg("select booking status failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if status != bookingPaid {
+			return ErrTicketVoid
+		}
+
+		if t.CheckedInAt != nil {
+			return ErrAlreadyCheckedIn
+		}
+
+		now := time.Now()
+		if err := tx.Model(&t).Updates(map[string]any{
+			"checked_in_at": now,
+			"checked_in_by": scannerID,
+		}).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("ticket_id", t.ID).
+				Msg("update ticket check-in failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		t.CheckedInAt = &now
+		t.CheckedInBy = &scannerID
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrAlreadyCheckedIn) {
+		return nil, err
+	}
+	return &t, err
+}
+
+func (r *GormRepository) Stats(ctx context.Context, eventID uint) (*Stats, error) {
+	var st Stats
+	if err := r.db.WithContext(ctx).
+		Table("tickets AS t").
+		Select("COUNT(*) AS issued, COUNT(t.checked_in_at) AS checked_in").
+		Joins("JOIN bookings AS b ON b.id = t.booking_id").
+		Where("t.event_id = ? AND b.status = ?", eventID, bookingPaid).
+		Scan(&st).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("count tickets failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &st, nil
+}
+
+func (r *GormRepository) views(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("tickets AS t").
+		Select("t.*, e.public_id AS event_public_id, b.public_id AS booking_public_id, b.status AS booking_status, " +
+			"COALESCE(CONCAT(sec.name, ' ', sr.label, '-', s.label), '') AS seat_label").
+		Joins("JOIN events AS e ON e.id = t.event_id").
+		Joins("JOIN bookings AS b ON b.id = t.booking_id").
+		Joins("LEFT JOIN seats AS s ON s.id = t.seat_id").
+		Joins("LEFT JOIN seat_rows AS sr ON sr.id = s.row_id").
+		Joins("LEFT JOIN seat_sections AS sec ON sec.id = sr.section_id")
+}
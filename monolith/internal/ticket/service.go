@@ -0,0 +1,246 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonDTO "github.com/anrisys/quicket/internal/dto"
+	ticketDTO "github.com/anrisys/quicket/internal/ticket/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
+	"github.com/rs/zerolog"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	StatusValid = "valid"
+	StatusCheckedIn = "checked_in"
+	StatusVoid = "void"
+
+	qrSize = 256
+)
+
+type ServiceInterface interface {
+	ListByBooking(ctx context.Context, bookingPublicID, userPublicID string) ([]ticketDTO.TicketDTO, error)
+	QRCode(ctx context.Context, ticketPublicID, userPublicID string) ([]byte, error)
+	CheckIn(ctx context.Context, eventPublicID string, req *ticketDTO.CheckInRequest, userPublicID string) (*ticketDTO.CheckInDTO, error)
+	Stats(ctx context.Context, eventPublicID, userPublicID string) (*ticketDTO.CheckInStatsDTO, error)
+	VerificationKey() *ticketDTO.VerificationKeyDTO
+}
+
+type Service struct {
+	repo Repository
+	signer *Signer
+	users types.UserReader
+	logger zerolog.Logger
+}
+
+func NewService(repo Repository, signer *Signer, users types.UserReader, logger zerolog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		signer: signer,
+		users: users,
+		logger: logger,
+	}
+}
+
+func (s *Service) ListByBooking(ctx context.Context, bookingPublicID, userPublicID string) ([]ticketDTO.TicketDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket/service#listByBooking: %w", err)
+	}
+
+	b, err := s.repo.FindBooking(ctx, bookingPublicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	// Someone else's booking is reported as missing rather than forbidden
+	// so booking IDs can not be probed.
+	if b.UserID != uint(usr.ID) {
+		return nil, errs.NewErrNotFound("booking")
+	}
+
+	tickets, err := s.repo.ListByBooking(ctx, b.ID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	res := make([]ticketDTO.TicketDTO, 0, len(tickets))
+	for i := range tickets {
+		t, err := s.prepareTicketDTO(&tickets[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *t)
+	}
+	return res, nil
+}
+
+// QRCode renders the token of a ticket as a PNG QR code.
+func (s *Service) QRCode(ctx context.Context, ticketPublicID, userPublicID string) ([]byte, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket/service#qrCode: %w", err)
+	}
+
+	t, err := s.repo.FindByPublicID(ctx, ticketPublicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	if t.UserID != uint(usr.ID) {
+		return nil, errs.NewErrNotFound("ticket")
+	}
+	if t.BookingStatus != bookingPaid {
+		return nil, s.mapError(ErrTicketVoid)
+	}
+
+	token, err := s.signer.Sign(t)
+	if err != nil {
+		return nil, fmt.Errorf("ticket/service#qrCode: failed to sign ticket: %w", err)
+	}
+
+	png, err := qrcode.Encode(token, qrcode.Medium, qrSize)
+	if err != nil {
+		return nil, fmt.Errorf("ticket/service#qrCode: failed to render QR code: %w", err)
+	}
+	return png, nil
+}
+
+// CheckIn admits the holder of a ticket token to an event. The token is
+// verified before the database is touched, so forged or foreign tokens are
+// rejected cheaply.
+func (s *Service) CheckIn(ctx context.Context, eventPublicID string, req *ticketDTO.CheckInRequest, userPublicID string) (*ticketDTO.CheckInDTO, error) {
+	usr, ev, err := s.authorizeScanner(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.signer.Verify(req.Token)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	if claims.EventID != ev.PublicID {
+		return nil, errs.NewValidationError("ticket is for another event")
+	}
+
+	t, err := s.repo.CheckIn(ctx, ev.ID, claims.ID, uint(usr.ID))
+	if errors.Is(err, ErrAlreadyCheckedIn) {
+		s.logger.Warn().
+			Str("ticket_public_id", claims.ID).
+			Str("event_public_id", eventPublicID).
+			Time("checked_in_at", *t.CheckedInAt).
+			Msg("Duplicate ticket scan rejected")
+		return nil, errs.NewConflictError(
+			fmt.Sprintf("ticket was already checked in at %s", t.CheckedInAt.Format("2006-01-02 15:04:05 MST")))
+	}
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	view, err := s.repo.FindByPublicID(ctx, claims.ID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	s.logger.Info().
+		Str("ticket_public_id", claims.ID).
+		Str("event_public_id", eventPublicID).
+		Str("scanned_by", usr.PublicID).
+		Msg("Ticket checked in")
+
+	return &ticketDTO.CheckInDTO{
+		TicketID: view.PublicID,
+		BookingID: view.BookingPublicID,
+		Seat: view.SeatLabel,
+		CheckedInAt: *t.CheckedInAt,
+		CheckedInBy: usr.PublicID,
+	}, nil
+}
+
+func (s *Service) Stats(ctx context.Context, eventPublicID, userPublicID string) (*ticketDTO.CheckInStatsDTO, error) {
+	_, ev, err := s.authorizeScanner(ctx, eventPublicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.repo.Stats(ctx, ev.ID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return &ticketDTO.CheckInStatsDTO{
+		EventID: ev.PublicID,
+		Issued: st.Issued,
+		CheckedIn: st.CheckedIn,
+		Remaining: st.Issued - st.CheckedIn,
+	}, nil
+}
+
+func (s *Service) VerificationKey() *ticketDTO.VerificationKeyDTO {
+	return &ticketDTO.VerificationKeyDTO{
+		Algorithm: "EdDSA",
+		PublicKey: s.signer.PublicKey(),
+	}
+}
+
+// authorizeScanner lets admins and staff scan at any event, and organizers
+// only at their own events.
+func (s *Service) authorizeScanner(ctx context.Context, eventPublicID, userPublicID string) (*commonDTO.UserDTO, *EventRow, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ticket/service#authorizeScanner: %w", err)
+	}
+
+	ev, err := s.repo.FindEvent(ctx, eventPublicID)
+	if err != nil {
+		return nil, nil, s.mapError(err)
+	}
+
+	if usr.Role == "organizer" && ev.OrganizerID != uint(usr.ID) {
+		return nil, nil, errs.NewForbiddenError("only the organizer of this event can check in its tickets")
+	}
+	return usr, ev, nil
+}
+
+func (s *Service) mapError(err error) error {
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		return errs.NewErrNotFound("event")
+	case errors.Is(err, ErrBookingNotFound):
+		return errs.NewErrNotFound("booking")
+	case errors.Is(err, ErrTicketNotFound):
+		return errs.NewErrNotFound("ticket")
+	case errors.Is(err, ErrInvalidToken):
+		return errs.NewValidationError("invalid ticket token")
+	case errors.Is(err, ErrTicketVoid):
+		return errs.NewConflictError("ticket is no longer valid")
+	default:
+		return fmt.Errorf("ticket service: %w", err)
+	}
+}
+
+func (s *Service) prepareTicketDTO(t *View) (*ticketDTO.TicketDTO, error) {
+	res := &ticketDTO.TicketDTO{
+		PublicID: t.PublicID,
+		EventID: t.EventPublicID,
+		BookingID: t.BookingPublicID,
+		Seat: t.SeatLabel,
+		CheckedInAt: t.CheckedInAt,
+	}
+
+	switch {
+	case t.BookingStatus != bookingPaid:
+		res.Status = StatusVoid
+	case t.CheckedInAt != nil:
+		res.Status = StatusCheckedIn
+	default:
+		res.Status = StatusValid
+		token, err := s.signer.Sign(t)
+		if err != nil {
+			return nil, fmt.Errorf("ticket/service#prepareTicketDTO: failed to sign ticket: %w", err)
+		}
+		res.Token = token
+	}
+	return res, nil
+}
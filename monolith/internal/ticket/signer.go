@@ -0,0 +1,70 @@
+package ticket
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the payload of a ticket token.
+type Claims struct {
+	EventID string `json:"evt"`
+	Seat string `json:"seat,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Signer signs ticket tokens. Tokens are EdDSA JWTs, so a scanner holding
+// only the public key can verify them without calling the API.
+type Signer struct {
+	private ed25519.PrivateKey
+	public ed25519.PublicKey
+}
+
+func NewSigner(cfg *config.AppConfig) (*Signer, error) {
+	seed, err := base64.StdEncoding.DecodeString(cfg.Ticket.SigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("ticket signer: invalid signing key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ticket signer: signing key must be %d bytes", ed25519.SeedSize)
+	}
+
+	private := ed25519.NewKeyFromSeed(seed)
+	return &Signer{
+		private: private,
+		public: private.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// Sign returns the token of a ticket. The token only depends on the stored
+// ticket, so it is the same every time it is rendered.
+func (s *Signer) Sign(t *View) (string, error) {
+	claims := Claims{
+		EventID: t.EventPublicID,
+		Seat: t.SeatLabel,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: t.PublicID,
+			IssuedAt: jwt.NewNumericDate(t.CreatedAt),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(s.private)
+}
+
+func (s *Signer) Verify(token string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return s.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
+	if err != nil || claims.ID == "" || claims.EventID == "" {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// PublicKey returns the base64 encoded key scanners verify tokens with.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.public)
+}
@@ -0,0 +1,76 @@
+package ticket
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, seed byte) *Signer {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(seed)), 32)))
+	s, err := NewSigner(&config.AppConfig{Ticket: config.TicketConfig{SigningKey: key}})
+	require.NoError(t, err)
+	return s
+}
+
+func testView() *View {
+	return &View{
+		Ticket: Ticket{
+			PublicID: "ticket-1",
+			CreatedAt: time.Now().Add(-time.Hour),
+		},
+		EventPublicID: "event-1",
+		SeatLabel: "Floor A-1",
+	}
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	s := newTestSigner(t, 'a')
+
+	token, err := s.Sign(testView())
+	require.NoError(t, err)
+
+	claims, err := s.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket-1", claims.ID)
+	assert.Equal(t, "event-1", claims.EventID)
+	assert.Equal(t, "Floor A-1", claims.Seat)
+
+	again, err := s.Sign(testView())
+	require.NoError(t, err)
+	assert.Equal(t, token, again, "token should be stable for the same ticket")
+}
+
+func TestSigner_VerifyRejectsTamperedToken(t *testing.T) {
+	s := newTestSigner(t, 'a')
+
+	token, err := s.Sign(testView())
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	forged, err := newTestSigner(t, 'b').Sign(&View{
+		Ticket: Ticket{PublicID: "ticket-2", CreatedAt: time.Now()},
+		EventPublicID: "event-1",
+	})
+	require.NoError(t, err)
+	forgedParts := strings.Split(forged, ".")
+
+	_, err = s.Verify(parts[0] + "." + forgedParts[1] + "." + parts[2])
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = s.Verify(forged)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = s.Verify("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewSigner_InvalidKey(t *testing.T) {
+	_, err := NewSigner(&config.AppConfig{Ticket: config.TicketConfig{SigningKey: "c2hvcnQ="}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package ticket
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	NewGormRepository,
+	NewSigner,
+	NewService,
+	NewHandler,
+	wire.Bind(new(Repository), new(*GormRepository)),
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
@@ -9,7 +9,7 @@ type User struct {
 	PublicID 	string `gorm:"column:public_id;type:char(36);uniqueIndex"`
 	Email    	string `gorm:"column:email;uniqueIndex"`
 	Password 	string `gorm:"column:password;size:255"`
-	Role     	string `gorm:"column:role;type:ENUM('user', 'organizer', 'admin');default:'user'"`
+	Role     	string `gorm:"column:role;type:ENUM('user', 'organizer', 'staff', 'admin');default:'user'"`
 }
 
 func (u *User) TableName() string {
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type VenueDTO struct {
+	PublicID  string    `json:"public_id"`
+	Name      string    `json:"name" example:"Jakarta International Expo"`
+	Address   string    `json:"address"`
+	TimeZone  string    `json:"time_zone" example:"Asia/Jakarta"`
+	Capacity  uint64    `json:"capacity" example:"5000"`
+	Latitude  *float64  `json:"latitude,omitempty" example:"-6.1466"`
+	Longitude *float64  `json:"longitude,omitempty" example:"106.8456"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type VenueListDTO struct {
+	Venues     []VenueDTO `json:"venues"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
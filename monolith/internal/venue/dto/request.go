@@ -0,0 +1,30 @@
+package dto
+
+// CreateVenueRequest creates a venue. TimeZone is an IANA zone name such as
+// Asia/Jakarta. Latitude and Longitude are given together or not at all.
+type CreateVenueRequest struct {
+	Name      string   `json:"name" binding:"required,min=3,max=256"`
+	Address   string   `json:"address" binding:"required,max=512"`
+	TimeZone  string   `json:"time_zone" binding:"required,timezone"`
+	Capacity  uint64   `json:"capacity" binding:"required,gt=0"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// UpdateVenueRequest holds the fields of a venue to change; omitted fields
+// are left as they are.
+type UpdateVenueRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=3,max=256"`
+	Address   *string  `json:"address" binding:"omitempty,max=512"`
+	TimeZone  *string  `json:"time_zone" binding:"omitempty,timezone"`
+	Capacity  *uint64  `json:"capacity" binding:"omitempty,gt=0"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// ListVenuesQuery pages the venue listing, optionally of one owner.
+type ListVenuesQuery struct {
+	Owner  string `form:"owner"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+type ResponseSuccess struct {
+	Code    string `json:"code" example:"SUCCESS"`
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type VenueSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Venue           VenueDTO `json:"venue"`
+}
+
+type ListVenuesSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	VenueListDTO    `json:",inline"`
+}
@@ -0,0 +1,9 @@
+package venue
+
+import "errors"
+
+var (
+	ErrVenueNotFound = errors.New("venue not found")
+	ErrVenueInUse = errors.New("venue has events")
+	ErrDB = errors.New("database error")
+)
\ No newline at end of file
@@ -0,0 +1,206 @@
+package venue
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/venue/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	srv ServiceInterface
+	logger zerolog.Logger
+}
+
+func NewHandler(srv ServiceInterface, logger zerolog.Logger) *Handler {
+	return &Handler{
+		srv: srv,
+		logger: logger,
+	}
+}
+
+// Create godoc
+// @Summary Create venue
+// @Description Create a venue events can be held at (Admin/Organizer only). Event times at the venue are read and validated in its time zone.
+// @Tags Venues
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateVenueRequest true "Venue data"
+// @Success 201 {object} dto.VenueSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden (role restriction)"
+// @Router /api/v1/venues [post]
+func (h *Handler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var req dto.CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid venue data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	v, err := h.srv.Create(ctx, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.VenueSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue created successfully",
+		},
+		Venue: *v,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Get godoc
+// @Summary Get venue
+// @Description Get a venue
+// @Tags Venues
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Success 200 {object} dto.VenueSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Router /api/v1/venues/{publicID} [get]
+func (h *Handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	v, err := h.srv.Get(ctx, c.Param("publicID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.VenueSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue retrieved successfully",
+		},
+		Venue: *v,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary List venues
+// @Description Page through venues in creation order; pass next_cursor back as cursor for the next page.
+// @Tags Venues
+// @Security BearerAuth
+// @Produce json
+// @Param owner query string false "Owner public ID"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} dto.ListVenuesSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Router /api/v1/venues [get]
+func (h *Handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var query dto.ListVenuesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErr := errs.NewValidationError("Invalid query parameters", err)
+		c.Error(validationErr)
+		return
+	}
+
+	list, err := h.srv.List(ctx, &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListVenuesSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venues retrieved successfully",
+		},
+		VenueListDTO: *list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update godoc
+// @Summary Update venue
+// @Description Change a venue (venue owner or admin only). The time zone of a venue with events can not change.
+// @Tags Venues
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Param request body dto.UpdateVenueRequest true "Fields to change"
+// @Success 200 {object} dto.VenueSuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Failure 409 {object} errs.ErrorResponse "Venue has events"
+// @Router /api/v1/venues/{publicID} [patch]
+func (h *Handler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	var req dto.UpdateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid venue data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	v, err := h.srv.Update(ctx, c.Param("publicID"), &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.VenueSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Venue updated successfully",
+		},
+		Venue: *v,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Delete godoc
+// @Summary Delete venue
+// @Description Delete a venue no event is held at (venue owner or admin only)
+// @Tags Venues
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Venue public ID"
+// @Success 200 {object} dto.ResponseSuccess
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "Venue not found"
+// @Failure 409 {object} errs.ErrorResponse "Venue has events"
+// @Router /api/v1/venues/{publicID} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	if err := h.srv.Delete(ctx, c.Param("publicID"), userPublicID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ResponseSuccess{
+		Code:    "SUCCESS",
+		Message: "Venue deleted successfully",
+	})
+}
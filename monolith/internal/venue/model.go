@@ -0,0 +1,24 @@
+package venue
+
+import "time"
+
+// Venue is a place events are held at. TimeZone is an IANA zone name; the
+// times of its events are stored in UTC and read in that zone. Capacity is
+// what an event at the venue seats unless it says otherwise.
+type Venue struct {
+	ID 				uint 		`gorm:"primarykey"`
+	PublicID 		string 		`gorm:"column:public_id;type:char(36);uniqueIndex"`
+	Name 			string 		`gorm:"column:name;size:256;not null"`
+	Address 		string 		`gorm:"column:address;size:512;not null"`
+	TimeZone 		string 		`gorm:"column:time_zone;size:64;not null"`
+	Capacity 		uint64 		`gorm:"column:capacity;not null"`
+	Latitude 		*float64 	`gorm:"column:latitude"`
+	Longitude 		*float64 	`gorm:"column:longitude"`
+	OwnerID 		uint 		`gorm:"column:owner_id;not null;index"`
+	CreatedAt 		time.Time
+	UpdatedAt 		time.Time
+}
+
+func (v *Venue) TableName() string {
+	return "venues"
+}
@@ -0,0 +1,202 @@
+package venue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Create(ctx context.Context, venue *Venue) error
+	FindByPublicID(ctx context.Context, publicID string) (*Venue, error)
+	List(ctx context.Context, filter Filter) ([]Venue, error)
+	Update(ctx context.Context, venueID uint, update Update) (*Venue, error)
+	Delete(ctx context.Context, venueID uint) error
+}
+
+// Filter narrows the venues returned by List, which orders them by id. A
+// zero AfterID starts from the first venue.
+type Filter struct {
+	OwnerID uint
+	AfterID uint
+	Limit int
+}
+
+// Update holds the fields of a venue to change; nil fields are left as they
+// are. Latitude and Longitude change together.
+type Update struct {
+	Name *string
+	Address *string
+	TimeZone *string
+	Capacity *uint64
+	Latitude *float64
+	Longitude *float64
+}
+
+type GormRepository struct {
+	db *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
+	return &GormRepository{
+		db: db,
+		logger: logger,
+	}
+}
+
+func (r *GormRepository) Create(ctx context.Context, venue *Venue) error {
+	if err := r.db.WithContext(ctx).Create(venue).Error; err != nil {
+		r.logger.Error().Err(err).
+			Str("venue_public_id", venue.PublicID).
+			Msg("insert venue failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+func (r *GormRepository) FindByPublicID(ctx context.Context, publicID string) (*Venue, error) {
+	var venue Venue
+	if err := r.db.WithContext(ctx).Take(&venue, "public_id = ?", publicID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVenueNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("venue_public_id", publicID).
+			Msg("select venue failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &venue, nil
+}
+
+func (r *GormRepository) List(ctx context.Context, filter Filter) ([]Venue, error) {
+	q := r.db.WithContext(ctx).Model(&Venue{})
+	if filter.OwnerID > 0 {
+		q = q.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("id > ?", filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var venues []Venue
+	if err := q.Order("id").Find(&venues).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("owner_id", filter.OwnerID).
+			Msg("list venues failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return venues, nil
+}
+
+// Update applies update to the venue. The time zone of a venue that has
+// events is fixed, since their times would silently move on the clock.
+func (r *GormRepository) Update(ctx context.Context, venueID uint, update Update) (*Venue, error) {
+	var venue Venue
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockVenue(tx, venueID, &venue); err != nil {
+			return err
+		}
+
+		if update.TimeZone != nil && *update.TimeZone != venue.TimeZone {
+			inUse, err := r.inUse(tx, venueID)
+			if err != nil {
+				return err
+			}
+			if inUse {
+				return ErrVenueInUse
+			}
+			venue.TimeZone = *update.TimeZone
+		}
+		if update.Name != nil {
+			venue.Name = *update.Name
+		}
+		if update.Address != nil {
+			venue.Address = *update.Address
+		}
+		if update.Capacity != nil {
+			venue.Capacity = *update.Capacity
+		}
+		if update.Latitude != nil && update.Longitude != nil {
+			venue.Latitude = update.Latitude
+			venue.Longitude = update.Longitude
+		}
+
+		if err := tx.Save(&venue).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Msg("update venue failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// Delete removes the venue. A venue that events or event series, deleted
+// ones included, still point at is kept.
+func (r *GormRepository) Delete(ctx context.Context, venueID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var venue Venue
+		if err := r.lockVenue(tx, venueID, &venue); err != nil {
+			return err
+		}
+
+		inUse, err := r.inUse(tx, venueID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrVenueInUse
+		}
+
+		if err := tx.Delete(&venue).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Msg("delete venue failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+func (r *GormRepository) lockVenue(tx *gorm.DB, venueID uint, venue *Venue) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Take(venue, venueID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVenueNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("venue_id", venueID).
+			Msg("lock/select venue failed")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
+// inUse reports whether any event or event series points at the venue.
+func (r *GormRepository) inUse(tx *gorm.DB, venueID uint) (bool, error) {
+	for _, table := range []string{"events", "event_series"} {
+		var n int64
+		if err := tx.Table(table).Where("venue_id = ?", venueID).Count(&n).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("venue_id", venueID).
+				Str("table", table).
+				Msg("count venue references failed")
+			return false, fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,224 @@
+package venue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	venueDTO "github.com/anrisys/quicket/internal/venue/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+type ServiceInterface interface {
+	Create(ctx context.Context, req *venueDTO.CreateVenueRequest, userPublicID string) (*venueDTO.VenueDTO, error)
+	Get(ctx context.Context, publicID string) (*venueDTO.VenueDTO, error)
+	List(ctx context.Context, query *venueDTO.ListVenuesQuery) (*venueDTO.VenueListDTO, error)
+	Update(ctx context.Context, publicID string, req *venueDTO.UpdateVenueRequest, userPublicID string) (*venueDTO.VenueDTO, error)
+	Delete(ctx context.Context, publicID string, userPublicID string) error
+}
+
+const defaultListLimit = 20
+
+type Service struct {
+	repo Repository
+	users types.UserReader
+	logger zerolog.Logger
+}
+
+func NewService(repo Repository, users types.UserReader, logger zerolog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		users: users,
+		logger: logger,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, req *venueDTO.CreateVenueRequest, userPublicID string) (*venueDTO.VenueDTO, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("venue/service#create: %w", err)
+	}
+
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public ID: %w", err)
+	}
+
+	venue := &Venue{
+		PublicID: publicID,
+		Name: req.Name,
+		Address: req.Address,
+		TimeZone: req.TimeZone,
+		Capacity: req.Capacity,
+		Latitude: req.Latitude,
+		Longitude: req.Longitude,
+		OwnerID: uint(usr.ID),
+	}
+	if err := s.repo.Create(ctx, venue); err != nil {
+		return nil, s.mapError(err)
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", venue.PublicID).
+		Str("user_id", userPublicID).
+		Str("time_zone", venue.TimeZone).
+		Msg("Venue created")
+	return prepareVenueDTO(venue), nil
+}
+
+func (s *Service) Get(ctx context.Context, publicID string) (*venueDTO.VenueDTO, error) {
+	venue, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	return prepareVenueDTO(venue), nil
+}
+
+func (s *Service) List(ctx context.Context, query *venueDTO.ListVenuesQuery) (*venueDTO.VenueListDTO, error) {
+	afterID, err := util.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, errs.NewValidationError("Invalid cursor")
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	list := &venueDTO.VenueListDTO{
+		Venues: make([]venueDTO.VenueDTO, 0, limit),
+	}
+
+	filter := Filter{AfterID: afterID}
+	if query.Owner != "" {
+		usr, err := s.users.FindUserByPublicID(ctx, query.Owner)
+		if errors.Is(err, errs.ErrNotFound) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("venue service#list: %w", err)
+		}
+		filter.OwnerID = uint(usr.ID)
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+	venues, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	if len(venues) > limit {
+		venues = venues[:limit]
+		list.NextCursor = util.EncodeCursor(venues[limit-1].ID)
+	}
+	for i := range venues {
+		list.Venues = append(list.Venues, *prepareVenueDTO(&venues[i]))
+	}
+	return list, nil
+}
+
+func (s *Service) Update(ctx context.Context, publicID string, req *venueDTO.UpdateVenueRequest, userPublicID string) (*venueDTO.VenueDTO, error) {
+	venue, err := s.findManagedVenue(ctx, publicID, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, venue.ID, Update{
+		Name: req.Name,
+		Address: req.Address,
+		TimeZone: req.TimeZone,
+		Capacity: req.Capacity,
+		Latitude: req.Latitude,
+		Longitude: req.Longitude,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", publicID).
+		Str("user_id", userPublicID).
+		Msg("Venue updated")
+	return prepareVenueDTO(updated), nil
+}
+
+// Delete removes a venue no event is held at.
+func (s *Service) Delete(ctx context.Context, publicID string, userPublicID string) error {
+	venue, err := s.findManagedVenue(ctx, publicID, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, venue.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	s.logger.Info().
+		Str("venue_public_id", publicID).
+		Str("user_id", userPublicID).
+		Msg("Venue deleted")
+	return nil
+}
+
+// findManagedVenue loads the venue and makes sure the user is its owner or
+// an admin.
+func (s *Service) findManagedVenue(ctx context.Context, publicID, userPublicID string) (*Venue, error) {
+	usr, err := s.users.FindUserByPublicID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("venue/service#managed: %w", err)
+	}
+
+	venue, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	if usr.Role != "admin" && venue.OwnerID != uint(usr.ID) {
+		return nil, errs.NewForbiddenError("only the owner of this venue can manage it")
+	}
+	return venue, nil
+}
+
+func (s *Service) mapError(err error) error {
+	switch {
+	case errors.Is(err, ErrVenueNotFound):
+		return errs.NewErrNotFound("venue")
+	case errors.Is(err, ErrVenueInUse):
+		return errs.NewConflictError("venue has events; its time zone can not change and it can not be deleted")
+	default:
+		return fmt.Errorf("venue service: %w", err)
+	}
+}
+
+func validateCoordinates(latitude, longitude *float64) error {
+	if (latitude == nil) != (longitude == nil) {
+		return errs.NewValidationError("latitude and longitude must be given together")
+	}
+	return nil
+}
+
+func prepareVenueDTO(venue *Venue) *venueDTO.VenueDTO {
+	return &venueDTO.VenueDTO{
+		PublicID: venue.PublicID,
+		Name: venue.Name,
+		Address: venue.Address,
+		TimeZone: venue.TimeZone,
+		Capacity: venue.Capacity,
+		Latitude: venue.Latitude,
+		Longitude: venue.Longitude,
+		CreatedAt: venue.CreatedAt,
+		UpdatedAt: venue.UpdatedAt,
+	}
+}
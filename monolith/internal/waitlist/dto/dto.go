@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/anrisys/quicket/pkg/money"
+)
+
+// EntryDTO is a waitlist entry. Position is the place in line among waiting
+// entries and is zero once the entry has been offered seats.
+type EntryDTO struct {
+	PublicID       string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	TicketTypeID   string     `json:"ticket_type_id,omitempty"`
+	Seats          uint       `json:"seats"`
+	Status         string     `json:"status"`
+	Position       uint       `json:"position"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type AcceptedBookingDTO struct {
+	PublicID   string      `json:"id"`
+	Seats      uint        `json:"seats"`
+	TotalPrice money.Money `json:"total_price"`
+	Status     string      `json:"status"`
+	ExpiredAt  time.Time   `json:"expired_at"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type JoinWaitlistRequest struct {
+	Seats        uint   `json:"seats" binding:"required,gt=0"`
+	TicketTypeID string `json:"ticket_type_id" binding:"omitempty,max=36"`
+}
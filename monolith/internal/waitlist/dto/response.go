@@ -0,0 +1,21 @@
+package dto
+
+type ResponseSuccess struct {
+	Code    string `json:"code" example:"SUCCESS"`
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type EntrySuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Entry           EntryDTO `json:"entry"`
+}
+
+type ListEntriesSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Entries         []EntryDTO `json:"entries"`
+}
+
+type AcceptOfferSuccessResponse struct {
+	ResponseSuccess `json:",inline"`
+	Booking         AcceptedBookingDTO `json:"booking"`
+}
@@ -0,0 +1,17 @@
+package waitlist
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrSeatMapEvent = errors.New("event uses a seat map")
+	ErrSeatsAvailable = errors.New("requested seats are available")
+	ErrAlreadyWaitlisted = errors.New("user is already on the waitlist of this event")
+	ErrTicketTypeRequired = errors.New("ticket type is required for this event")
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrExceedsMaxPerOrder = errors.New("seats exceed the ticket type per-order limit")
+	ErrEntryNotFound = errors.New("waitlist entry not found")
+	ErrNotEntryOwner = errors.New("waitlist entry belongs to another user")
+	ErrNoActiveOffer = errors.New("waitlist entry has no active offer")
+	ErrDB = errors.New("database error")
+)
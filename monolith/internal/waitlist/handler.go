@@ -0,0 +1,131 @@
+package waitlist
+
+import (
+	"net/http"
+
+	"github.com/anrisys/quicket/internal/waitlist/dto"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	srv ServiceInterface
+	logger zerolog.Logger
+}
+
+func NewHandler(srv ServiceInterface, logger zerolog.Logger) *Handler {
+	return &Handler{
+		srv: srv,
+		logger: logger,
+	}
+}
+
+// Join godoc
+// @Summary Join event waitlist
+// @Description Join the FIFO waitlist of a sold-out event. Seats freed later are offered to the oldest entry whose request fits.
+// @Tags Waitlist
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param publicID path string true "Event public ID"
+// @Param request body dto.JoinWaitlistRequest true "Requested seats"
+// @Success 201 {object} dto.EntrySuccessResponse
+// @Failure 400 {object} errs.ErrorResponse "Validation error"
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errs.ErrorResponse "Event not found"
+// @Failure 409 {object} errs.ErrorResponse "Already waitlisted or seats available"
+// @Router /api/v1/events/{publicID}/waitlist [post]
+func (h *Handler) Join(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	eventPublicID := c.Param("publicID")
+
+	var req dto.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := errs.NewValidationError("Invalid waitlist data", err)
+		c.Error(validationErr)
+		return
+	}
+
+	entry, err := h.srv.Join(ctx, eventPublicID, &req, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.EntrySuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Joined waitlist successfully",
+		},
+		Entry: *entry,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// List godoc
+// @Summary List my waitlist entries
+// @Description List the authenticated user's waiting and offered entries with their position in line
+// @Tags Waitlist
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ListEntriesSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Router /api/v1/waitlist [get]
+func (h *Handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+
+	entries, err := h.srv.ListMine(ctx, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.ListEntriesSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Waitlist entries retrieved successfully",
+		},
+		Entries: entries,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Accept godoc
+// @Summary Accept waitlist offer
+// @Description Turn an active waitlist offer into a pending booking
+// @Tags Waitlist
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "Waitlist entry public ID"
+// @Success 201 {object} dto.AcceptOfferSuccessResponse
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Entry belongs to another user"
+// @Failure 404 {object} errs.ErrorResponse "Entry not found"
+// @Failure 409 {object} errs.ErrorResponse "No active offer"
+// @Router /api/v1/waitlist/{publicID}/accept [post]
+func (h *Handler) Accept(c *gin.Context) {
+	ctx := c.Request.Context()
+	userPublicID := c.GetString("publicID")
+	entryPublicID := c.Param("publicID")
+
+	booking, err := h.srv.Accept(ctx, entryPublicID, userPublicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dto.AcceptOfferSuccessResponse{
+		ResponseSuccess: dto.ResponseSuccess{
+			Code:    "SUCCESS",
+			Message: "Waitlist offer accepted, booking created",
+		},
+		Booking: *booking,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,30 @@
+package waitlist
+
+import "time"
+
+const (
+	StatusWaiting = "waiting"
+	StatusOffered = "offered"
+	StatusConverted = "converted"
+	StatusLapsed = "lapsed"
+)
+
+// Entry is a user's place in the waitlist of an event. While offered, the
+// requested seats are held for the user until OfferExpiresAt.
+type Entry struct {
+	ID uint `gorm:"primarykey"`
+	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex"`
+	EventID uint `gorm:"column:event_id;not null"`
+	TicketTypeID *uint `gorm:"column:ticket_type_id"`
+	UserID uint `gorm:"column:user_id;not null"`
+	Seats uint `gorm:"column:seats;not null"`
+	Status string `gorm:"column:status;type:ENUM('waiting', 'offered', 'converted', 'lapsed');default:'waiting'"`
+	BookingID *uint `gorm:"column:booking_id"`
+	OfferExpiresAt *time.Time `gorm:"column:offer_expires_at"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (e *Entry) TableName() string {
+	return "waitlist_entries"
+}
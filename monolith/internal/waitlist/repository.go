@@ -0,0 +1,509 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anrisys/quicket/internal/booking"
+	"github.com/anrisys/quicket/pkg/money"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Join(ctx context.Context, e *Entry, ticketTypePublicID string) error
+	Position(ctx context.Context, e *Entry) (uint, error)
+	ListActiveByUser(ctx context.Context, userID uint) ([]EntryView, error)
+	Promote(ctx context.Context, eventID uint, now time.Time, offerTTL time.Duration) ([]Entry, error)
+	LapseOffers(ctx context.Context, now time.Time, limit int) ([]Entry, error)
+	EventsWithWaiting(ctx context.Context) ([]uint, error)
+	Accept(ctx context.Context, publicID string, userID uint, now time.Time, b *BookingRow) error
+}
+
+// EntryView is an entry joined with the public IDs of its event and ticket
+// type and its place in line.
+type EntryView struct {
+	Entry
+	EventPublicID string
+	TicketTypePublicID *string
+	Position uint
+}
+
+// BookingRow is the pending booking an accepted offer turns into.
+type BookingRow struct {
+	ID uint
+	PublicID string
+	EventID uint
+	TicketTypeID *uint
+	UserID uint
+	Seats uint
+	TotalPrice money.Money
+	Currency money.Currency
+	Status string
+	ExpiredAt time.Time
+}
+
+type eventRow struct {
+	ID uint
+	AvailableSeats uint64
+}
+
+type ticketTypeRow struct {
+	ID uint
+	Price money.Money
+	Currency money.Currency
+	Available uint64
+	MaxPerOrder uint
+}
+
+const entryViewColumns = "waitlist_entries.*, events.public_id AS event_public_id, " +
+	"ticket_types.public_id AS ticket_type_public_id, " +
+	"(SELECT COUNT(*) FROM waitlist_entries ahead WHERE ahead.event_id = waitlist_entries.event_id " +
+	"AND ahead.status = 'waiting' AND ahead.id <= waitlist_entries.id) AS position"
+
+type GormRepository struct {
+	db *gorm.DB
+	logger zerolog.Logger
+}
+
+func NewGormRepository(db *gorm.DB, logger zerolog.Logger) *GormRepository {
+	return &GormRepository{
+		db: db,
+		logger: logger,
+	}
+}
+
+// Join appends e to the waitlist of its event. The event row is locked so the
+// check that the seats are really gone and the insert can not race with a
+// promotion.
+func (r *GormRepository) Join(ctx context.Context, e *Entry, ticketTypePublicID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEvent(tx, e.EventID)
+		if err != nil {
+			return err
+		}
+
+		var seats int64
+		if err := tx.Table("seats").Where("event_id = ?", e.EventID).Count(&seats).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", e.EventID).
+				Msg("count seats failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if seats > 0 {
+			return ErrSeatMapEvent
+		}
+
+		tt, err := r.findTicketType(tx, e.EventID, ticketTypePublicID)
+		if err != nil {
+			return err
+		}
+		if tt != nil {
+			if e.Seats > tt.MaxPerOrder {
+				return ErrExceedsMaxPerOrder
+			}
+			e.TicketTypeID = &tt.ID
+		}
+
+		var active int64
+		if err := tx.Model(&Entry{}).
+			Where("event_id = ? AND user_id = ? AND status IN ?", e.EventID, e.UserID, []string{StatusWaiting, StatusOffered}).
+			Count(&active).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", e.EventID).
+				Uint("user_id", e.UserID).
+				Msg("count active entries failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if active > 0 {
+			return ErrAlreadyWaitlisted
+		}
+
+		var waiting int64
+		if err := tx.Model(&Entry{}).
+			Where("event_id = ? AND status = ?", e.EventID, StatusWaiting).
+			Count(&waiting).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", e.EventID).
+				Msg("count waiting entries failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		fits := ev.AvailableSeats >= uint64(e.Seats) && (tt == nil || tt.Available >= uint64(e.Seats))
+		if waiting == 0 && fits {
+			return ErrSeatsAvailable
+		}
+
+		if err := tx.Create(e).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", e.EventID).
+				Uint("user_id", e.UserID).
+				Msg("insert waitlist entry failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+// Position returns the place of a waiting entry in its event's line,
+// starting at 1.
+func (r *GormRepository) Position(ctx context.Context, e *Entry) (uint, error) {
+	var ahead int64
+	if err := r.db.WithContext(ctx).
+		Model(&Entry{}).
+		Where("event_id = ? AND status = ? AND id <= ?", e.EventID, StatusWaiting, e.ID).
+		Count(&ahead).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("entry_id", e.ID).
+			Msg("count entries ahead failed")
+		return 0, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return uint(ahead), nil
+}
+
+func (r *GormRepository) ListActiveByUser(ctx context.Context, userID uint) ([]EntryView, error) {
+	var views []EntryView
+	if err := r.db.WithContext(ctx).
+		Table("waitlist_entries").
+		Select(entryViewColumns).
+		Joins("JOIN events ON events.id = waitlist_entries.event_id").
+		Joins("LEFT JOIN ticket_types ON ticket_types.id = waitlist_entries.ticket_type_id").
+		Where("waitlist_entries.user_id = ? AND waitlist_entries.status IN ?", userID, []string{StatusWaiting, StatusOffered}).
+		Order("waitlist_entries.id").
+		Find(&views).Error; err != nil {
+		r.logger.Error().Err(err).
+			Uint("user_id", userID).
+			Msg("list waitlist entries failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return views, nil
+}
+
+// Promote offers seats to the waiting entries of an event in the order they
+// joined, holding the seats for offerTTL. Entries whose request does not fit
+// are passed over and keep their place. Rows are locked in the order event,
+// ticket types, the same order bookings use.
+func (r *GormRepository) Promote(ctx context.Context, eventID uint, now time.Time, offerTTL time.Duration) ([]Entry, error) {
+	var offered []Entry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ev, err := r.lockEvent(tx, eventID)
+		if err != nil {
+			return err
+		}
+		if ev.AvailableSeats == 0 {
+			return nil
+		}
+
+		var waiting []Entry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ? AND status = ?", eventID, StatusWaiting).
+			Order("id").
+			Find(&waiting).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("lock/select waiting entries failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if len(waiting) == 0 {
+			return nil
+		}
+
+		tierAvailable, err := r.lockTicketTypes(tx, waiting)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := now.Add(offerTTL)
+		offered = entriesThatFit(waiting, ev.AvailableSeats, tierAvailable)
+		for i := range offered {
+			offered[i].Status = StatusOffered
+			offered[i].OfferExpiresAt = &expiresAt
+		}
+		if len(offered) == 0 {
+			return nil
+		}
+
+		for _, e := range offered {
+			if err := tx.Model(&Entry{ID: e.ID}).
+				Updates(map[string]any{"status": StatusOffered, "offer_expires_at": expiresAt}).Error; err != nil {
+				r.logger.Error().Err(err).
+					Uint("entry_id", e.ID).
+					Msg("mark entry offered failed")
+				return fmt.Errorf("%w: %v", ErrDB, err)
+			}
+		}
+
+		return moveSeats(tx, offered, "-")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offered, nil
+}
+
+// entriesThatFit returns the waiting entries, oldest first, that the
+// available seats can serve, passing over those that do not fit. It follows
+// booking.RequestsThatFit, which direct bookings are checked against.
+func entriesThatFit(waiting []Entry, available uint64, tierAvailable map[uint]uint64) []Entry {
+	requests := make([]booking.WaitingRequest, 0, len(waiting))
+	byID := make(map[uint]Entry, len(waiting))
+	for _, e := range waiting {
+		requests = append(requests, booking.WaitingRequest{ID: e.ID, TicketTypeID: e.TicketTypeID, Seats: e.Seats})
+		byID[e.ID] = e
+	}
+
+	var fits []Entry
+	for _, w := range booking.RequestsThatFit(requests, available, tierAvailable) {
+		fits = append(fits, byID[w.ID])
+	}
+	return fits
+}
+
+// LapseOffers closes up to limit offers whose hold has passed and gives the
+// held seats back. Offers locked by a concurrent accept are skipped.
+func (r *GormRepository) LapseOffers(ctx context.Context, now time.Time, limit int) ([]Entry, error) {
+	var lapsed []Entry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND offer_expires_at <= ?", StatusOffered, now).
+			Order("offer_expires_at").
+			Limit(limit).
+			Find(&lapsed).Error; err != nil {
+			r.logger.Error().Err(err).Msg("lock/select lapsed offers failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if len(lapsed) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(lapsed))
+		for _, e := range lapsed {
+			ids = append(ids, e.ID)
+		}
+		if err := tx.Model(&Entry{}).
+			Where("id IN ?", ids).
+			Update("status", StatusLapsed).Error; err != nil {
+			r.logger.Error().Err(err).
+				Int("entries", len(ids)).
+				Msg("mark offers lapsed failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		return moveSeats(tx, lapsed, "+")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lapsed, nil
+}
+
+func (r *GormRepository) EventsWithWaiting(ctx context.Context) ([]uint, error) {
+	var eventIDs []uint
+	if err := r.db.WithContext(ctx).
+		Model(&Entry{}).
+		Distinct("event_id").
+		Where("status = ?", StatusWaiting).
+		Pluck("event_id", &eventIDs).Error; err != nil {
+		r.logger.Error().Err(err).Msg("select events with waiting entries failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return eventIDs, nil
+}
+
+// Accept turns the active offer of an entry into the pending booking b. The
+// seats were already taken from the event when the offer was made.
+func (r *GormRepository) Accept(ctx context.Context, publicID string, userID uint, now time.Time, b *BookingRow) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var e Entry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&e).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEntryNotFound
+			}
+			r.logger.Error().Err(err).
+				Str("entry_public_id", publicID).
+				Msg("lock/select waitlist entry failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if e.UserID != userID {
+			return ErrNotEntryOwner
+		}
+		if e.Status != StatusOffered || e.OfferExpiresAt == nil || !now.Before(*e.OfferExpiresAt) {
+			return ErrNoActiveOffer
+		}
+
+		// Events without tiers are free and only give the currency.
+		var price ticketTypeRow
+		q := tx.Table("events").Select("0 AS price, currency").Where("id = ?", e.EventID)
+		if e.TicketTypeID != nil {
+			q = tx.Table("ticket_types").Select("price, currency").Where("id = ?", *e.TicketTypeID)
+		}
+		if err := q.Take(&price).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("entry_id", e.ID).
+				Msg("select booking price failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		b.EventID = e.EventID
+		b.TicketTypeID = e.TicketTypeID
+		b.UserID = e.UserID
+		b.Seats = e.Seats
+		b.Currency = price.Currency
+		total, err := price.Price.Mul(int64(e.Seats))
+		if err != nil {
+			return fmt.Errorf("booking total price: %w", err)
+		}
+		b.TotalPrice = total
+		if err := tx.Table("bookings").Create(b).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("entry_id", e.ID).
+				Msg("insert booking from offer failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+
+		if err := booking.RecordCreated(tx, b.ID, "waitlist offer accepted", booking.UserActor(e.UserID)); err != nil {
+			r.logger.Error().Err(err).
+				Uint("booking_id", b.ID).
+				Msg("insert booking status history failed")
+			return err
+		}
+
+		if err := tx.Model(&e).
+			Updates(map[string]any{"status": StatusConverted, "booking_id": b.ID}).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("entry_id", e.ID).
+				Msg("mark entry converted failed")
+			return fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		return nil
+	})
+}
+
+func (r *GormRepository) lockEvent(tx *gorm.DB, eventID uint) (*eventRow, error) {
+	var ev eventRow
+	if err := tx.Table("events").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, available_seats").
+		Where("id = ? AND deleted_at IS NULL", eventID).
+		Take(&ev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		r.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("lock/select event failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &ev, nil
+}
+
+// findTicketType resolves the tier an entry waits for. Events with tiers
+// need one; events without return nil.
+func (r *GormRepository) findTicketType(tx *gorm.DB, eventID uint, publicID string) (*ticketTypeRow, error) {
+	if publicID == "" {
+		var tiers int64
+		if err := tx.Table("ticket_types").Where("event_id = ?", eventID).Count(&tiers).Error; err != nil {
+			r.logger.Error().Err(err).
+				Uint("event_id", eventID).
+				Msg("count ticket types failed")
+			return nil, fmt.Errorf("%w: %v", ErrDB, err)
+		}
+		if tiers > 0 {
+			return nil, ErrTicketTypeRequired
+		}
+		return nil, nil
+	}
+
+	var tt ticketTypeRow
+	if err := tx.Table("ticket_types").
+		Select("id, price, currency, available, max_per_order").
+		Where("public_id = ? AND event_id = ?", publicID, eventID).
+		Take(&tt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketTypeNotFound
+		}
+		r.logger.Error().Err(err).
+			Str("ticket_type_public_id", publicID).
+			Msg("select ticket type failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return &tt, nil
+}
+
+// lockTicketTypes locks the tiers the entries wait for, in id order, and
+// returns their available seats.
+func (r *GormRepository) lockTicketTypes(tx *gorm.DB, entries []Entry) (map[uint]uint64, error) {
+	available := make(map[uint]uint64)
+	var ids []uint
+	for _, e := range entries {
+		if e.TicketTypeID != nil {
+			ids = append(ids, *e.TicketTypeID)
+		}
+	}
+	if len(ids) == 0 {
+		return available, nil
+	}
+
+	var tiers []ticketTypeRow
+	if err := tx.Table("ticket_types").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, available").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&tiers).Error; err != nil {
+		r.logger.Error().Err(err).Msg("lock/select ticket types failed")
+		return nil, fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	for _, tt := range tiers {
+		available[tt.ID] = tt.Available
+	}
+	return available, nil
+}
+
+// moveSeats takes ("-") or gives back ("+") the seats of the entries on their
+// events and ticket types, each in id order.
+func moveSeats(tx *gorm.DB, entries []Entry, op string) error {
+	seatsByEvent := make(map[uint]uint)
+	seatsByTicketType := make(map[uint]uint)
+	for _, e := range entries {
+		seatsByEvent[e.EventID] += e.Seats
+		if e.TicketTypeID != nil {
+			seatsByTicketType[*e.TicketTypeID] += e.Seats
+		}
+	}
+
+	for _, id := range sortedKeys(seatsByEvent) {
+		if err := tx.Table("events").
+			Where("id = ?", id).
+			Update("available_seats", gorm.Expr("available_seats "+op+" ?", seatsByEvent[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: move seats of event %d: %v", ErrDB, id, err)
+		}
+	}
+
+	for _, id := range sortedKeys(seatsByTicketType) {
+		if err := tx.Table("ticket_types").
+			Where("id = ?", id).
+			Update("available", gorm.Expr("available "+op+" ?", seatsByTicketType[id])).
+			Error; err != nil {
+			return fmt.Errorf("%w: move seats of ticket type %d: %v", ErrDB, id, err)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[uint]uint) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
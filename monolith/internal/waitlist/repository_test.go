@@ -0,0 +1,61 @@
+package waitlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntriesThatFit(t *testing.T) {
+	vip := uint(7)
+
+	tests := []struct {
+		name      string
+		waiting   []Entry
+		available uint64
+		tiers     map[uint]uint64
+		want      []uint
+	}{
+		{
+			name:      "offers in the order entries joined",
+			waiting:   []Entry{{ID: 1, Seats: 2}, {ID: 2, Seats: 1}, {ID: 3, Seats: 1}},
+			available: 3,
+			want:      []uint{1, 2},
+		},
+		{
+			name:      "passes over a large party at the head",
+			waiting:   []Entry{{ID: 1, Seats: 4}, {ID: 2, Seats: 1}},
+			available: 3,
+			want:      []uint{2},
+		},
+		{
+			name:      "passes over a head entry whose ticket type is sold out",
+			waiting:   []Entry{{ID: 1, Seats: 2, TicketTypeID: &vip}, {ID: 2, Seats: 1}},
+			available: 5,
+			tiers:     map[uint]uint64{vip: 0},
+			want:      []uint{2},
+		},
+		{
+			name:      "takes the ticket type seats it offers",
+			waiting:   []Entry{{ID: 1, Seats: 1, TicketTypeID: &vip}, {ID: 2, Seats: 1, TicketTypeID: &vip}},
+			available: 5,
+			tiers:     map[uint]uint64{vip: 1},
+			want:      []uint{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiers := tt.tiers
+			if tiers == nil {
+				tiers = map[uint]uint64{}
+			}
+
+			var got []uint
+			for _, e := range entriesThatFit(tt.waiting, tt.available, tiers) {
+				got = append(got, e.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anrisys/quicket/internal/booking"
+	commonDTO "github.com/anrisys/quicket/internal/dto"
+	waitlistDTO "github.com/anrisys/quicket/internal/waitlist/dto"
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/anrisys/quicket/pkg/errs"
+	"github.com/anrisys/quicket/pkg/types"
+	"github.com/anrisys/quicket/pkg/util"
+	"github.com/rs/zerolog"
+)
+
+type ServiceInterface interface {
+	Join(ctx context.Context, eventPublicID string, req *waitlistDTO.JoinWaitlistRequest, userPublicID string) (*waitlistDTO.EntryDTO, error)
+	ListMine(ctx context.Context, userPublicID string) ([]waitlistDTO.EntryDTO, error)
+	Accept(ctx context.Context, entryPublicID, userPublicID string) (*waitlistDTO.AcceptedBookingDTO, error)
+}
+
+type Service struct {
+	repo Repository
+	events types.EventReader
+	users types.UserReader
+	payments types.SimulatePayment
+	bookings types.PendingBookingFailer
+	offerTTL time.Duration
+	holdDuration time.Duration
+	logger zerolog.Logger
+}
+
+func NewService(
+	repo Repository,
+	events types.EventReader,
+	users types.UserReader,
+	payments types.SimulatePayment,
+	bookings types.PendingBookingFailer,
+	cfg *config.AppConfig,
+	logger zerolog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		events: events,
+		users: users,
+		payments: payments,
+		bookings: bookings,
+		offerTTL: cfg.Waitlist.OfferTTL,
+		holdDuration: cfg.Booking.HoldDuration,
+		logger: logger,
+	}
+}
+
+func (s *Service) Join(ctx context.Context, eventPublicID string, req *waitlistDTO.JoinWaitlistRequest, userPublicID string) (*waitlistDTO.EntryDTO, error) {
+	ev, err := s.events.GetEventDateTimeAndSeats(ctx, eventPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist service#join: %w", err)
+	}
+	if time.Now().After(ev.EndDate) {
+		return nil, errs.NewConflictError("can not join the waitlist of a past event")
+	}
+
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist service#join: %w", err)
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist#join: generate public id: %w", err)
+	}
+
+	e := &Entry{
+		PublicID: publicID,
+		EventID: uint(ev.ID),
+		UserID: *userID,
+		Seats: req.Seats,
+		Status: StatusWaiting,
+	}
+	if err := s.repo.Join(ctx, e, req.TicketTypeID); err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			return nil, errs.NewErrNotFound("event")
+		case errors.Is(err, ErrSeatMapEvent):
+			return nil, errs.NewValidationError("events with a seat map do not have a waitlist")
+		case errors.Is(err, ErrSeatsAvailable):
+			return nil, errs.NewConflictError("the requested seats are available, book them directly")
+		case errors.Is(err, ErrAlreadyWaitlisted):
+			return nil, errs.NewConflictError("already on the waitlist of this event")
+		case errors.Is(err, ErrTicketTypeRequired):
+			return nil, errs.NewValidationError("ticket_type_id is required for this event")
+		case errors.Is(err, ErrTicketTypeNotFound):
+			return nil, errs.NewErrNotFound("ticket type")
+		case errors.Is(err, ErrExceedsMaxPerOrder):
+			return nil, errs.NewValidationError("seats exceed the per-order limit of this ticket type")
+		default:
+			return nil, fmt.Errorf("waitlist#join: persist: %w", err)
+		}
+	}
+
+	position, err := s.repo.Position(ctx, e)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist#join: position: %w", err)
+	}
+
+	s.logger.Info().
+		Str("event_public_id", eventPublicID).
+		Str("entry_public_id", e.PublicID).
+		Uint("seats", e.Seats).
+		Uint("position", position).
+		Msg("Joined waitlist")
+
+	return &waitlistDTO.EntryDTO{
+		PublicID: e.PublicID,
+		EventID: eventPublicID,
+		TicketTypeID: req.TicketTypeID,
+		Seats: e.Seats,
+		Status: e.Status,
+		Position: position,
+		CreatedAt: e.CreatedAt,
+	}, nil
+}
+
+func (s *Service) ListMine(ctx context.Context, userPublicID string) ([]waitlistDTO.EntryDTO, error) {
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist service#list: %w", err)
+	}
+
+	views, err := s.repo.ListActiveByUser(ctx, *userID)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist#list: %w", err)
+	}
+
+	entries := make([]waitlistDTO.EntryDTO, 0, len(views))
+	for _, v := range views {
+		entry := waitlistDTO.EntryDTO{
+			PublicID: v.PublicID,
+			EventID: v.EventPublicID,
+			Seats: v.Seats,
+			Status: v.Status,
+			OfferExpiresAt: v.OfferExpiresAt,
+			CreatedAt: v.CreatedAt,
+		}
+		if v.TicketTypePublicID != nil {
+			entry.TicketTypeID = *v.TicketTypePublicID
+		}
+		if v.Status == StatusWaiting {
+			entry.Position = v.Position
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Accept converts the user's active offer into a pending booking and starts
+// its payment, the same way a direct booking does.
+func (s *Service) Accept(ctx context.Context, entryPublicID, userPublicID string) (*waitlistDTO.AcceptedBookingDTO, error) {
+	userID, err := s.users.GetUserID(ctx, userPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist service#accept: %w", err)
+	}
+
+	publicID, err := util.GeneratePublicID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waitlist#accept: generate public id: %w", err)
+	}
+
+	now := time.Now()
+	b := &BookingRow{
+		PublicID: publicID,
+		Status: booking.StatusPending,
+		ExpiredAt: now.Add(s.holdDuration),
+	}
+	if err := s.repo.Accept(ctx, entryPublicID, *userID, now, b); err != nil {
+		switch {
+		case errors.Is(err, ErrEntryNotFound):
+			return nil, errs.NewErrNotFound("waitlist entry")
+		case errors.Is(err, ErrNotEntryOwner):
+			return nil, errs.NewForbiddenError("waitlist entry belongs to another user")
+		case errors.Is(err, ErrNoActiveOffer):
+			return nil, errs.NewConflictError("waitlist entry has no active offer")
+		default:
+			return nil, fmt.Errorf("waitlist#accept: persist: %w", err)
+		}
+	}
+
+	if _, err := s.payments.SimulatePayment(ctx, &commonDTO.SimulateBookingPayment{
+		Amount: b.TotalPrice,
+		BookingID: b.ID,
+		BookingPublicID: b.PublicID,
+		UserID: b.UserID,
+	}); err != nil {
+		s.logger.Error().Err(err).
+			Str("booking_public_id", b.PublicID).
+			Msg("failed to queue booking payment")
+		if failErr := s.bookings.FailPending(ctx, b.ID, "payment could not be queued"); failErr != nil {
+			s.logger.Error().Err(failErr).
+				Str("booking_public_id", b.PublicID).
+				Msg("failed to fail unpaid booking")
+		} else {
+			s.SeatsReleased(ctx, b.EventID)
+		}
+		return nil, fmt.Errorf("waitlist#accept: queue payment: %w", err)
+	}
+
+	s.logger.Info().
+		Str("entry_public_id", entryPublicID).
+		Str("booking_public_id", b.PublicID).
+		Msg("Waitlist offer accepted")
+
+	return &waitlistDTO.AcceptedBookingDTO{
+		PublicID: b.PublicID,
+		Seats: b.Seats,
+		TotalPrice: b.TotalPrice,
+		Status: b.Status,
+		ExpiredAt: b.ExpiredAt,
+	}, nil
+}
+
+// SeatsReleased offers the freed seats of an event to its waitlist. Failures
+// are only logged; the sweeper retries on its next run.
+func (s *Service) SeatsReleased(ctx context.Context, eventID uint) {
+	offered, err := s.repo.Promote(ctx, eventID, time.Now(), s.offerTTL)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Uint("event_id", eventID).
+			Msg("failed to offer released seats to the waitlist")
+		return
+	}
+
+	for _, e := range offered {
+		s.logger.Info().
+			Uint("event_id", e.EventID).
+			Str("entry_public_id", e.PublicID).
+			Uint("seats", e.Seats).
+			Time("offer_expires_at", *e.OfferExpiresAt).
+			Msg("waitlist entry offered seats")
+	}
+}
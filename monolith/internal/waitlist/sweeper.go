@@ -0,0 +1,83 @@
+package waitlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/anrisys/quicket/pkg/config"
+	"github.com/rs/zerolog"
+)
+
+const lapseBatchSize = 100
+
+// Sweeper closes offers nobody accepted in time and offers the seats of every
+// event with a waitlist to the next entries in line. It also catches seats
+// freed by paths that do not notify the waitlist directly.
+type Sweeper struct {
+	repo Repository
+	srv *Service
+	interval time.Duration
+	logger zerolog.Logger
+}
+
+func NewSweeper(repo Repository, srv *Service, cfg *config.AppConfig, logger zerolog.Logger) *Sweeper {
+	return &Sweeper{
+		repo: repo,
+		srv: srv,
+		interval: cfg.Waitlist.SweepInterval,
+		logger: logger,
+	}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (w *Sweeper) Run(ctx context.Context) {
+	w.logger.Info().
+		Dur("interval", w.interval).
+		Msg("waitlist sweeper started")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info().Msg("waitlist sweeper stopped")
+			return
+		case <-ticker.C:
+			if err := w.Sweep(ctx, time.Now()); err != nil {
+				w.logger.Error().Err(err).Msg("failed to sweep waitlists")
+			}
+		}
+	}
+}
+
+func (w *Sweeper) Sweep(ctx context.Context, now time.Time) error {
+	for {
+		lapsed, err := w.repo.LapseOffers(ctx, now, lapseBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range lapsed {
+			w.logger.Info().
+				Uint("event_id", e.EventID).
+				Str("entry_public_id", e.PublicID).
+				Uint("seats", e.Seats).
+				Msg("waitlist offer lapsed, seats released")
+		}
+		if len(lapsed) < lapseBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	eventIDs, err := w.repo.EventsWithWaiting(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range eventIDs {
+		if ctx.Err() != nil {
+			return nil
+		}
+		w.srv.SeatsReleased(ctx, id)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package waitlist
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	NewGormRepository,
+	NewService,
+	NewHandler,
+	NewSweeper,
+	wire.Bind(new(Repository), new(*GormRepository)),
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
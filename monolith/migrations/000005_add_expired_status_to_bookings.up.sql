@@ -0,0 +1,3 @@
+ALTER TABLE bookings
+    MODIFY status ENUM('pending', 'success', 'failed', 'expired') NOT NULL DEFAULT 'pending',
+    ADD INDEX `idx_bookings_status_expired_at` (`status`, `expired_at`);
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE idempotency_keys (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scope VARCHAR(64) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status ENUM('in_progress', 'completed') NOT NULL DEFAULT 'in_progress',
+    response_code SMALLINT UNSIGNED NULL,
+    response_body MEDIUMBLOB NULL,
+    expires_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    UNIQUE KEY `uq_idempotency_keys_scope_key` (`scope`, `idempotency_key`),
+    INDEX `idx_idempotency_keys_expires_at` (`expires_at`)
+) ENGINE = InnoDB;
\ No newline at end of file
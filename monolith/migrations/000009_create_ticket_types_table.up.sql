@@ -0,0 +1,14 @@
+CREATE TABLE ticket_types (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    event_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    quota BIGINT UNSIGNED NOT NULL,
+    available BIGINT UNSIGNED NOT NULL,
+    max_per_order INT UNSIGNED NOT NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX `idx_ticket_types_event_id` (`event_id`)
+) ENGINE = InnoDB;
\ No newline at end of file
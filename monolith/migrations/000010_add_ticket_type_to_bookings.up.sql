@@ -0,0 +1,3 @@
+ALTER TABLE bookings
+    ADD COLUMN ticket_type_id BIGINT UNSIGNED NULL AFTER event_id,
+    ADD CONSTRAINT `fk_bookings_ticket_type_id` FOREIGN KEY (`ticket_type_id`) REFERENCES ticket_types(`id`) ON UPDATE CASCADE ON DELETE SET NULL;
\ No newline at end of file
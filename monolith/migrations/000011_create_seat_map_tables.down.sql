@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS seats;
+DROP TABLE IF EXISTS seat_rows;
+DROP TABLE IF EXISTS seat_sections;
\ No newline at end of file
@@ -0,0 +1,32 @@
+CREATE TABLE seat_sections (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    event_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX `idx_seat_sections_event_id` (`event_id`)
+) ENGINE = InnoDB;
+
+CREATE TABLE seat_rows (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    section_id BIGINT UNSIGNED NOT NULL,
+    label VARCHAR(20) NOT NULL,
+    FOREIGN KEY (section_id) REFERENCES seat_sections(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX `idx_seat_rows_section_id` (`section_id`)
+) ENGINE = InnoDB;
+
+CREATE TABLE seats (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    event_id BIGINT UNSIGNED NOT NULL,
+    row_id BIGINT UNSIGNED NOT NULL,
+    label VARCHAR(20) NOT NULL,
+    status ENUM('available', 'held', 'sold') NOT NULL DEFAULT 'available',
+    booking_id BIGINT UNSIGNED NULL,
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (row_id) REFERENCES seat_rows(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT `fk_seats_booking_id` FOREIGN KEY (`booking_id`) REFERENCES bookings(`id`) ON UPDATE CASCADE ON DELETE SET NULL,
+    INDEX `idx_seats_event_id` (`event_id`),
+    INDEX `idx_seats_booking_id` (`booking_id`)
+) ENGINE = InnoDB;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE waitlist_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    event_id BIGINT UNSIGNED NOT NULL,
+    ticket_type_id BIGINT UNSIGNED NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    seats BIGINT UNSIGNED NOT NULL,
+    status ENUM('waiting', 'offered', 'converted', 'lapsed') NOT NULL DEFAULT 'waiting',
+    booking_id BIGINT UNSIGNED NULL,
+    offer_expires_at DATETIME(3) NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (event_id) REFERENCES events(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (ticket_type_id) REFERENCES ticket_types(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    INDEX `idx_waitlist_entries_event_status` (`event_id`, `status`, `id`),
+    INDEX `idx_waitlist_entries_user_id` (`user_id`),
+    INDEX `idx_waitlist_entries_offer_expires_at` (`status`, `offer_expires_at`)
+) ENGINE = InnoDB;
\ No newline at end of file
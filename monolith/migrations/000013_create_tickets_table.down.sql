@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tickets;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE tickets (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    booking_id BIGINT UNSIGNED NOT NULL,
+    event_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    seat_id BIGINT UNSIGNED NULL,
+    checked_in_at DATETIME(3) NULL,
+    checked_in_by BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES events(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (seat_id) REFERENCES seats(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    FOREIGN KEY (checked_in_by) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    INDEX `idx_tickets_seat_id` (`seat_id`),
+    INDEX `idx_tickets_booking_id` (`booking_id`),
+    INDEX `idx_tickets_event_checked_in` (`event_id`, `checked_in_at`)
+) ENGINE = InnoDB;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    MODIFY role ENUM('user', 'organizer', 'staff', 'admin') NOT NULL DEFAULT 'user';
\ No newline at end of file
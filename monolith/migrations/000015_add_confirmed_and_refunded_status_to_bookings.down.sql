@@ -0,0 +1,6 @@
+ALTER TABLE bookings
+    MODIFY status ENUM('pending', 'success', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded') NOT NULL DEFAULT 'pending';
+UPDATE bookings SET status = 'success' WHERE status = 'confirmed';
+UPDATE bookings SET status = 'cancelled' WHERE status = 'refunded';
+ALTER TABLE bookings
+    MODIFY status ENUM('pending', 'success', 'failed', 'expired', 'cancelled') NOT NULL DEFAULT 'pending';
\ No newline at end of file
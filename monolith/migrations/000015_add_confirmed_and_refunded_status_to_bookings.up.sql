@@ -0,0 +1,5 @@
+ALTER TABLE bookings
+    MODIFY status ENUM('pending', 'success', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded') NOT NULL DEFAULT 'pending';
+UPDATE bookings SET status = 'confirmed' WHERE status = 'success';
+ALTER TABLE bookings
+    MODIFY status ENUM('pending', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded') NOT NULL DEFAULT 'pending';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS booking_status_history;
\ No newline at end of file
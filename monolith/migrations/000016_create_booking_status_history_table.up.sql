@@ -0,0 +1,15 @@
+CREATE TABLE booking_status_history (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    booking_id BIGINT UNSIGNED NOT NULL,
+    from_status ENUM('pending', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded') NULL,
+    to_status ENUM('pending', 'confirmed', 'failed', 'expired', 'cancelled', 'refunded') NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    actor_type ENUM('user', 'system') NOT NULL,
+    actor_id BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    INDEX `idx_booking_status_history_booking_id` (`booking_id`, `id`)
+) ENGINE = InnoDB;
+INSERT INTO booking_status_history (booking_id, from_status, to_status, reason, actor_type, created_at)
+    SELECT id, NULL, status, 'backfilled', 'system', updated_at FROM bookings;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE payments
+    DROP INDEX `idx_payments_provider_ref`,
+    DROP COLUMN provider_ref,
+    DROP COLUMN provider;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE payments
+    ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT 'simulator' AFTER status,
+    ADD COLUMN provider_ref VARCHAR(64) NULL AFTER provider,
+    ADD INDEX `idx_payments_provider_ref` (`provider`, `provider_ref`);
\ No newline at end of file
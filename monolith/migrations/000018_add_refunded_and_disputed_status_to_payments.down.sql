@@ -0,0 +1,3 @@
+UPDATE payments SET status = 'success' WHERE status IN ('refunded', 'disputed');
+ALTER TABLE payments
+    MODIFY status ENUM('success', 'failed') NOT NULL;
\ No newline at end of file
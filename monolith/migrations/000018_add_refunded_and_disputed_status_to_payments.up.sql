@@ -0,0 +1,2 @@
+ALTER TABLE payments
+    MODIFY status ENUM('success', 'failed', 'refunded', 'disputed') NOT NULL;
\ No newline at end of file
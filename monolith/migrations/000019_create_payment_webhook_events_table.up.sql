@@ -0,0 +1,10 @@
+CREATE TABLE payment_webhook_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    provider VARCHAR(32) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payment_id BIGINT UNSIGNED NULL,
+    result ENUM('applied', 'ignored') NOT NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (payment_id) REFERENCES payments(id) ON UPDATE CASCADE ON DELETE SET NULL
+) ENGINE = InnoDB;
\ No newline at end of file
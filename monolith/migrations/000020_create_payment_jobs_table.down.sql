@@ -0,0 +1 @@
+DROP TABLE IF EXISTS payment_jobs;
\ No newline at end of file
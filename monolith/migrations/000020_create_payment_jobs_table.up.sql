@@ -0,0 +1,16 @@
+CREATE TABLE payment_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    payment_public_id CHAR(36) NOT NULL UNIQUE,
+    booking_id BIGINT UNSIGNED NOT NULL,
+    booking_public_id CHAR(36) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    status ENUM('queued', 'running', 'done', 'dead') NOT NULL DEFAULT 'queued',
+    attempts INT UNSIGNED NOT NULL DEFAULT 0,
+    available_at DATETIME(3) NOT NULL,
+    last_error VARCHAR(255) NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    INDEX `idx_payment_jobs_status_available_at` (status, available_at),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB;
\ No newline at end of file
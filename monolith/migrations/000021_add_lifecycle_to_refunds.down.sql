@@ -0,0 +1,10 @@
+DELETE newer FROM refunds newer
+    JOIN refunds older ON older.payment_id = newer.payment_id AND older.id < newer.id;
+UPDATE refunds SET status = 'requested';
+ALTER TABLE refunds
+    DROP COLUMN requested_by,
+    DROP COLUMN failure_message,
+    DROP COLUMN reason,
+    MODIFY status ENUM('requested') NOT NULL DEFAULT 'requested',
+    ADD UNIQUE INDEX payment_id (payment_id),
+    DROP INDEX `idx_refunds_payment_id`;
\ No newline at end of file
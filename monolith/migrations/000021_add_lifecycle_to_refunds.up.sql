@@ -0,0 +1,7 @@
+ALTER TABLE refunds
+    ADD INDEX `idx_refunds_payment_id` (payment_id),
+    DROP INDEX payment_id,
+    MODIFY status ENUM('requested', 'succeeded', 'failed') NOT NULL DEFAULT 'requested',
+    ADD COLUMN reason VARCHAR(255) NULL AFTER status,
+    ADD COLUMN failure_message VARCHAR(255) NULL AFTER reason,
+    ADD COLUMN requested_by BIGINT UNSIGNED NULL AFTER failure_message;
\ No newline at end of file
@@ -0,0 +1,21 @@
+ALTER TABLE payment_jobs MODIFY amount DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE payment_jobs SET amount = amount / 100;
+ALTER TABLE payment_jobs MODIFY amount DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE refunds MODIFY amount DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE refunds SET amount = amount / 100;
+ALTER TABLE refunds MODIFY amount DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE payments MODIFY amount DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE payments SET amount = amount / 100;
+ALTER TABLE payments MODIFY amount DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE bookings MODIFY total_price DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE bookings SET total_price = total_price / 100;
+ALTER TABLE bookings MODIFY total_price DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE ticket_types MODIFY price DECIMAL(14, 2) NOT NULL, DROP COLUMN currency;
+UPDATE ticket_types SET price = price / 100;
+ALTER TABLE ticket_types MODIFY price DECIMAL(10, 2) NOT NULL;
+
+ALTER TABLE events DROP COLUMN currency;
\ No newline at end of file
@@ -0,0 +1,32 @@
+ALTER TABLE events
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE ticket_types
+    MODIFY price DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER price;
+UPDATE ticket_types SET price = price * 100;
+ALTER TABLE ticket_types MODIFY price BIGINT NOT NULL;
+
+ALTER TABLE bookings
+    MODIFY total_price DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER total_price;
+UPDATE bookings SET total_price = total_price * 100;
+ALTER TABLE bookings MODIFY total_price BIGINT NOT NULL;
+
+ALTER TABLE payments
+    MODIFY amount DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
+UPDATE payments SET amount = amount * 100;
+ALTER TABLE payments MODIFY amount BIGINT NOT NULL;
+
+ALTER TABLE refunds
+    MODIFY amount DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
+UPDATE refunds SET amount = amount * 100;
+ALTER TABLE refunds MODIFY amount BIGINT NOT NULL;
+
+ALTER TABLE payment_jobs
+    MODIFY amount DECIMAL(14, 2) NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
+UPDATE payment_jobs SET amount = amount * 100;
+ALTER TABLE payment_jobs MODIFY amount BIGINT NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reconciliation_mismatches;
+DROP TABLE IF EXISTS reconciliation_reports;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE reconciliation_reports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    provider VARCHAR(32) NOT NULL,
+    window_from DATETIME(3) NOT NULL,
+    window_to DATETIME(3) NOT NULL,
+    checked INT UNSIGNED NOT NULL DEFAULT 0,
+    mismatch_count INT UNSIGNED NOT NULL DEFAULT 0,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX `idx_reconciliation_reports_window` (window_from, window_to)
+) ENGINE = InnoDB;
+
+CREATE TABLE reconciliation_mismatches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    report_id BIGINT UNSIGNED NOT NULL,
+    kind ENUM('missing_locally', 'amount_mismatch', 'status_mismatch', 'confirmed_without_payment') NOT NULL,
+    provider_ref VARCHAR(64) NULL,
+    payment_public_id CHAR(36) NULL,
+    booking_public_id CHAR(36) NULL,
+    provider_value VARCHAR(64) NULL,
+    local_value VARCHAR(64) NULL,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (report_id) REFERENCES reconciliation_reports(id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB;
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE event_series (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    public_id CHAR(36) NOT NULL UNIQUE,
+    title VARCHAR(256) NOT NULL,
+    description TEXT,
+    organizer_id BIGINT UNSIGNED NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    rrule VARCHAR(256) NOT NULL,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    max_seats BIGINT UNSIGNED NOT NULL,
+    materialized_until DATETIME NOT NULL,
+    finished BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    deleted_at DATETIME(3) NULL,
+    FOREIGN KEY (organizer_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX `idx_event_series_deleted_at` (deleted_at),
+    INDEX `idx_event_series_due` (finished, materialized_until)
+) ENGINE = InnoDB;
+
+ALTER TABLE events
+    ADD COLUMN series_id BIGINT UNSIGNED NULL,
+    ADD COLUMN occurrence_start DATETIME NULL,
+    ADD UNIQUE INDEX `idx_events_series_occurrence` (series_id, occurrence_start),
+    ADD CONSTRAINT `fk_events_series` FOREIGN KEY (series_id) REFERENCES event_series(id) ON UPDATE CASCADE ON DELETE SET NULL;
\ No newline at end of file
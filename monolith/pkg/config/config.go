@@ -1,14 +1,20 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type ServerConfig struct {
-	Port string `mapstructure:"PORT"`
+	Port            string        `mapstructure:"PORT"`
+	// ShutdownTimeout bounds draining requests, stopping the background
+	// workers and closing connections once the server is told to stop.
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
 }
 
 type DBConfig struct {
@@ -31,20 +37,113 @@ type SecurityConfig struct {
 	JWTExpiry time.Duration `mapstructure:"jwt_expiry"`
 }
 
+type BookingConfig struct {
+	HoldDuration    time.Duration `mapstructure:"booking_hold_duration"`
+	ExpiryInterval  time.Duration `mapstructure:"booking_expiry_interval"`
+	ExpiryBatchSize int           `mapstructure:"booking_expiry_batch_size"`
+}
+
+type EventSeriesConfig struct {
+	// Horizon is how far ahead the occurrences of a series are created.
+	Horizon             time.Duration `mapstructure:"event_series_horizon"`
+	MaterializeInterval time.Duration `mapstructure:"event_series_materialize_interval"`
+}
+
+type IdempotencyConfig struct {
+	KeyTTL time.Duration `mapstructure:"idempotency_key_ttl"`
+}
+
+type WaitlistConfig struct {
+	OfferTTL      time.Duration `mapstructure:"waitlist_offer_ttl"`
+	SweepInterval time.Duration `mapstructure:"waitlist_sweep_interval"`
+}
+
+type PaymentConfig struct {
+	// Provider is simulator, fake or http.
+	Provider            string        `mapstructure:"payment_provider"`
+	FakeOutcome         string        `mapstructure:"payment_fake_outcome"`
+	GatewayURL          string        `mapstructure:"payment_gateway_url"`
+	GatewayTimeout      time.Duration `mapstructure:"payment_gateway_timeout"`
+	// WebhookSecret signs provider webhooks; webhooks are refused while it
+	// is empty.
+	WebhookSecret       string        `mapstructure:"payment_webhook_secret"`
+	WebhookTolerance    time.Duration `mapstructure:"payment_webhook_tolerance"`
+	// Workers is how many jobs are charged at the same time. New payments
+	// are refused once QueueDepth jobs are waiting.
+	Workers             int           `mapstructure:"payment_workers"`
+	QueueDepth          int           `mapstructure:"payment_queue_depth"`
+	MaxAttempts         int           `mapstructure:"payment_max_attempts"`
+	RetryBackoff        time.Duration `mapstructure:"payment_retry_backoff"`
+	// VisibilityTimeout is how long a leased job stays hidden from other
+	// workers before it is handed out again.
+	VisibilityTimeout   time.Duration `mapstructure:"payment_visibility_timeout"`
+	PollInterval        time.Duration `mapstructure:"payment_poll_interval"`
+	// RefundRetryInterval is how often refunds the provider could not be
+	// reached for are sent again.
+	RefundRetryInterval time.Duration `mapstructure:"payment_refund_retry_interval"`
+	// FailurePolicy is what a failed payment does to its booking: fail
+	// releases the seats right away, retry keeps them held so the user can
+	// pay again until the booking expires.
+	FailurePolicy       string        `mapstructure:"payment_failure_policy"`
+	// ReconcileDelay is how long after midnight the previous day is
+	// reconciled with the provider, so late webhooks can land first.
+	ReconcileDelay      time.Duration `mapstructure:"payment_reconcile_delay"`
+}
+
+type TicketConfig struct {
+	// SigningKey is the base64 encoded Ed25519 seed tickets are signed with.
+	SigningKey string `mapstructure:"ticket_signing_key"`
+}
+
 type AppConfig struct {
 	UserServiceURL string `mapstructure:"USER_SERVICE_URL"`
 	Server   ServerConfig
 	Logging  LogConfig
 	Database DBConfig       `mapstructure:",squash"`
 	Security SecurityConfig `mapstructure:",squash"`
+	Booking  BookingConfig  `mapstructure:",squash"`
+	EventSeries EventSeriesConfig `mapstructure:",squash"`
+	Idempotency IdempotencyConfig `mapstructure:",squash"`
+	Waitlist WaitlistConfig `mapstructure:",squash"`
+	Ticket   TicketConfig   `mapstructure:",squash"`
+	Payment  PaymentConfig  `mapstructure:",squash"`
 }
 
 func DefaultConfig() *AppConfig {
 	return &AppConfig{
-		Server:  ServerConfig{Port: "8080"},
+		Server:  ServerConfig{Port: "8080", ShutdownTimeout: 30 * time.Second},
 		Logging: LogConfig{Level: "debug", Pretty: true},
 		Security: SecurityConfig{BcryptCost: 14},
 		Database: DBConfig{},
+		Booking: BookingConfig{
+			HoldDuration:    5 * time.Minute,
+			ExpiryInterval:  30 * time.Second,
+			ExpiryBatchSize: 100,
+		},
+		EventSeries: EventSeriesConfig{
+			Horizon:             90 * 24 * time.Hour,
+			MaterializeInterval: time.Hour,
+		},
+		Idempotency: IdempotencyConfig{KeyTTL: 24 * time.Hour},
+		Waitlist: WaitlistConfig{
+			OfferTTL:      15 * time.Minute,
+			SweepInterval: 30 * time.Second,
+		},
+		Payment: PaymentConfig{
+			Provider:            "simulator",
+			FakeOutcome:         "approve",
+			GatewayTimeout:      10 * time.Second,
+			WebhookTolerance:    5 * time.Minute,
+			Workers:             5,
+			QueueDepth:          1000,
+			MaxAttempts:         5,
+			RetryBackoff:        5 * time.Second,
+			VisibilityTimeout:   time.Minute,
+			PollInterval:        time.Second,
+			RefundRetryInterval: time.Minute,
+			FailurePolicy:       "fail",
+			ReconcileDelay:      time.Hour,
+		},
 	}
 }
 
@@ -70,15 +169,35 @@ func Load() (*AppConfig, error) {
 		return nil, err
 	}
 
+	checkServerConfig(config)
+
 	checkDatabaseConfig(config)
 
 	checkSecurityConfig(config)
 
 	checkClientServices(config)
 
+	checkBookingConfig(config)
+
+	checkEventSeriesConfig(config)
+
+	checkIdempotencyConfig(config)
+
+	checkWaitlistConfig(config)
+
+	checkTicketConfig(config)
+
+	checkPaymentConfig(config)
+
 	return config, nil
 }
 
+func checkServerConfig(config *AppConfig) {
+	if config.Server.ShutdownTimeout <= 0 {
+		log.Fatal("Server shutdown timeout must be positive")
+	}
+}
+
 func checkDatabaseConfig(config *AppConfig) {
 	if config.Database == (DBConfig{}) {
 		log.Fatal("Database has not been set yet")
@@ -103,4 +222,93 @@ func checkClientServices(config *AppConfig) {
 	if config.UserServiceURL == "" {
 		log.Fatal("USER CLIENT URL has not been set yet")
 	}
+}
+
+func checkBookingConfig(config *AppConfig) {
+	if config.Booking.HoldDuration <= 0 {
+		log.Fatal("Booking hold duration must be positive")
+	}
+
+	if config.Booking.ExpiryInterval <= 0 {
+		log.Fatal("Booking expiry interval must be positive")
+	}
+
+	if config.Booking.ExpiryBatchSize <= 0 {
+		log.Fatal("Booking expiry batch size must be positive")
+	}
+}
+
+func checkEventSeriesConfig(config *AppConfig) {
+	if config.EventSeries.Horizon <= 0 {
+		log.Fatal("Event series horizon must be positive")
+	}
+
+	if config.EventSeries.MaterializeInterval <= 0 {
+		log.Fatal("Event series materialize interval must be positive")
+	}
+}
+
+func checkIdempotencyConfig(config *AppConfig) {
+	if config.Idempotency.KeyTTL <= 0 {
+		log.Fatal("Idempotency key TTL must be positive")
+	}
+}
+
+func checkWaitlistConfig(config *AppConfig) {
+	if config.Waitlist.OfferTTL <= 0 {
+		log.Fatal("Waitlist offer TTL must be positive")
+	}
+
+	if config.Waitlist.SweepInterval <= 0 {
+		log.Fatal("Waitlist sweep interval must be positive")
+	}
+}
+
+func checkTicketConfig(config *AppConfig) {
+	seed, err := base64.StdEncoding.DecodeString(config.Ticket.SigningKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		log.Fatal("Ticket signing key must be a base64 encoded 32 byte Ed25519 seed")
+	}
+}
+
+func checkPaymentConfig(config *AppConfig) {
+	switch config.Payment.Provider {
+	case "simulator":
+	case "fake":
+		if !slices.Contains([]string{"approve", "decline", "error"}, config.Payment.FakeOutcome) {
+			log.Fatal("Payment fake outcome must be approve, decline or error")
+		}
+	case "http":
+		if config.Payment.GatewayURL == "" {
+			log.Fatal("Payment gateway URL has not been set yet")
+		}
+		if config.Payment.GatewayTimeout <= 0 {
+			log.Fatal("Payment gateway timeout must be positive")
+		}
+	default:
+		log.Fatal("Payment provider must be simulator, fake or http")
+	}
+
+	if config.Payment.WebhookTolerance <= 0 {
+		log.Fatal("Payment webhook tolerance must be positive")
+	}
+
+	if config.Payment.Workers <= 0 || config.Payment.QueueDepth <= 0 {
+		log.Fatal("Payment workers and queue depth must be positive")
+	}
+	if config.Payment.MaxAttempts <= 0 {
+		log.Fatal("Payment max attempts must be positive")
+	}
+	if config.Payment.RetryBackoff <= 0 || config.Payment.VisibilityTimeout <= 0 || config.Payment.PollInterval <= 0 {
+		log.Fatal("Payment retry backoff, visibility timeout and poll interval must be positive")
+	}
+	if config.Payment.RefundRetryInterval <= 0 {
+		log.Fatal("Payment refund retry interval must be positive")
+	}
+	if !slices.Contains([]string{"fail", "retry"}, config.Payment.FailurePolicy) {
+		log.Fatal("Payment failure policy must be fail or retry")
+	}
+	if config.Payment.ReconcileDelay < 0 || config.Payment.ReconcileDelay >= 24*time.Hour {
+		log.Fatal("Payment reconcile delay must be between 0 and 24h")
+	}
 }
\ No newline at end of file
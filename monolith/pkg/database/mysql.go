@@ -12,7 +12,7 @@ import (
 
 func MySQLDB(cfg *config.AppConfig) (*gorm.DB, error) {
 	cfgDB := cfg.Database
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
 		cfgDB.DBUser,
 		cfgDB.DBPassword,
 		cfgDB.DBHost,
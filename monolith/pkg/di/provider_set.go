@@ -3,8 +3,13 @@ package di
 import (
 	"github.com/anrisys/quicket/internal/booking"
 	"github.com/anrisys/quicket/internal/event"
+	"github.com/anrisys/quicket/internal/idempotency"
 	"github.com/anrisys/quicket/internal/infrastructure"
 	"github.com/anrisys/quicket/internal/payment"
+	"github.com/anrisys/quicket/internal/seatmap"
+	"github.com/anrisys/quicket/internal/ticket"
+	"github.com/anrisys/quicket/internal/venue"
+	"github.com/anrisys/quicket/internal/waitlist"
 	"github.com/anrisys/quicket/pkg/config"
 	"github.com/anrisys/quicket/pkg/config/logger"
 	"github.com/anrisys/quicket/pkg/database"
@@ -48,9 +53,16 @@ var (
 		event.ProviderSet,
 		payment.ProviderSet,
 		booking.ProviderSet,
+		idempotency.ProviderSet,
+		seatmap.ProviderSet,
+		venue.ProviderSet,
+		ticket.ProviderSet,
+		waitlist.ProviderSet,
 		UserServiceClientSet,
 		wire.Bind(new(types.EventReader), new(*event.EventService)),
 		wire.Bind(new(types.SimulatePayment), new(*payment.PaymentService)),
+		wire.Bind(new(types.PendingBookingFailer), new(*booking.GormRepository)),
+		wire.Bind(new(types.SeatReleaseListener), new(*waitlist.Service)),
 		wire.Struct(new(App), "*"),
 	)
 )
\ No newline at end of file
@@ -6,8 +6,16 @@ package di
 import (
 	"github.com/anrisys/quicket/internal/booking"
 	"github.com/anrisys/quicket/internal/event"
+	"github.com/anrisys/quicket/internal/idempotency"
+	"github.com/anrisys/quicket/internal/payment"
+	"github.com/anrisys/quicket/internal/seatmap"
+	"github.com/anrisys/quicket/internal/ticket"
+	"github.com/anrisys/quicket/internal/venue"
+	"github.com/anrisys/quicket/internal/waitlist"
 	"github.com/anrisys/quicket/pkg/config"
 	"github.com/google/wire"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
 func InitializeApp() (*App, error) {
@@ -17,6 +25,21 @@ func InitializeApp() (*App, error) {
 
 type App struct {
 	Config 		*config.AppConfig
+	DB *gorm.DB
+	Logger zerolog.Logger
 	BookingHandler *booking.Handler
+	BookingExpirer *booking.Expirer
 	EventHandler *event.EventHandler
+	EventSeriesHandler *event.SeriesHandler
+	EventSeriesMaterializer *event.Materializer
+	Idempotency *idempotency.Middleware
+	PaymentHandler *payment.Handler
+	PaymentWorker *payment.Worker
+	Reconciler *payment.ReconciliationService
+	RefundService *payment.RefundService
+	SeatMapHandler *seatmap.Handler
+	TicketHandler *ticket.Handler
+	VenueHandler *venue.Handler
+	WaitlistHandler *waitlist.Handler
+	WaitlistSweeper *waitlist.Sweeper
 }
\ No newline at end of file
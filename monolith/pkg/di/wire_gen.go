@@ -9,11 +9,18 @@ package di
 import (
 	"github.com/anrisys/quicket/internal/booking"
 	"github.com/anrisys/quicket/internal/event"
+	"github.com/anrisys/quicket/internal/idempotency"
 	"github.com/anrisys/quicket/internal/infrastructure"
 	"github.com/anrisys/quicket/internal/payment"
+	"github.com/anrisys/quicket/internal/seatmap"
+	"github.com/anrisys/quicket/internal/ticket"
+	"github.com/anrisys/quicket/internal/venue"
+	"github.com/anrisys/quicket/internal/waitlist"
 	"github.com/anrisys/quicket/pkg/config"
 	"github.com/anrisys/quicket/pkg/config/logger"
 	"github.com/anrisys/quicket/pkg/database"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
 // Injectors from wire.go:
@@ -33,14 +40,60 @@ func InitializeApp() (*App, error) {
 	userServiceClient := infrastructure.NewUserServiceClient(appConfig)
 	eventService := event.NewEventService(eventRepository, userServiceClient, zerologLogger)
 	paymentGormRepository := payment.NewRepository(db, zerologLogger)
-	paymentService := payment.NewPaymentService(paymentGormRepository, zerologLogger)
-	service := booking.NewService(gormRepository, eventService, zerologLogger, paymentService, userServiceClient)
+	paymentProvider, err := payment.NewProvider(appConfig)
+	if err != nil {
+		return nil, err
+	}
+	paymentService := payment.NewPaymentService(paymentGormRepository, paymentProvider, userServiceClient, appConfig, zerologLogger)
+	waitlistGormRepository := waitlist.NewGormRepository(db, zerologLogger)
+	waitlistService := waitlist.NewService(waitlistGormRepository, eventService, userServiceClient, paymentService, gormRepository, appConfig, zerologLogger)
+	service := booking.NewService(gormRepository, eventService, zerologLogger, paymentService, userServiceClient, waitlistService, appConfig)
 	handler := booking.NewHandler(service, zerologLogger)
-	eventHandler := event.NewEventHandler(eventService, zerologLogger)
+	expirer := booking.NewExpirer(gormRepository, waitlistService, appConfig, zerologLogger)
+	materializer := event.NewMaterializer(eventRepository, appConfig, zerologLogger)
+	seriesService := event.NewSeriesService(eventRepository, eventService, userServiceClient, materializer, zerologLogger)
+	eventHandler := event.NewEventHandler(eventService, seriesService, zerologLogger)
+	seriesHandler := event.NewSeriesHandler(seriesService, zerologLogger)
+	idempotencyGormRepository := idempotency.NewGormRepository(db, zerologLogger)
+	middleware := idempotency.NewMiddleware(idempotencyGormRepository, appConfig, zerologLogger)
+	refundService := payment.NewRefundService(paymentGormRepository, paymentProvider, userServiceClient, appConfig, zerologLogger)
+	reconciliationService := payment.NewReconciliationService(paymentGormRepository, paymentProvider, appConfig, zerologLogger)
+	paymentHandler := payment.NewHandler(paymentService, refundService, reconciliationService, zerologLogger)
+	worker := payment.NewWorker(paymentGormRepository, paymentProvider, appConfig, zerologLogger)
+	seatmapGormRepository := seatmap.NewGormRepository(db, zerologLogger)
+	seatmapService := seatmap.NewService(seatmapGormRepository, userServiceClient, zerologLogger)
+	seatmapHandler := seatmap.NewHandler(seatmapService, zerologLogger)
+	ticketGormRepository := ticket.NewGormRepository(db, zerologLogger)
+	signer, err := ticket.NewSigner(appConfig)
+	if err != nil {
+		return nil, err
+	}
+	ticketService := ticket.NewService(ticketGormRepository, signer, userServiceClient, zerologLogger)
+	ticketHandler := ticket.NewHandler(ticketService, zerologLogger)
+	venueGormRepository := venue.NewGormRepository(db, zerologLogger)
+	venueService := venue.NewService(venueGormRepository, userServiceClient, zerologLogger)
+	venueHandler := venue.NewHandler(venueService, zerologLogger)
+	waitlistHandler := waitlist.NewHandler(waitlistService, zerologLogger)
+	sweeper := waitlist.NewSweeper(waitlistGormRepository, waitlistService, appConfig, zerologLogger)
 	app := &App{
-		Config:         appConfig,
-		BookingHandler: handler,
-		EventHandler:   eventHandler,
+		Config:                  appConfig,
+		DB:                      db,
+		Logger:                  zerologLogger,
+		BookingHandler:          handler,
+		BookingExpirer:          expirer,
+		EventHandler:            eventHandler,
+		EventSeriesHandler:      seriesHandler,
+		EventSeriesMaterializer: materializer,
+		Idempotency:             middleware,
+		PaymentHandler:          paymentHandler,
+		PaymentWorker:           worker,
+		Reconciler:              reconciliationService,
+		RefundService:           refundService,
+		SeatMapHandler:          seatmapHandler,
+		TicketHandler:           ticketHandler,
+		VenueHandler:            venueHandler,
+		WaitlistHandler:         waitlistHandler,
+		WaitlistSweeper:         sweeper,
 	}
 	return app, nil
 }
@@ -48,7 +101,22 @@ func InitializeApp() (*App, error) {
 // wire.go:
 
 type App struct {
-	Config         *config.AppConfig
-	BookingHandler *booking.Handler
-	EventHandler   *event.EventHandler
+	Config                  *config.AppConfig
+	DB                      *gorm.DB
+	Logger                  zerolog.Logger
+	BookingHandler          *booking.Handler
+	BookingExpirer          *booking.Expirer
+	EventHandler            *event.EventHandler
+	EventSeriesHandler      *event.SeriesHandler
+	EventSeriesMaterializer *event.Materializer
+	Idempotency             *idempotency.Middleware
+	PaymentHandler          *payment.Handler
+	PaymentWorker           *payment.Worker
+	Reconciler              *payment.ReconciliationService
+	RefundService           *payment.RefundService
+	SeatMapHandler          *seatmap.Handler
+	TicketHandler           *ticket.Handler
+	VenueHandler            *venue.Handler
+	WaitlistHandler         *waitlist.Handler
+	WaitlistSweeper         *waitlist.Sweeper
 }
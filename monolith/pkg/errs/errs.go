@@ -109,6 +109,45 @@ func NewConflictError(message string, internalErr ...error) *AppError {
 	return ae
 }
 
+func NewForbiddenError(message string, internalErr ...error) *AppError {
+	ae := &AppError{
+		Status:  http.StatusForbidden,
+		Code:    "FORBIDDEN",
+		Message: message,
+		Details: nil,
+	}
+	if len(internalErr) > 0 {
+		ae.Err = internalErr[0]
+	}
+	return ae
+}
+
+func NewUnauthorizedError(message string, internalErr ...error) *AppError {
+	ae := &AppError{
+		Status:  http.StatusUnauthorized,
+		Code:    "UNAUTHORIZED",
+		Message: message,
+		Details: nil,
+	}
+	if len(internalErr) > 0 {
+		ae.Err = internalErr[0]
+	}
+	return ae
+}
+
+func NewUnprocessableEntityError(message string, internalErr ...error) *AppError {
+	ae := &AppError{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "UNPROCESSABLE_ENTITY",
+		Message: message,
+		Details: nil,
+	}
+	if len(internalErr) > 0 {
+		ae.Err = internalErr[0]
+	}
+	return ae
+}
+
 func NewServiceUnavailableError(message string, internalErr ...error) *AppError {
     ae := &AppError{
 		Status:  http.StatusServiceUnavailable,
@@ -0,0 +1,71 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown_StopsTasksBeforeClosingInReverse(t *testing.T) {
+	m := New(time.Second, zerolog.Nop())
+
+	var mu sync.Mutex
+	var order []string
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, step)
+	}
+
+	m.OnClose("db", func() error { record("close db"); return nil })
+	m.OnClose("channel", func() error { record("close channel"); return nil })
+	m.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		record("worker stopped")
+		return nil
+	})
+
+	assert.NoError(t, m.Shutdown())
+	assert.Equal(t, []string{"worker stopped", "close channel", "close db"}, order)
+}
+
+func TestShutdown_Deadline(t *testing.T) {
+	m := New(50*time.Millisecond, zerolog.Nop())
+
+	closed := false
+	m.OnClose("db", func() error { closed = true; return nil })
+	m.Go("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	err := m.Shutdown()
+	assert.ErrorContains(t, err, "did not stop in time")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.True(t, closed)
+}
+
+func TestWait_TaskFailure(t *testing.T) {
+	m := New(time.Second, zerolog.Nop())
+	boom := errors.New("connection lost")
+
+	m.Go("consumer", func(ctx context.Context) error {
+		return boom
+	})
+	stopped := false
+	m.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped = true
+		return nil
+	})
+
+	err := m.Wait()
+	assert.ErrorIs(t, err, boom)
+	assert.True(t, stopped)
+}
@@ -0,0 +1,120 @@
+package money
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type priced struct {
+	ID uint
+	Price Money
+	Refunded Money
+	Currency Currency
+}
+
+func TestGormScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		row     []driver.Value
+	}{
+		{
+			name: "currency after the amounts",
+			columns: []string{"id", "price", "refunded", "currency"},
+			row: []driver.Value{int64(1), int64(1250), []byte("500"), []byte("USD")},
+		},
+		{
+			name: "currency before the amounts",
+			columns: []string{"currency", "id", "price", "refunded"},
+			row: []driver.Value{[]byte("USD"), int64(1), int64(1250), []byte("500")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openFake(t, &fakeRows{columns: tt.columns, rows: [][]driver.Value{tt.row}})
+
+			var got priced
+			require.NoError(t, db.Table("priced").Take(&got).Error)
+			assert.Equal(t, New(1250, "USD"), got.Price)
+			assert.Equal(t, New(500, "USD"), got.Refunded)
+			assert.Equal(t, Currency("USD"), got.Currency)
+		})
+	}
+}
+
+func TestGormValue(t *testing.T) {
+	db := openFake(t, &fakeRows{})
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).
+		Create(&priced{Price: New(1250, "USD"), Refunded: New(0, "USD"), Currency: "USD"}).Statement
+	assert.Equal(t, []any{int64(1250), int64(0), "USD"}, valuesOf(t, stmt.Vars))
+}
+
+func valuesOf(t *testing.T, vars []any) []any {
+	out := make([]any, 0, len(vars))
+	for _, v := range vars {
+		valuer, ok := v.(driver.Valuer)
+		require.True(t, ok, "%T is not a driver.Valuer", v)
+		value, err := valuer.Value()
+		require.NoError(t, err)
+		out = append(out, value)
+	}
+	return out
+}
+
+func openFake(t *testing.T, rows *fakeRows) *gorm.DB {
+	sqlDB := sql.OpenDB(fakeConnector{rows: rows})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+	return db
+}
+
+// fakeConnector answers every query with the same rows.
+type fakeConnector struct{ rows *fakeRows }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct{ rows *fakeRows }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c fakeConn) Commit() error { return nil }
+func (c fakeConn) Rollback() error { return nil }
+
+type fakeStmt struct{ rows *fakeRows }
+
+func (s fakeStmt) Close() error { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{columns: s.rows.columns, rows: s.rows.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,175 @@
+// Package money represents amounts exactly, as a whole number of minor
+// units (cents) of an ISO 4217 currency.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used where no currency was given, and is what every
+// amount stored before currencies were tracked is in.
+const DefaultCurrency Currency = "IDR"
+
+var (
+	ErrInvalidAmount = errors.New("money: invalid amount")
+	ErrInvalidCurrency = errors.New("money: invalid currency")
+	ErrCurrencyMismatch = errors.New("money: currencies do not match")
+	ErrOverflow = errors.New("money: amount out of range")
+)
+
+// Currency is an ISO 4217 currency code such as IDR or USD.
+type Currency string
+
+// zeroDecimal and threeDecimal list the ISO 4217 currencies whose minor unit
+// is not a hundredth.
+var (
+	zeroDecimal = map[Currency]bool{
+		"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+		"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "UYI": true,
+		"VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+	}
+	threeDecimal = map[Currency]bool{
+		"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true,
+	}
+)
+
+// Valid reports whether c looks like an ISO 4217 code: three upper case
+// letters.
+func (c Currency) Valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponent is the number of decimals of the currency's minor unit.
+func (c Currency) Exponent() int {
+	switch {
+	case zeroDecimal[c]:
+		return 0
+	case threeDecimal[c]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Money is an amount in minor units of Currency; 1250 IDR is 12.50 IDR.
+type Money struct {
+	Amount int64
+	Currency Currency
+}
+
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal amount such as "12.50" in currency. It refuses
+// more decimals than the currency has.
+func Parse(s string, currency Currency) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	digits := strings.TrimPrefix(s, "-")
+	negative := len(digits) < len(s)
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	exp := currency.Exponent()
+	if whole == "" || len(frac) > exp || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	amount, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return New(amount, currency), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount with the currency's decimals, e.g. "12.50".
+func (m Money) Decimal() string {
+	exp := m.Currency.Exponent()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Add returns m + o; both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, o)
+	}
+	return New(sum, m.Currency), nil
+}
+
+// Sub returns m - o; both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	diff := m.Amount - o.Amount
+	if (o.Amount > 0 && diff > m.Amount) || (o.Amount < 0 && diff < m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrOverflow, m, o)
+	}
+	return New(diff, m.Currency), nil
+}
+
+// Mul returns m times n, e.g. a ticket price times the number of seats.
+func (m Money) Mul(n int64) (Money, error) {
+	// The division misses only -1 * MinInt64, which wraps to MinInt64.
+	product := m.Amount * n
+	if m.Amount != 0 && (product/m.Amount != n || (m.Amount == -1 && n == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrOverflow, m, n)
+	}
+	return New(product, m.Currency), nil
+}
@@ -0,0 +1,120 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		currency Currency
+		want     int64
+		wantErr  error
+	}{
+		{name: "two decimals", in: "12.50", currency: "USD", want: 1250},
+		{name: "fewer decimals are padded", in: "12.5", currency: "USD", want: 1250},
+		{name: "whole amount", in: "12", currency: "IDR", want: 1200},
+		{name: "zero decimal currency", in: "1500", currency: "JPY", want: 1500},
+		{name: "three decimal currency", in: "1.005", currency: "KWD", want: 1005},
+		{name: "negative", in: "-0.05", currency: "USD", want: -5},
+		{name: "too many decimals", in: "0.001", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "decimals on a zero decimal currency", in: "1.5", currency: "JPY", wantErr: ErrInvalidAmount},
+		{name: "not a number", in: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "empty", in: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "bad currency", in: "1", currency: "usd", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in, tt.currency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.want, tt.currency), got)
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	assert.Equal(t, "12.50", New(1250, "USD").Decimal())
+	assert.Equal(t, "0.05", New(5, "USD").Decimal())
+	assert.Equal(t, "-0.05", New(-5, "USD").Decimal())
+	assert.Equal(t, "1500", New(1500, "JPY").Decimal())
+	assert.Equal(t, "1.005", New(1005, "KWD").Decimal())
+	assert.Equal(t, "12.50 IDR", New(1250, "IDR").String())
+}
+
+func TestArithmetic(t *testing.T) {
+	price := New(1999, "USD")
+	total, err := price.Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, New(5997, "USD"), total)
+
+	sum, err := price.Add(New(1, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, New(2000, "USD"), sum)
+
+	diff, err := price.Sub(New(2000, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, New(-1, "USD"), diff)
+
+	_, err = price.Add(New(1, "IDR"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestArithmetic_Overflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func() (Money, error)
+		want    Money
+		wantErr bool
+	}{
+		{name: "mul largest", op: func() (Money, error) { return New(math.MaxInt64/2, "USD").Mul(2) }, want: New(math.MaxInt64-1, "USD")},
+		{name: "mul overflow", op: func() (Money, error) { return New(math.MaxInt64/2+1, "USD").Mul(2) }, wantErr: true},
+		{name: "mul negative overflow", op: func() (Money, error) { return New(math.MinInt64/2-1, "USD").Mul(2) }, wantErr: true},
+		{name: "mul minus one by smallest", op: func() (Money, error) { return New(-1, "USD").Mul(math.MinInt64) }, wantErr: true},
+		{name: "mul smallest by minus one", op: func() (Money, error) { return New(math.MinInt64, "USD").Mul(-1) }, wantErr: true},
+		{name: "mul zero", op: func() (Money, error) { return New(0, "USD").Mul(math.MaxInt64) }, want: New(0, "USD")},
+		{name: "add overflow", op: func() (Money, error) { return New(math.MaxInt64, "USD").Add(New(1, "USD")) }, wantErr: true},
+		{name: "add negative overflow", op: func() (Money, error) { return New(math.MinInt64, "USD").Add(New(-1, "USD")) }, wantErr: true},
+		{name: "sub overflow", op: func() (Money, error) { return New(math.MinInt64, "USD").Sub(New(1, "USD")) }, wantErr: true},
+		{name: "sub negative overflow", op: func() (Money, error) { return New(math.MaxInt64, "USD").Sub(New(-1, "USD")) }, wantErr: true},
+		{name: "sub to smallest", op: func() (Money, error) { return New(-1, "USD").Sub(New(math.MaxInt64, "USD")) }, want: New(math.MinInt64, "USD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrOverflow)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(New(1250, "IDR"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"12.50","currency":"IDR"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, New(1250, "IDR"), m)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":12.5,"currency":"USD"}`), &m))
+	assert.Equal(t, New(1250, "USD"), m)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"12.505","currency":"USD"}`), &m), ErrInvalidAmount)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"1"}`), &m), ErrInvalidCurrency)
+}
@@ -0,0 +1,131 @@
+package rrule
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=2;COUNT=5",
+		"FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20261231T235959Z",
+		"FREQ=MONTHLY;INTERVAL=3",
+	} {
+		r, err := Parse(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, s, r.String())
+	}
+}
+
+func TestParse_Normalizes(t *testing.T) {
+	r, err := Parse("RRULE:freq=weekly;byday=fr,mo;interval=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR", r.String())
+
+	r, err = Parse("FREQ=DAILY;UNTIL=20261231")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), r.Until)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231",
+		"FREQ=DAILY;UNTIL=31-12-2026",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	} {
+		_, err := Parse(s)
+		assert.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestAll_Daily(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;INTERVAL=2;COUNT=3")
+	start := time.Date(2026, 1, 30, 19, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		start,
+		time.Date(2026, 2, 1, 19, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 3, 19, 0, 0, 0, time.UTC),
+	}, slices.Collect(r.All(start)))
+}
+
+func TestAll_WeeklyByDay(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=4")
+	// A Thursday: the Monday of its week is before the start and skipped.
+	start := time.Date(2026, 3, 5, 18, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		start,
+		time.Date(2026, 3, 16, 18, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 19, 18, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 30, 18, 30, 0, 0, time.UTC),
+	}, slices.Collect(r.All(start)))
+}
+
+func TestAll_MonthlySkipsShortMonths(t *testing.T) {
+	r, _ := Parse("FREQ=MONTHLY;COUNT=3")
+	start := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		start,
+		time.Date(2026, 3, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, 5, 31, 10, 0, 0, 0, time.UTC),
+	}, slices.Collect(r.All(start)))
+}
+
+func TestAll_UntilIsInclusive(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;UNTIL=20260317T190000Z")
+	start := time.Date(2026, 3, 3, 19, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		start,
+		time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 17, 19, 0, 0, 0, time.UTC),
+	}, slices.Collect(r.All(start)))
+}
+
+func TestAll_KeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	r, _ := Parse("FREQ=WEEKLY;COUNT=2")
+	start := time.Date(2026, 3, 23, 20, 0, 0, 0, loc)
+
+	got := slices.Collect(r.All(start))
+	assert.Equal(t, 20, got[1].Hour())
+	assert.Equal(t, 7*24*time.Hour-time.Hour, got[1].Sub(got[0]))
+}
+
+func TestBetween_Unbounded(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY")
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	got := r.Between(start, start.AddDate(0, 0, 10), start.AddDate(0, 0, 13))
+	assert.Equal(t, []time.Time{
+		start.AddDate(0, 0, 10),
+		start.AddDate(0, 0, 11),
+		start.AddDate(0, 0, 12),
+	}, got)
+}
+
+func TestShift_RotatesWeekdays(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;BYDAY=MO,SA;COUNT=4")
+
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU,SU;COUNT=4", r.Shift(1).String())
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=FR,SU;COUNT=4", r.Shift(-1).String())
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,SA;COUNT=4", r.String())
+}
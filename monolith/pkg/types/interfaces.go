@@ -21,4 +21,15 @@ type BookingReader interface {
 
 type SimulatePayment interface {
 	SimulatePayment(ctx context.Context, bookData *commonDTO.SimulateBookingPayment) (*commonDTO.PaymentDTO, error)
+}
+
+// PendingBookingFailer fails a pending booking and gives its seats back.
+type PendingBookingFailer interface {
+	FailPending(ctx context.Context, bookingID uint, reason string) error
+}
+
+// SeatReleaseListener is told when seats of an event are given back, so they
+// can be offered to its waitlist.
+type SeatReleaseListener interface {
+	SeatsReleased(ctx context.Context, eventID uint)
 }
\ No newline at end of file
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, id := range []uint{1, 42, 1 << 40} {
+		got, err := DecodeCursor(EncodeCursor(id))
+		assert.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+}
+
+func TestDecodeCursor_Empty(t *testing.T) {
+	got, err := DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), got)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"%%%", EncodeCursor(0), "bm90LWEtbnVtYmVy"} {
+		_, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestTimeCursor_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 10, 19, 30, 0, 250, time.UTC)
+	gotTime, gotID, err := DecodeTimeCursor(EncodeTimeCursor(at, 42))
+	assert.NoError(t, err)
+	assert.True(t, at.Equal(gotTime))
+	assert.Equal(t, uint(42), gotID)
+}
+
+func TestDecodeTimeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"%%%", EncodeCursor(42), EncodeTimeCursor(time.Now(), 0)} {
+		_, _, err := DecodeTimeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/anrisys/quicket/pkg/money"
 	tokenGenerator "github.com/anrisys/quicket/pkg/token"
 	"github.com/anrisys/quicket/test/test_utils"
 )
@@ -224,4 +225,346 @@ func validateBookingSuccessCreate(t *testing.T, actualResponse, expectedResponse
 	if !ok || expiredAt == "" {
 		t.Errorf("expected booking 'expired_at' to be a non-empty string, got %v", actualBooking["expired_at"])
 	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	s := test_utils.NewTestServer()
+	defer s.Close()
+
+	testUser, err := test_utils.CreateTestUser(s.App.Config)
+	if err != nil {
+		t.Logf("failed to seed user data %v", err)
+	}
+
+	tg := tokenGenerator.NewGenerator(s.App.Config)
+	tokenTest, err := tg.GenerateToken(testUser.PublicID, testUser.Role)
+	if err != nil {
+		t.Logf("failed to generate test user token %v", err)
+	}
+	validEvent, err := test_utils.CreateTestEvent(s.App.Config, "Cancellable Event", testUser.ID)
+	if err != nil {
+		t.Logf("failed to seed event data %v", err)
+	}
+	defer test_utils.CleanupTestUser(s.App.Config, testUser.Email)
+	defer test_utils.CleanupTestEvent(s.App.Config, validEvent.Title)
+
+	resp, err := s.MakeRequest("POST", "/api/v1/bookings/"+validEvent.PublicID, tokenTest, map[string]any{"seats": uint(2)})
+	if err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+	var created map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	bookingID, _ := created["booking"].(map[string]any)["id"].(string)
+
+	tests := []struct{
+		name                 string
+		token                string
+		bookingID            string
+		expectedStatus       int
+		expectedResponseBody map[string]any
+		expectError          bool
+	} {
+		{
+			name: "cancel booking success",
+			token: tokenTest,
+			bookingID: bookingID,
+			expectedStatus: http.StatusOK,
+			expectedResponseBody: map[string]any{
+				"code": "SUCCESS",
+				"message": "Booking cancelled successfully",
+			},
+			expectError: false,
+		},
+		{
+			name: "cancel booking failed - already cancelled",
+			token: tokenTest,
+			bookingID: bookingID,
+			expectedStatus: http.StatusConflict,
+			expectedResponseBody: map[string]any{
+				"code": "CONFLICT_ERROR",
+				"message": "booking can not be cancelled anymore",
+			},
+			expectError: true,
+		},
+		{
+			name: "cancel booking failed - non-existent booking",
+			token: tokenTest,
+			bookingID: "non-existent-id",
+			expectedStatus: http.StatusNotFound,
+			expectedResponseBody: map[string]any{
+				"code": "NOT_FOUND",
+				"message": "booking not found",
+			},
+			expectError: true,
+		},
+		{
+			name: "cancel booking failed - unauthorized",
+			token: "",
+			bookingID: bookingID,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponseBody: map[string]any{
+				"code": "UNAUTHORIZED",
+				"message": "Authentication required",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.MakeRequest("POST", "/api/v1/bookings/"+tt.bookingID+"/cancel", tt.token, nil)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Test: %s", tt.expectedStatus, resp.StatusCode, tt.name)
+			}
+
+			var responseBody map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !tt.expectError {
+				test_utils.ValidateGenericSuccess(t, responseBody)
+				actualBooking, ok := responseBody["booking"].(map[string]any)
+				if !ok {
+					t.Fatalf("expected 'booking' field to be a map, got %T", responseBody["booking"])
+				}
+				if actualBooking["status"] != "cancelled" {
+					t.Errorf("expected booking status cancelled, got %v", actualBooking["status"])
+				}
+			} else {
+				test_utils.ValidateErrorResponse(t, responseBody, tt.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestCreateBookingWithTicketType(t *testing.T) {
+	s := test_utils.NewTestServer()
+	defer s.Close()
+
+	testUser, err := test_utils.CreateTestUser(s.App.Config)
+	if err != nil {
+		t.Logf("failed to seed user data %v", err)
+	}
+
+	tg := tokenGenerator.NewGenerator(s.App.Config)
+	tokenTest, err := tg.GenerateToken(testUser.PublicID, testUser.Role)
+	if err != nil {
+		t.Logf("failed to generate test user token %v", err)
+	}
+	tieredEvent, err := test_utils.CreateTestEvent(s.App.Config, "Tiered Event", testUser.ID)
+	if err != nil {
+		t.Logf("failed to seed event data %v", err)
+	}
+	vip, err := test_utils.CreateTestTicketType(s.App.Config, tieredEvent.ID, "VIP", money.New(5000, money.DefaultCurrency), 10, 4)
+	if err != nil {
+		t.Logf("failed to seed ticket type data %v", err)
+	}
+	defer test_utils.CleanupTestUser(s.App.Config, testUser.Email)
+	defer test_utils.CleanupTestEvent(s.App.Config, tieredEvent.Title)
+
+	tests := []struct{
+		name                 string
+		payload              map[string]any
+		expectedStatus       int
+		expectedResponseBody map[string]any
+		expectError          bool
+	} {
+		{
+			name: "create booking success - price from ticket type",
+			payload: map[string]any{
+				"seats": uint(2),
+				"ticket_type_id": vip.PublicID,
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponseBody: map[string]any{
+				"total_price": "100.00",
+			},
+			expectError: false,
+		},
+		{
+			name: "create booking failed - ticket type required",
+			payload: map[string]any{
+				"seats": uint(1),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponseBody: map[string]any{
+				"code": "VALIDATION_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "create booking failed - above per-order limit",
+			payload: map[string]any{
+				"seats": uint(5),
+				"ticket_type_id": vip.PublicID,
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponseBody: map[string]any{
+				"code": "VALIDATION_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "create booking failed - unknown ticket type",
+			payload: map[string]any{
+				"seats": uint(1),
+				"ticket_type_id": "non-existent-id",
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponseBody: map[string]any{
+				"code": "NOT_FOUND",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.MakeRequest("POST", "/api/v1/bookings/"+tieredEvent.PublicID, tokenTest, tt.payload)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Test: %s", tt.expectedStatus, resp.StatusCode, tt.name)
+			}
+
+			var responseBody map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !tt.expectError {
+				test_utils.ValidateGenericSuccess(t, responseBody)
+				actualBooking, ok := responseBody["booking"].(map[string]any)
+				if !ok {
+					t.Fatalf("expected 'booking' field to be a map, got %T", responseBody["booking"])
+				}
+				totalPrice, _ := actualBooking["total_price"].(map[string]any)
+				if totalPrice["amount"] != tt.expectedResponseBody["total_price"] {
+					t.Errorf("expected total_price %v, got %v", tt.expectedResponseBody["total_price"], actualBooking["total_price"])
+				}
+			} else {
+				test_utils.ValidateErrorResponse(t, responseBody, tt.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestCreateBookingWithSeatMap(t *testing.T) {
+	s := test_utils.NewTestServer()
+	defer s.Close()
+
+	testUser, err := test_utils.CreateTestUser(s.App.Config)
+	if err != nil {
+		t.Logf("failed to seed user data %v", err)
+	}
+
+	tg := tokenGenerator.NewGenerator(s.App.Config)
+	tokenTest, err := tg.GenerateToken(testUser.PublicID, testUser.Role)
+	if err != nil {
+		t.Logf("failed to generate test user token %v", err)
+	}
+	seatedEvent, err := test_utils.CreateTestEvent(s.App.Config, "Seated Event", testUser.ID)
+	if err != nil {
+		t.Logf("failed to seed event data %v", err)
+	}
+	seats, err := test_utils.CreateTestSeatMap(s.App.Config, seatedEvent.ID, 4)
+	if err != nil {
+		t.Logf("failed to seed seat map data %v", err)
+	}
+	defer test_utils.CleanupTestUser(s.App.Config, testUser.Email)
+	defer test_utils.CleanupTestEvent(s.App.Config, seatedEvent.Title)
+
+	tests := []struct{
+		name                 string
+		payload              map[string]any
+		expectedStatus       int
+		expectedResponseBody map[string]any
+		expectError          bool
+	} {
+		{
+			name: "create booking success - picked seats",
+			payload: map[string]any{
+				"seat_ids": []string{seats[0].PublicID, seats[1].PublicID},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponseBody: map[string]any{
+				"seats": float64(2),
+			},
+			expectError: false,
+		},
+		{
+			name: "create booking failed - seat already held",
+			payload: map[string]any{
+				"seat_ids": []string{seats[1].PublicID, seats[2].PublicID},
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponseBody: map[string]any{
+				"code": "CONFLICT_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "create booking failed - seat selection required",
+			payload: map[string]any{
+				"seats": uint(1),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponseBody: map[string]any{
+				"code": "VALIDATION_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "create booking failed - unknown seat",
+			payload: map[string]any{
+				"seat_ids": []string{"non-existent-id"},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponseBody: map[string]any{
+				"code": "NOT_FOUND",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.MakeRequest("POST", "/api/v1/bookings/"+seatedEvent.PublicID, tokenTest, tt.payload)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Test: %s", tt.expectedStatus, resp.StatusCode, tt.name)
+			}
+
+			var responseBody map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !tt.expectError {
+				test_utils.ValidateGenericSuccess(t, responseBody)
+				actualBooking, ok := responseBody["booking"].(map[string]any)
+				if !ok {
+					t.Fatalf("expected 'booking' field to be a map, got %T", responseBody["booking"])
+				}
+				if actualBooking["seats"] != tt.expectedResponseBody["seats"] {
+					t.Errorf("expected seats %v, got %v", tt.expectedResponseBody["seats"], actualBooking["seats"])
+				}
+			} else {
+				test_utils.ValidateErrorResponse(t, responseBody, tt.expectedResponseBody)
+			}
+		})
+	}
 }
\ No newline at end of file
@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	tokenGenerator "github.com/anrisys/quicket/pkg/token"
+	"github.com/anrisys/quicket/test/test_utils"
+)
+
+func TestJoinWaitlist(t *testing.T) {
+	s := test_utils.NewTestServer()
+	defer s.Close()
+
+	testUser, err := test_utils.CreateTestUser(s.App.Config)
+	if err != nil {
+		t.Logf("failed to seed user data %v", err)
+	}
+
+	tg := tokenGenerator.NewGenerator(s.App.Config)
+	tokenTest, err := tg.GenerateToken(testUser.PublicID, testUser.Role)
+	if err != nil {
+		t.Logf("failed to generate test user token %v", err)
+	}
+	limitedEvent, err := test_utils.CreateLimitedSeatsEventTest(s.App.Config, "Waitlist Limited Event", testUser.ID)
+	if err != nil {
+		t.Logf("failed to seed limited event data %v", err)
+	}
+	openEvent, err := test_utils.CreateTestEvent(s.App.Config, "Waitlist Open Event", testUser.ID)
+	if err != nil {
+		t.Logf("failed to seed event data %v", err)
+	}
+	defer test_utils.CleanupTestUser(s.App.Config, testUser.Email)
+	defer test_utils.CleanupTestEvent(s.App.Config, limitedEvent.Title)
+	defer test_utils.CleanupTestEvent(s.App.Config, openEvent.Title)
+
+	tests := []struct{
+		name                 string
+		eventID              string
+		payload              map[string]any
+		expectedStatus       int
+		expectedResponseBody map[string]any
+		expectError          bool
+	} {
+		{
+			name: "join waitlist success - first in line",
+			eventID: limitedEvent.PublicID,
+			payload: map[string]any{
+				"seats": uint(6),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponseBody: map[string]any{
+				"status": "waiting",
+				"position": float64(1),
+			},
+			expectError: false,
+		},
+		{
+			name: "join waitlist failed - already waitlisted",
+			eventID: limitedEvent.PublicID,
+			payload: map[string]any{
+				"seats": uint(6),
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponseBody: map[string]any{
+				"code": "CONFLICT_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "join waitlist failed - seats still available",
+			eventID: openEvent.PublicID,
+			payload: map[string]any{
+				"seats": uint(2),
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResponseBody: map[string]any{
+				"code": "CONFLICT_ERROR",
+			},
+			expectError: true,
+		},
+		{
+			name: "join waitlist failed - zero seats",
+			eventID: limitedEvent.PublicID,
+			payload: map[string]any{
+				"seats": uint(0),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponseBody: map[string]any{
+				"code": "VALIDATION_ERROR",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.MakeRequest("POST", "/api/v1/events/"+tt.eventID+"/waitlist", tokenTest, tt.payload)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Test: %s", tt.expectedStatus, resp.StatusCode, tt.name)
+			}
+
+			var responseBody map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !tt.expectError {
+				test_utils.ValidateGenericSuccess(t, responseBody)
+				actualEntry, ok := responseBody["entry"].(map[string]any)
+				if !ok {
+					t.Fatalf("expected 'entry' field to be a map, got %T", responseBody["entry"])
+				}
+				for key, expected := range tt.expectedResponseBody {
+					if actualEntry[key] != expected {
+						t.Errorf("expected %s %v, got %v", key, expected, actualEntry[key])
+					}
+				}
+			} else {
+				test_utils.ValidateErrorResponse(t, responseBody, tt.expectedResponseBody)
+			}
+		})
+	}
+}
\ No newline at end of file
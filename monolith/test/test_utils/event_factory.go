@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/anrisys/quicket/internal/event"
+	"github.com/anrisys/quicket/internal/seatmap"
 	"github.com/anrisys/quicket/pkg/config"
 	"github.com/anrisys/quicket/pkg/database"
+	"github.com/anrisys/quicket/pkg/money"
 	"github.com/anrisys/quicket/pkg/security"
 )
 
@@ -36,6 +38,7 @@ func CreateTestEvent(cfg *config.AppConfig, title string, userID uint) (*event.E
 		MaxSeats: 100,
 		AvailableSeats: 100,
 		OrganizerID: userID,
+		Currency: money.DefaultCurrency,
 	}
 
 	if err := gormDB.Create(event).Error; err != nil {
@@ -80,6 +83,7 @@ func CreatePastEventTest(cfg *config.AppConfig, title string, userID uint) (*eve
 		MaxSeats: 100,
 		AvailableSeats: 100,
 		OrganizerID: userID,
+		Currency: money.DefaultCurrency,
 	}
 
 	if err := gormDB.Create(pastEvent).Error; err != nil {
@@ -115,6 +119,7 @@ func CreateLimitedSeatsEventTest(cfg *config.AppConfig, title string, userID uin
 		MaxSeats: 5,
 		AvailableSeats: 5,
 		OrganizerID: userID,
+		Currency: money.DefaultCurrency,
 	}
 
 	if err := gormDB.Create(pastEvent).Error; err != nil {
@@ -122,4 +127,74 @@ func CreateLimitedSeatsEventTest(cfg *config.AppConfig, title string, userID uin
 	}
 
 	return pastEvent, nil
+}
+
+func CreateTestTicketType(cfg *config.AppConfig, eventID uint, name string, price money.Money, quota uint64, maxPerOrder uint) (*event.TicketType, error) {
+	gormDB, err := database.MySQLDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize db: %w", err)
+	}
+
+	as := security.NewAccountSecurity(cfg)
+	publicID, err := as.GeneratePublicID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public id for test ticket type: %w", err)
+	}
+
+	tt := &event.TicketType{
+		PublicID: publicID,
+		EventID: eventID,
+		Name: name,
+		Price: price,
+		Currency: price.Currency,
+		Quota: quota,
+		Available: quota,
+		MaxPerOrder: maxPerOrder,
+	}
+
+	if err := gormDB.Create(tt).Error; err != nil {
+		return nil, fmt.Errorf("failed to create ticket type test: %w", err)
+	}
+
+	return tt, nil
+}
+
+func CreateTestSeatMap(cfg *config.AppConfig, eventID uint, seats int) ([]seatmap.Seat, error) {
+	gormDB, err := database.MySQLDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize db: %w", err)
+	}
+
+	as := security.NewAccountSecurity(cfg)
+	sectionPublicID, err := as.GeneratePublicID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate public id for test section: %w", err)
+	}
+
+	row := seatmap.Row{Label: "A"}
+	for i := 1; i <= seats; i++ {
+		seatPublicID, err := as.GeneratePublicID(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate public id for test seat: %w", err)
+		}
+		row.Seats = append(row.Seats, seatmap.Seat{
+			PublicID: seatPublicID,
+			EventID: eventID,
+			Label: fmt.Sprint(i),
+			Status: seatmap.SeatAvailable,
+		})
+	}
+
+	section := &seatmap.Section{
+		PublicID: sectionPublicID,
+		EventID: eventID,
+		Name: "Floor",
+		Rows: []seatmap.Row{row},
+	}
+
+	if err := gormDB.Create(section).Error; err != nil {
+		return nil, fmt.Errorf("failed to create seat map test: %w", err)
+	}
+
+	return section.Rows[0].Seats, nil
 }
\ No newline at end of file
@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/anrisys/quicket/user-service/pkg/di"
+	"github.com/anrisys/quicket/user-service/pkg/lifecycle"
 	"github.com/anrisys/quicket/user-service/router"
 )
 
@@ -31,11 +33,27 @@ func main() {
     if err != nil {
         log.Fatalf("Failed to initialize app: %v", err)
     }
+
+    lc := lifecycle.New(app.Config.Server.ShutdownTimeout, app.Logger)
+
+    sqlDB, err := app.DB.DB()
+    if err != nil {
+        log.Fatalf("Failed to get database pool: %v", err)
+    }
+    lc.OnClose("mysql", sqlDB.Close)
+    lc.OnClose("rabbitmq", app.RabbitMQ.Close)
+    lc.OnClose("rabbitmq publisher", app.Publisher.Close)
+
+    lc.Go("outbox relay", app.Relay.Run)
     
     r := router.SetupRouter(app)
     
-    addr := fmt.Sprintf(":%s", app.Config.Server.Port)
-	if err := r.Run(addr); err != nil {
-        log.Fatalf("Server failed :%v", err)
+    lc.Serve(&http.Server{
+        Addr: fmt.Sprintf(":%s", app.Config.Server.Port),
+        Handler: r,
+    })
+
+    if err := lc.Wait(); err != nil {
+        log.Fatalf("Server stopped with errors: %v", err)
     }
 }
\ No newline at end of file
@@ -11,7 +11,7 @@ type RegisterUserRequest struct {
 	Email                string `json:"email" binding:"required,email"`
 	Password             string `json:"password" binding:"required,min=8,password"`
 	PasswordConfirmation string `json:"password_confirmation" binding:"required,eqfield=Password"`
-	Role                 string `json:"role" binding:"omitempty,oneof=user organizer admin"`
+	Role                 string `json:"role" binding:"omitempty,oneof=user organizer staff admin"`
 }
 
 type LoginUserRequest struct {
@@ -209,6 +209,36 @@ func (h *UserHandler) GetUserByPublicID(c *gin.Context)  {
 	c.JSON(http.StatusOK, response)
 }
 
+// DeleteUser godoc
+// @Summary Delete user
+// @Description Delete a user account (the user themselves or an admin only)
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param publicID path string true "User Public ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 401 {object} errs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errs.ErrorResponse "Forbidden"
+// @Failure 404 {object} errs.ErrorResponse "User not found"
+// @Failure 500 {object} errs.ErrorResponse "Internal server error"
+// @Router /api/v1/users/{publicID} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	publicID := c.Param("publicID")
+
+	err := h.srv.Delete(ctx, publicID, c.GetString("publicID"), c.GetString("role"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response := ResponseSuccess{
+		Code: "SUCCESS",
+		Message: "User deleted successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // HealthCheck godoc
 // @Summary Health Check
 // @Description Check if the service is healthy
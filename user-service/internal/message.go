@@ -0,0 +1,19 @@
+package internal
+
+// Messages announcing changes to users are published to UsersExchange.
+// Their shape is shared with the consumers in booking-service.
+const (
+	UsersExchange = "user.exchange"
+
+	RoutingKeyUserCreated = "user.created"
+	RoutingKeyUserDeleted = "user.deleted"
+)
+
+type UserCreatedMessage struct {
+	ID       uint   `json:"id"`
+	PublicID string `json:"public_id"`
+}
+
+type UserDeletedMessage struct {
+	ID uint `json:"id"`
+}
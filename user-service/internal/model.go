@@ -2,12 +2,14 @@ package internal
 
 import "gorm.io/gorm"
 
+// User is an account. Email is unique among users that are not deleted, so
+// the email of a deleted user can sign up again.
 type User struct {
 	gorm.Model
 	PublicID string `gorm:"column:public_id;type:char(36);uniqueIndex"`
-	Email string `gorm:"column:email;uniqueIndex"`
+	Email string `gorm:"column:email"`
 	Password string `gorm:"column:password;size:255"`
-	Role string `gorm:"column:role;type:ENUM('user', 'organizer', 'admin');default:'user'"`
+	Role string `gorm:"column:role;type:ENUM('user', 'organizer', 'staff', 'admin');default:'user'"`
 }
 
 func (u *User) TableName() string {
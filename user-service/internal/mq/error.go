@@ -1,8 +0,0 @@
-package mq
-
-import "errors"
-
-var (
-	ErrFailedToDeclareExchange = errors.New("failed to declare exchange")
-	ErrFailedToDeclareQueue = errors.New("failed to declare queue")
-)
\ No newline at end of file
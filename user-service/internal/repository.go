@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/anrisys/quicket/user-service/pkg/errs"
+	"github.com/anrisys/quicket/user-service/pkg/outbox"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
@@ -18,6 +19,7 @@ type UserRepositoryInterface interface {
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	EmailExists(ctx context.Context, email string) bool
 	GetUserPrimaryID(ctx context.Context, publicID string) (*uint, error)
+	Delete(ctx context.Context, user *User) error
 }
 
 type UserRepository struct {
@@ -32,8 +34,18 @@ func NewUserRepository(db *gorm.DB, logger zerolog.Logger) *UserRepository {
 	}
 }
 
+// Create saves user and queues the user.created message in the same
+// transaction.
 func (r *UserRepository) Create(ctx context.Context, user *User) error {
-	err := r.db.WithContext(ctx).Create(user).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, UsersExchange, RoutingKeyUserCreated, UserCreatedMessage{
+			ID:       user.ID,
+			PublicID: user.PublicID,
+		})
+	})
     if err != nil {
         r.logger.Error().Err(err).Msg("DB operation failed")
         
@@ -128,6 +140,26 @@ func (r *UserRepository) EmailExists(ctx context.Context, email string) bool {
 	return count > 0
 }
 
+// Delete soft-deletes user and queues the user.deleted message in the same
+// transaction.
+func (r *UserRepository) Delete(ctx context.Context, user *User) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(user).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, UsersExchange, RoutingKeyUserDeleted, UserDeletedMessage{
+			ID: user.ID,
+		})
+	})
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("public_id", user.PublicID).
+			Msg("failed to delete user")
+		return fmt.Errorf("%w: %v", ErrDB, err)
+	}
+	return nil
+}
+
 func isConnectionError(err error) bool {
     // Checking connection error
     return strings.Contains(err.Error(), "connection refused") || 
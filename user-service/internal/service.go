@@ -17,6 +17,7 @@ type UserServiceInterface interface {
 	FindUserById(ctx context.Context, id int) (*UserDTO, error)
 	FindUserByPublicID(ctx context.Context, publicID string) (*UserDTO, error)
 	GetUserPrimaryID(ctx context.Context, publicID string) (*uint, error)
+	Delete(ctx context.Context, publicID, requesterPublicID, requesterRole string) error
 }
 
 type UserService struct {
@@ -137,6 +138,32 @@ func (s *UserService) GetUserPrimaryID(ctx context.Context, publicID string) (*u
 	return userID, nil
 }
 
+// Delete removes the user with publicID. Users may delete themselves;
+// admins may delete anyone.
+func (s *UserService) Delete(ctx context.Context, publicID, requesterPublicID, requesterRole string) error {
+	if requesterPublicID != publicID && requesterRole != "admin" {
+		return errs.NewForbiddenError("only the user or an admin can delete this account")
+	}
+
+	user, err := s.repo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return errs.NewErrNotFound("user")
+		}
+		return errs.ErrInternal
+	}
+
+	if err := s.repo.Delete(ctx, user); err != nil {
+		return errs.NewInternalError("failed to delete user", err)
+	}
+
+	s.logger.Info().Ctx(ctx).
+		Str("user_public_id", publicID).
+		Str("deleted_by", requesterPublicID).
+		Msg("User deleted")
+	return nil
+}
+
 func (s *UserService) toUserDTO(user *User) *UserDTO {
 	return &UserDTO{
 		ID:       int(user.ID),
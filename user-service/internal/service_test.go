@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anrisys/quicket/user-service/pkg/errs"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// userRepo stands in for the user repository. Only what the tests call is
+// implemented.
+type userRepo struct {
+	UserRepositoryInterface
+	users map[string]*User
+	// deleteErr is what Delete returns; deleted is the user it was called
+	// with.
+	deleteErr error
+	deleted   *User
+}
+
+func (r *userRepo) FindByPublicID(ctx context.Context, publicID string) (*User, error) {
+	user, ok := r.users[publicID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *userRepo) Delete(ctx context.Context, user *User) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	r.deleted = user
+	return nil
+}
+
+func TestUserService_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		publicID      string
+		requester     string
+		requesterRole string
+		deleteErr     error
+		wantStatus    int
+	}{
+		{name: "user deletes themselves", publicID: "u-1", requester: "u-1", requesterRole: "user"},
+		{name: "admin deletes another user", publicID: "u-1", requester: "u-9", requesterRole: "admin"},
+		{name: "user deletes another user", publicID: "u-1", requester: "u-2", requesterRole: "user", wantStatus: http.StatusForbidden},
+		{name: "organizer deletes another user", publicID: "u-1", requester: "u-2", requesterRole: "organizer", wantStatus: http.StatusForbidden},
+		{name: "unknown user", publicID: "u-3", requester: "u-9", requesterRole: "admin", wantStatus: http.StatusNotFound},
+		{name: "database error", publicID: "u-1", requester: "u-1", requesterRole: "user", deleteErr: ErrDB, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &userRepo{
+				users:     map[string]*User{"u-1": {Model: gorm.Model{ID: 1}, PublicID: "u-1"}},
+				deleteErr: tt.deleteErr,
+			}
+			s := NewUserService(repo, zerolog.Nop(), nil, nil)
+
+			err := s.Delete(context.Background(), tt.publicID, tt.requester, tt.requesterRole)
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("Delete() error = %v", err)
+				}
+				if repo.deleted == nil || repo.deleted.PublicID != tt.publicID {
+					t.Errorf("deleted %+v, want %s", repo.deleted, tt.publicID)
+				}
+				return
+			}
+			var appErr *errs.AppError
+			if !errors.As(err, &appErr) || appErr.Status != tt.wantStatus {
+				t.Fatalf("Delete() error = %v, want status %d", err, tt.wantStatus)
+			}
+			if repo.deleted != nil {
+				t.Errorf("deleted %+v, want none", repo.deleted)
+			}
+		})
+	}
+}
+
+// The messages are decoded by booking-service, so their JSON must not
+// change shape.
+func TestUserMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  any
+		want string
+	}{
+		{name: "created", msg: UserCreatedMessage{ID: 1, PublicID: "u-1"}, want: `{"id":1,"public_id":"u-1"}`},
+		{name: "deleted", msg: UserDeletedMessage{ID: 1}, want: `{"id":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+UPDATE users SET role = 'user' WHERE role = 'staff';
+ALTER TABLE users
+    MODIFY role ENUM('user', 'organizer', 'admin') NOT NULL DEFAULT 'user';
\ No newline at end of file
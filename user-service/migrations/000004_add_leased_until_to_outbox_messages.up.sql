@@ -0,0 +1,2 @@
+ALTER TABLE `outbox_messages`
+    ADD COLUMN `leased_until` DATETIME(3) NULL AFTER `available_at`;
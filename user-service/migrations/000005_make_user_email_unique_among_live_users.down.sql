@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP INDEX `idx_users_live_email`,
+    DROP COLUMN `live_email`,
+    ADD UNIQUE INDEX `email` (`email`);
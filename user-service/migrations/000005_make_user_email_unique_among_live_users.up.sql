@@ -0,0 +1,8 @@
+-- MySQL has no partial indexes, so uniqueness of the email among users that
+-- are not deleted goes through a generated column that is NULL for deleted
+-- users. A deleted user's email can then be registered again.
+ALTER TABLE users
+    DROP INDEX `email`,
+    ADD COLUMN `live_email` VARCHAR(255)
+        GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `email`, NULL)) VIRTUAL,
+    ADD UNIQUE INDEX `idx_users_live_email` (`live_email`);
@@ -7,4 +7,5 @@ type Config struct {
 	Log            *LogConfig
 	JWT            *JWTConfig
 	RabbitMQConfig *RabbitMQConfig
+	Outbox         *OutboxConfig
 }
\ No newline at end of file
@@ -10,6 +10,11 @@ func Load() (*Config, error) {
 	viper.AddConfigPath("../..")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("USER_SERVICE_SHUTDOWN_TIMEOUT", "10s")
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "5m")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, err
@@ -46,6 +51,11 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	var outboxConfig OutboxConfig
+	if err := viper.Unmarshal(&outboxConfig); err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Bcrypt: &bcryptConfig,
 		Server: &serverConfig,
@@ -53,6 +63,7 @@ func Load() (*Config, error) {
 		Log: &logConfig,
 		JWT: &jwtConfig,
 		RabbitMQConfig: &rabbitMQConfig,
+		Outbox: &outboxConfig,
 	}
 
 	if err := validateConfig(cfg); err != nil {
@@ -78,5 +89,8 @@ func validateConfig(config *Config) error {
 	if err := config.RabbitMQConfig.Validate(); err != nil {
 		return err
 	}
+	if err := config.Outbox.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
\ No newline at end of file
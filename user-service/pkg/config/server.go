@@ -1,14 +1,23 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type ServerConfig struct {
-	Port string `mapstructure:"USER_SERVICE_PORT"`
+	Port            string        `mapstructure:"USER_SERVICE_PORT"`
+	// ShutdownTimeout bounds draining requests and closing connections once
+	// the server is told to stop.
+	ShutdownTimeout time.Duration `mapstructure:"USER_SERVICE_SHUTDOWN_TIMEOUT"`
 }
 
 func (s *ServerConfig) Validate() error {
 	if s.Port == "" {
 		return errors.New("server port has not been set")
 	}
+	if s.ShutdownTimeout <= 0 {
+		return errors.New("server shutdown timeout must be greater than zero")
+	}
 	return nil
 }
\ No newline at end of file
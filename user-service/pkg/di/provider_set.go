@@ -4,6 +4,8 @@ import (
 	"github.com/anrisys/quicket/user-service/internal"
 	"github.com/anrisys/quicket/user-service/pkg/config"
 	"github.com/anrisys/quicket/user-service/pkg/database"
+	"github.com/anrisys/quicket/user-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/user-service/pkg/outbox"
 	"github.com/anrisys/quicket/user-service/pkg/security"
 	"github.com/anrisys/quicket/user-service/pkg/token"
 	"github.com/google/wire"
@@ -21,6 +23,8 @@ var (
 	)
 	UserAppProviderSet = wire.NewSet(
 		ConfigSet,
+		rabbitmq.SetUpProviderSet,
+		outbox.NewRelay,
 		internal.NewUserRepository,
 		internal.NewUserService,
 		internal.NewUserHandler,
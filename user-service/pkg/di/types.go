@@ -3,9 +3,18 @@ package di
 import (
 	"github.com/anrisys/quicket/user-service/internal"
 	"github.com/anrisys/quicket/user-service/pkg/config"
+	"github.com/anrisys/quicket/user-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/user-service/pkg/outbox"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
 type UserServiceApp struct {
-	Config  *config.Config
-	Handler *internal.UserHandler
+	Config    *config.Config
+	Logger    zerolog.Logger
+	DB        *gorm.DB
+	Handler   *internal.UserHandler
+	RabbitMQ  *rabbitmq.Client
+	Publisher *rabbitmq.Publisher
+	Relay     *outbox.Relay
 }
\ No newline at end of file
@@ -10,6 +10,8 @@ import (
 	"github.com/anrisys/quicket/user-service/internal"
 	"github.com/anrisys/quicket/user-service/pkg/config"
 	"github.com/anrisys/quicket/user-service/pkg/database"
+	"github.com/anrisys/quicket/user-service/pkg/mq/rabbitmq"
+	"github.com/anrisys/quicket/user-service/pkg/outbox"
 	"github.com/anrisys/quicket/user-service/pkg/security"
 	"github.com/anrisys/quicket/user-service/pkg/token"
 )
@@ -31,9 +33,17 @@ func InitializeUserServiceApp() (*UserServiceApp, error) {
 	tokenGenerator := token.NewTokenGenerator(configConfig)
 	userService := internal.NewUserService(userRepository, logger, accountSecurity, tokenGenerator)
 	userHandler := internal.NewUserHandler(userService, logger)
+	client := rabbitmq.NewClient(configConfig, logger)
+	publisher := rabbitmq.NewPublisher(client)
+	relay := outbox.NewRelay(db, publisher, configConfig, logger)
 	userServiceApp := &UserServiceApp{
-		Config:  configConfig,
-		Handler: userHandler,
+		Config:    configConfig,
+		Logger:    logger,
+		DB:        db,
+		Handler:   userHandler,
+		RabbitMQ:  client,
+		Publisher: publisher,
+		Relay:     relay,
 	}
 	return userServiceApp, nil
 }
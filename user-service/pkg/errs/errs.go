@@ -109,6 +109,19 @@ func NewConflictError(message string, internalErr ...error) *AppError {
 	return ae
 }
 
+func NewForbiddenError(message string, internalErr ...error) *AppError {
+	ae := &AppError{
+		Status:  http.StatusForbidden,
+		Code:    "FORBIDDEN",
+		Message: message,
+		Details: nil,
+	}
+	if len(internalErr) > 0 {
+		ae.Err = internalErr[0]
+	}
+	return ae
+}
+
 func NewServiceUnavailableError(message string, internalErr ...error) *AppError {
     ae := &AppError{
 		Status:  http.StatusServiceUnavailable,
@@ -0,0 +1,152 @@
+// Package lifecycle runs the HTTP server and the background tasks of a
+// service, and stops them in order when the process is told to terminate.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Manager shuts a service down in three steps, all within one deadline:
+// the HTTP servers stop accepting connections and drain the requests in
+// flight, the context of the background tasks is cancelled and they are
+// waited for, and then the closers run in reverse order of registration.
+// Tasks still running when the deadline passes are abandoned.
+type Manager struct {
+	timeout time.Duration
+	logger zerolog.Logger
+
+	ctx context.Context
+	cancel context.CancelFunc
+	tasks sync.WaitGroup
+	servers []*http.Server
+	closers []closer
+	failed chan error
+}
+
+type closer struct {
+	name string
+	close func() error
+}
+
+func New(timeout time.Duration, logger zerolog.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		timeout: timeout,
+		logger: logger,
+		ctx: ctx,
+		cancel: cancel,
+		failed: make(chan error, 1),
+	}
+}
+
+// Serve runs srv in the background until shutdown. A server that can not
+// listen triggers shutdown.
+func (m *Manager) Serve(srv *http.Server) {
+	m.servers = append(m.servers, srv)
+	go func() {
+		m.logger.Info().Str("addr", srv.Addr).Msg("http server started")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.fail(fmt.Errorf("http server: %w", err))
+		}
+	}()
+}
+
+// Go runs task in the background. Its context is cancelled when shutdown
+// starts; the task should then finish or hand back the work in hand and
+// return. A task that returns an error before that triggers shutdown.
+func (m *Manager) Go(name string, task func(ctx context.Context) error) {
+	m.tasks.Add(1)
+	go func() {
+		defer m.tasks.Done()
+		if err := task(m.ctx); err != nil && m.ctx.Err() == nil {
+			m.fail(fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		m.logger.Info().Str("task", name).Msg("background task stopped")
+	}()
+}
+
+// OnClose registers fn to release a resource once the servers and the
+// tasks have stopped. Resources are closed in reverse order of
+// registration, so a resource should be registered before the ones that
+// depend on it.
+func (m *Manager) OnClose(name string, fn func() error) {
+	m.closers = append(m.closers, closer{name: name, close: fn})
+}
+
+// Wait blocks until the process receives SIGINT or SIGTERM, or a server or
+// task fails, and then shuts down.
+func (m *Manager) Wait() error {
+	signals, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var cause error
+	select {
+	case <-signals.Done():
+		m.logger.Info().Msg("shutdown signal received")
+	case cause = <-m.failed:
+		m.logger.Error().Err(cause).Msg("shutting down after failure")
+	}
+	return errors.Join(cause, m.Shutdown())
+}
+
+// Shutdown stops the servers, then the tasks, then closes the resources.
+// It reports what did not stop in time or failed to close.
+func (m *Manager) Shutdown() error {
+	m.logger.Info().Dur("timeout", m.timeout).Msg("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+	for _, srv := range m.servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("http server: %w", err))
+		}
+	}
+
+	m.cancel()
+	stopped := make(chan struct{})
+	go func() {
+		m.tasks.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, errors.New("background tasks did not stop in time"))
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+			continue
+		}
+		m.logger.Info().Str("resource", c.name).Msg("closed")
+	}
+
+	err := errors.Join(errs...)
+	if err == nil {
+		m.logger.Info().Msg("shutdown complete")
+	}
+	return err
+}
+
+func (m *Manager) fail(err error) {
+	select {
+	case m.failed <- err:
+	default:
+		// Shutdown is already on its way.
+		m.logger.Error().Err(err).Msg("failure during shutdown")
+	}
+}
@@ -2,35 +2,64 @@ package rabbitmq
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/anrisys/quicket/user-service/pkg/config"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rs/zerolog"
 )
 
+// Client holds the connection to RabbitMQ. The connection is dialed on
+// first use and dialed again after it drops, so the service can start and
+// keep running while the broker is away.
 type Client struct {
-	conn *amqp.Connection
+	url    string
 	logger zerolog.Logger
-}
 
-func NewClient(config *config.Config, logger zerolog.Logger) (*Client, error) {
-	conn, err := amqp.Dial(config.RabbitMQConfig.URL())
-	if err != nil {
-		logger.Error().Err(err).Msg("failed to connect to RabbitMQ")
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
-	logger.Info().Msg("RabbitMQ connected successfully")
+	mu   sync.Mutex
+	conn *amqp.Connection
+}
 
+func NewClient(cfg *config.Config, logger zerolog.Logger) *Client {
 	return &Client{
-		conn: conn,
+		url:    cfg.RabbitMQConfig.URL(),
 		logger: logger,
-	}, nil
+	}
 }
 
-func (c *Client) GetChannel() (*amqp.Channel, error) {
+// Channel opens a channel, connecting first if there is no live connection.
+func (c *Client) Channel() (*amqp.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || c.conn.IsClosed() {
+		conn, err := amqp.Dial(c.url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+		c.conn = conn
+		c.logger.Info().Msg("RabbitMQ connected successfully")
+	}
+
 	ch, err := c.conn.Channel()
-	    if err != nil {
-        return nil, fmt.Errorf("failed to create channel: %w", err)
-    }
-    return ch, nil
-}
\ No newline at end of file
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel: %w", err)
+	}
+	return ch, nil
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil
+	}
+	if err := c.conn.Close(); err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to close RabbitMQ connection")
+		return err
+	}
+
+	c.logger.Info().Msg("RabbitMQ connection closed")
+	return nil
+}
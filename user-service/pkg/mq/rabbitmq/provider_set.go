@@ -0,0 +1,8 @@
+package rabbitmq
+
+import "github.com/google/wire"
+
+var SetUpProviderSet = wire.NewSet(
+	NewClient,
+	NewPublisher,
+)
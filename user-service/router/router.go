@@ -62,5 +62,6 @@ func registerRoutes(r *gin.Engine, app *di.UserServiceApp) {
 		// protected.GET("/:id", app.Handler.GetUserByID)
 		protected.GET("/:publicID/primary-id", app.Handler.GetUserPrimaryID)
 		protected.GET("/public/:publicID", app.Handler.GetUserByPublicID)
+		protected.DELETE("/:publicID", app.Handler.DeleteUser)
 	}
 }
\ No newline at end of file